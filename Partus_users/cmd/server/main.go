@@ -1,24 +1,47 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"net"
 	"os"
+	// Embute a base de fusos IANA usada para validar e exibir o fuso horário dos usuários.
+	_ "time/tzdata"
 
 	"github.com/jonh-dev/go-logger/logger"
 	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/audit"
 	"github.com/jonh-dev/partus_users/internal/config"
 	"github.com/jonh-dev/partus_users/internal/encryption"
+	"github.com/jonh-dev/partus_users/internal/events"
+	"github.com/jonh-dev/partus_users/internal/handlers"
+	"github.com/jonh-dev/partus_users/internal/i18n"
+	"github.com/jonh-dev/partus_users/internal/mailer"
+	"github.com/jonh-dev/partus_users/internal/migrations"
+	"github.com/jonh-dev/partus_users/internal/passwordpolicy"
 	"github.com/jonh-dev/partus_users/internal/repositories"
+	"github.com/jonh-dev/partus_users/internal/sanitizer"
 	"github.com/jonh-dev/partus_users/internal/services"
+	"github.com/jonh-dev/partus_users/internal/tokens"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 )
 
 func main() {
-	logger.Info("Iniciando o servidor...")
-
 	envGetter := config.NewEnvVarGetter()
 
+	// "server migrate" aplica as migrações pendentes e encerra sem subir o servidor.
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		dbService, err := config.NewDBService(envGetter)
+		if err != nil {
+			logger.Fatal("Falha ao criar o DBService: " + err.Error())
+		}
+		runMigrations(dbService)
+		return
+	}
+
+	logger.Info("Iniciando o servidor...")
+
 	certFile, err := envGetter.Get("SSL_CERT_FILE")
 
 	if err != nil {
@@ -40,25 +63,141 @@ func main() {
 		logger.Fatal("Falha ao setar o TLS: " + err.Error())
 	}
 
-	logger.Info("Criando servidor...")
-	s := grpc.NewServer(grpc.Creds(creds))
-
 	logger.Info("Registrando serviços...")
 	dbService, err := config.NewDBService(envGetter)
 	if err != nil {
 		logger.Fatal("Falha ao criar o DBService: " + err.Error())
 	}
 
-	passwordEncryptor := &encryption.BcryptPasswordEncryptor{}
-	repo := repositories.NewUserRepository(dbService)
-	personalInfoRepo := repositories.NewPersonalInfoRepository(dbService)
-	accountInfoRepo := repositories.NewAccountInfoRepository(dbService)
+	runMigrations(dbService)
+
+	lockoutPolicy, err := config.NewLockoutPolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a política de bloqueio: " + err.Error())
+	}
+
+	passwordPolicy, err := config.NewPasswordPolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a política de senhas: " + err.Error())
+	}
+
+	passwordChecker, err := passwordpolicy.NewCheckerFromPolicy(passwordPolicy)
+	if err != nil {
+		logger.Fatal("Falha ao carregar as listas de senhas recusadas: " + err.Error())
+	}
+
+	passwordHashingPolicy, err := config.NewPasswordHashingPolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a configuração de hash de senhas: " + err.Error())
+	}
+
+	tokenPolicy, err := config.NewTokenPolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a configuração de tokens: " + err.Error())
+	}
+
+	tokenIssuer, err := tokens.LoadJWTIssuer(tokenPolicy)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a chave de assinatura dos tokens: " + err.Error())
+	}
+
+	mfaPolicy, err := config.NewMFAPolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a configuração de MFA: " + err.Error())
+	}
+
+	mfaSecretCipher, err := encryption.NewAESGCMCipher(mfaPolicy.EncryptionKey)
+	if err != nil {
+		logger.Fatal("Falha ao criar a cifra dos segredos de MFA: " + err.Error())
+	}
+
+	emailVerificationPolicy, err := config.NewEmailVerificationPolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a configuração de verificação de e-mail: " + err.Error())
+	}
+
+	passwordResetPolicy, err := config.NewPasswordResetPolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a configuração de redefinição de senha: " + err.Error())
+	}
+
+	smtpConfig, err := config.NewSMTPConfig(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a configuração de SMTP: " + err.Error())
+	}
+
+	var emailSender mailer.Mailer
+	if smtpConfig != nil {
+		emailSender = mailer.NewSMTPMailer(smtpConfig)
+	} else {
+		logger.Info("SMTP_HOST não definido; os e-mails serão apenas registrados no log")
+		emailSender = &mailer.LogMailer{}
+	}
+
+	passwordEncryptor, err := encryption.NewPasswordEncryptor(passwordHashingPolicy)
+	if err != nil {
+		logger.Fatal("Falha ao criar o PasswordEncryptor: " + err.Error())
+	}
+
+	outboxPolicy, err := config.NewOutboxPolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a configuração da outbox: " + err.Error())
+	}
+
+	publisher, err := events.NewPublisher(outboxPolicy)
+	if err != nil {
+		logger.Fatal("Falha ao conectar ao broker de eventos: " + err.Error())
+	}
+	defer publisher.Close()
+
+	// As alterações em users, personal_info e account_info passam pelos repositórios auditados, que
+	// gravam os eventos de domínio na outbox na mesma transação.
+	auditRepo := repositories.NewAuditRepository(dbService)
+	outboxRepo := repositories.NewOutboxRepository(dbService)
+	auditRecorder := audit.NewRecorder(auditRepo, dbService, events.NewOutboxWriter(outboxRepo))
+	repo := audit.NewUserRepository(repositories.NewUserRepository(dbService), auditRecorder)
+	personalInfoRepo := audit.NewPersonalInfoRepository(repositories.NewPersonalInfoRepository(dbService), auditRecorder)
+	accountInfoRepo := audit.NewAccountInfoRepository(repositories.NewAccountInfoRepository(dbService), auditRecorder)
+	sessionRepo := repositories.NewSessionRepository(dbService)
+	mfaRepo := repositories.NewMFARepository(dbService)
+	passwordResetRepo := repositories.NewPasswordResetRepository(dbService)
+
+	sessionService := services.NewSessionService(sessionRepo, repo, tokenIssuer, tokenPolicy)
+	emailVerificationService := services.NewEmailVerificationService(personalInfoRepo, accountInfoRepo, dbService, emailSender, emailVerificationPolicy)
+	personalInfoService := services.NewPersonalInfoService(personalInfoRepo, emailVerificationService)
+	accountInfoService := services.NewAccountInfoService(accountInfoRepo, passwordEncryptor, passwordChecker, lockoutPolicy, passwordPolicy, sessionService, dbService)
+	mfaService := services.NewMFAService(mfaRepo, accountInfoService, mfaSecretCipher, mfaPolicy, tokenIssuer)
+	passwordResetService := services.NewPasswordResetService(personalInfoRepo, accountInfoRepo, passwordResetRepo, passwordEncryptor, passwordChecker, passwordPolicy, sessionService, dbService, emailSender, passwordResetPolicy)
+	service := services.NewUserService(repo, personalInfoService, accountInfoService, dbService, sessionService, mfaService, emailVerificationService, mfaRepo, passwordResetRepo)
+
+	purgePolicy, err := config.NewPurgePolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a política de limpeza de usuários: " + err.Error())
+	}
+	go services.NewUserPurger(service, purgePolicy).Start(audit.WithMetadata(context.Background(), audit.Metadata{Actor: audit.SystemActor, Method: "UserPurger"}))
+
+	suspensionPolicy, err := config.NewSuspensionPolicy(envGetter)
+	if err != nil {
+		logger.Fatal("Falha ao carregar a configuração de suspensões: " + err.Error())
+	}
+	go services.NewSuspensionReactivator(accountInfoService, suspensionPolicy).Start(audit.WithMetadata(context.Background(), audit.Metadata{Actor: audit.SystemActor, Method: "SuspensionReactivator"}))
+
+	if outboxPolicy.Broker == "" {
+		logger.Info("OUTBOX_BROKER não definido; os eventos serão apenas registrados no log e descartados")
+	}
+	go events.NewRelay(outboxRepo, publisher, outboxPolicy).Start(context.Background())
 
-	personalInfoService := services.NewPersonalInfoService(personalInfoRepo)
-	accountInfoService := services.NewAccountInfoService(accountInfoRepo, passwordEncryptor)
-	service := services.NewUserService(repo, personalInfoService, accountInfoService)
+	logger.Info("Criando servidor...")
+	s := grpc.NewServer(grpc.Creds(creds), grpc.ChainUnaryInterceptor(audit.UnaryServerInterceptor(tokenIssuer), sanitizer.UnaryServerInterceptor(), i18n.UnaryServerInterceptor()))
 
 	api.RegisterUserServiceServer(s, service)
+	api.RegisterPersonalInfoServiceServer(s, handlers.NewPersonalInfoHandler(personalInfoService))
+	api.RegisterAccountInfoServiceServer(s, handlers.NewAccountInfoHandler(accountInfoService))
+	api.RegisterSessionServiceServer(s, sessionService)
+	api.RegisterMFAServiceServer(s, mfaService)
+	api.RegisterEmailVerificationServiceServer(s, emailVerificationService)
+	api.RegisterPasswordResetServiceServer(s, passwordResetService)
+	api.RegisterAuditServiceServer(s, services.NewAuditService(auditRepo))
 
 	port := os.Getenv("SERVER_PORT")
 	if port == "" {
@@ -75,3 +214,13 @@ func main() {
 		logger.Fatal("Falha ao inciar o servidor: " + err.Error())
 	}
 }
+
+func runMigrations(dbService *config.DBService) {
+	logger.Info("Verificando migrações do banco de dados...")
+	migrator := migrations.NewMigrator(dbService.Client.Database(dbService.DBName), migrations.All)
+	applied, err := migrator.Migrate(context.Background())
+	if err != nil {
+		logger.Fatal("Falha ao aplicar as migrações: " + err.Error())
+	}
+	logger.Success(fmt.Sprintf("Migrações concluídas: %d aplicada(s)", applied))
+}
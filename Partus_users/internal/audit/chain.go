@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+)
+
+var ErrBrokenChain = errors.New("cadeia de auditoria corrompida")
+
+// chainedEvent é o conteúdo coberto pelo hash de um evento. A ordem dos campos é fixa e occurredAt
+// é formatado em UTC, para que o hash recalculado a partir do banco seja o mesmo.
+type chainedEvent struct {
+	Sequence   int64               `json:"sequence"`
+	Collection string              `json:"collection"`
+	DocumentId string              `json:"documentId"`
+	Operation  string              `json:"operation"`
+	Actor      string              `json:"actor"`
+	Method     string              `json:"method"`
+	RequestId  string              `json:"requestId"`
+	Changes    []model.AuditChange `json:"changes"`
+	OccurredAt string              `json:"occurredAt"`
+	PrevHash   string              `json:"prevHash"`
+}
+
+// ComputeHash calcula o SHA-256 do evento junto com o hash do evento anterior.
+func ComputeHash(event *model.AuditEvent) string {
+	chained := chainedEvent{
+		Sequence:   event.Sequence,
+		Collection: event.Collection,
+		DocumentId: event.DocumentId,
+		Operation:  event.Operation,
+		Actor:      event.Actor,
+		Method:     event.Method,
+		RequestId:  event.RequestId,
+		Changes:    event.Changes,
+		OccurredAt: event.OccurredAt.UTC().Format(time.RFC3339Nano),
+		PrevHash:   event.PrevHash,
+	}
+	// Sem mudanças, o campo é omitido no banco e lido de volta como nil.
+	if len(chained.Changes) == 0 {
+		chained.Changes = nil
+	}
+
+	data, _ := json.Marshal(chained)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyChain confere uma sequência contínua de eventos em ordem crescente de sequence: o hash de
+// cada evento e a ligação com o anterior. O primeiro evento da lista é aceito como ponto de partida.
+func VerifyChain(events []*model.AuditEvent) error {
+	for i, event := range events {
+		if ComputeHash(event) != event.Hash {
+			return fmt.Errorf("%w: o hash do evento %d não confere", ErrBrokenChain, event.Sequence)
+		}
+		if i == 0 {
+			continue
+		}
+
+		previous := events[i-1]
+		if event.Sequence != previous.Sequence+1 {
+			return fmt.Errorf("%w: faltam eventos entre %d e %d", ErrBrokenChain, previous.Sequence, event.Sequence)
+		}
+		if event.PrevHash != previous.Hash {
+			return fmt.Errorf("%w: o evento %d não aponta para o evento %d", ErrBrokenChain, event.Sequence, previous.Sequence)
+		}
+	}
+	return nil
+}
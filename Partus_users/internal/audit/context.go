@@ -0,0 +1,48 @@
+package audit
+
+import "context"
+
+const (
+	// SystemActor identifica as alterações feitas pelo próprio serviço, como as tarefas periódicas.
+	SystemActor = "partus_users"
+	// AnonymousActor identifica as chamadas sem um access token válido, como o login.
+	AnonymousActor = "anonymous"
+)
+
+// Metadata identifica quem fez a alteração e em qual chamada.
+type Metadata struct {
+	Actor     string
+	Method    string
+	RequestId string
+	// Roles são os papéis do access token da chamada; vazio nas chamadas anônimas e nas internas.
+	Roles []string
+}
+
+// HasRole informa se o access token da chamada tem o papel.
+func (m Metadata) HasRole(role string) bool {
+	for _, granted := range m.Roles {
+		if granted == role {
+			return true
+		}
+	}
+	return false
+}
+
+type metadataKey struct{}
+
+func WithMetadata(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, metadataKey{}, metadata)
+}
+
+// MetadataFromContext retorna os metadados gravados por WithMetadata. Sem eles, a alteração é
+// atribuída ao próprio serviço.
+func MetadataFromContext(ctx context.Context) Metadata {
+	metadata, ok := ctx.Value(metadataKey{}).(Metadata)
+	if !ok {
+		return Metadata{Actor: SystemActor}
+	}
+	if metadata.Actor == "" {
+		metadata.Actor = SystemActor
+	}
+	return metadata
+}
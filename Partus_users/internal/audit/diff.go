@@ -0,0 +1,156 @@
+package audit
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/sanitizer"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Diff compara duas versões de um documento e retorna os campos que mudaram, pelo caminho JSON.
+// before nil é uma criação e after nil uma remoção. Campos marcados com (api.sensitive) aparecem
+// apenas como alterados, sem os valores.
+func Diff(before, after proto.Message) []model.AuditChange {
+	beforeMessage, afterMessage := reflectMessage(before), reflectMessage(after)
+	if beforeMessage == nil && afterMessage == nil {
+		return nil
+	}
+	if beforeMessage == nil {
+		beforeMessage = afterMessage.Type().Zero()
+	}
+	if afterMessage == nil {
+		afterMessage = beforeMessage.Type().Zero()
+	}
+
+	changes := []model.AuditChange{}
+	diffMessage("", beforeMessage, afterMessage, &changes)
+	return changes
+}
+
+// reflectMessage retorna nil também para ponteiros nulos de mensagens geradas.
+func reflectMessage(message proto.Message) protoreflect.Message {
+	if message == nil || !message.ProtoReflect().IsValid() {
+		return nil
+	}
+	return message.ProtoReflect()
+}
+
+func diffMessage(prefix string, before, after protoreflect.Message, changes *[]model.AuditChange) {
+	fields := after.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		path := prefix + field.JSONName()
+
+		if isNestedMessage(field) {
+			diffMessage(path+".", before.Get(field).Message(), after.Get(field).Message(), changes)
+			continue
+		}
+
+		beforeValue, afterValue := formatField(before, field), formatField(after, field)
+		if beforeValue == afterValue {
+			continue
+		}
+
+		if sanitizer.IsSensitive(field) {
+			*changes = append(*changes, model.AuditChange{Field: path, Redacted: true})
+			continue
+		}
+		*changes = append(*changes, model.AuditChange{Field: path, Before: beforeValue, After: afterValue})
+	}
+}
+
+// isNestedMessage indica os campos comparados campo a campo. Listas, mapas e tipos conhecidos como
+// Timestamp são comparados como um valor só.
+func isNestedMessage(field protoreflect.FieldDescriptor) bool {
+	if field.Message() == nil || field.IsList() || field.IsMap() {
+		return false
+	}
+	return field.Message().FullName().Parent() != "google.protobuf"
+}
+
+// formatField retorna "" para campos não preenchidos e documentos inexistentes; os demais são
+// formatados como no JSON do proto, com listas e mapas em JSON compacto. Enums são sempre
+// formatados, já que o valor zero, como ACTIVE, também tem significado.
+func formatField(message protoreflect.Message, field protoreflect.FieldDescriptor) string {
+	if !message.IsValid() {
+		return ""
+	}
+	isEnum := field.Kind() == protoreflect.EnumKind && !field.IsList() && !field.IsMap()
+	if !isEnum && !message.Has(field) {
+		return ""
+	}
+	value := message.Get(field)
+
+	switch {
+	case field.IsList():
+		list := value.List()
+		elements := make([]json.RawMessage, 0, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			elements = append(elements, jsonValue(field, list.Get(i)))
+		}
+		data, _ := json.Marshal(elements)
+		return string(data)
+	case field.IsMap():
+		entries := map[string]json.RawMessage{}
+		value.Map().Range(func(key protoreflect.MapKey, entry protoreflect.Value) bool {
+			entries[key.String()] = jsonValue(field.MapValue(), entry)
+			return true
+		})
+		data, _ := json.Marshal(entries)
+		return string(data)
+	default:
+		return formatValue(field, value)
+	}
+}
+
+func formatValue(field protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch field.Kind() {
+	case protoreflect.EnumKind:
+		if enumValue := field.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return fmt.Sprint(value.Enum())
+	case protoreflect.BytesKind:
+		return base64.StdEncoding.EncodeToString(value.Bytes())
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		data := jsonValue(field, value)
+		// Tipos conhecidos como Timestamp são strings no JSON; as aspas não fazem parte do valor.
+		var text string
+		if err := json.Unmarshal(data, &text); err == nil {
+			return text
+		}
+		return string(data)
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
+
+// jsonValue formata um valor como JSON. Mensagens são copiadas e têm os campos sensíveis apagados
+// antes da formatação.
+func jsonValue(field protoreflect.FieldDescriptor, value protoreflect.Value) json.RawMessage {
+	if field.Kind() != protoreflect.MessageKind && field.Kind() != protoreflect.GroupKind {
+		data, _ := json.Marshal(formatValue(field, value))
+		return data
+	}
+
+	message := proto.Clone(value.Message().Interface())
+	sanitizer.Scrub(message)
+	data, err := protojson.Marshal(message)
+	if err != nil {
+		data, _ = json.Marshal(err.Error())
+		return data
+	}
+
+	// A saída do protojson varia os espaços de propósito; compactá-la deixa o texto estável.
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return data
+	}
+	return compact.Bytes()
+}
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestIdMetadataKey é o metadado com o ID da requisição. Quando o cliente não o envia, um ID é
+// gerado e devolvido no cabeçalho da resposta.
+const RequestIdMetadataKey = "x-request-id"
+
+type AccessTokenVerifier interface {
+	VerifyAccessToken(token string) (*tokens.AccessTokenClaims, error)
+}
+
+// UnaryServerInterceptor grava no contexto o autor da chamada, lido do access token, o método gRPC
+// e o ID da requisição, usados nos eventos de auditoria das alterações feitas pela chamada.
+func UnaryServerInterceptor(verifier AccessTokenVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+
+		requestId := firstValue(md, RequestIdMetadataKey)
+		if requestId == "" {
+			requestId = newRequestId()
+		}
+		// Sem um stream de servidor (como nos testes) não há para onde enviar o cabeçalho.
+		_ = grpc.SetHeader(ctx, metadata.Pairs(RequestIdMetadataKey, requestId))
+
+		actor, roles := callerFromToken(verifier, firstValue(md, "authorization"))
+		ctx = WithMetadata(ctx, Metadata{
+			Actor:     actor,
+			Method:    info.FullMethod,
+			RequestId: requestId,
+			Roles:     roles,
+		})
+		return handler(ctx, req)
+	}
+}
+
+// callerFromToken retorna o usuário e os papéis do access token "Bearer <token>", ou AnonymousActor
+// sem papéis se não houver token válido.
+func callerFromToken(verifier AccessTokenVerifier, authorization string) (string, []string) {
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return AnonymousActor, nil
+	}
+
+	claims, err := verifier.VerifyAccessToken(strings.TrimSpace(token))
+	if err != nil || claims.Subject == "" {
+		return AnonymousActor, nil
+	}
+	return claims.Subject, claims.Roles
+}
+
+func firstValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+func newRequestId() string {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(data)
+}
@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+)
+
+// Listener recebe cada evento de auditoria gravado, no mesmo contexto e portanto na mesma
+// transação da alteração. Um erro desfaz a alteração.
+type Listener interface {
+	OnAuditEvent(ctx context.Context, event *model.AuditEvent) error
+}
+
+type Recorder struct {
+	repo      repositories.IAuditRepository
+	txRunner  config.TransactionRunner
+	listeners []Listener
+}
+
+func NewRecorder(repo repositories.IAuditRepository, txRunner config.TransactionRunner, listeners ...Listener) *Recorder {
+	return &Recorder{repo: repo, txRunner: txRunner, listeners: listeners}
+}
+
+// Record acrescenta um evento ao fim da cadeia, com o autor, o método e a requisição do contexto.
+// Atualizações sem mudanças não são registradas. O evento é gravado na transação da alteração.
+//
+// A cadeia é única para todo o serviço: cada evento lê o último e ocupa a sequence seguinte. Duas
+// transações concorrentes disputam a mesma sequence e a segunda recebe um WriteConflict, marcado
+// como TransientTransactionError; o WithTransaction do driver desfaz a alteração inteira e a
+// executa de novo sobre o novo fim da cadeia. Por isso as escritas auditadas, incluindo os logins
+// bem-sucedidos, são serializadas: sob concorrência a vazão fica limitada a uma transação por vez
+// e cada conflito custa uma nova execução da alteração. Quem chama deve devolver o erro do driver
+// sem convertê-lo em status, para que a marcação chegue ao WithTransaction.
+func (r *Recorder) Record(ctx context.Context, collection string, documentId string, operation string, changes []model.AuditChange) error {
+	if operation == model.AuditOperationUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	last, err := r.repo.GetLastAuditEvent(ctx)
+	if err != nil {
+		return err
+	}
+
+	metadata := MetadataFromContext(ctx)
+	event := &model.AuditEvent{
+		Sequence:   1,
+		Collection: collection,
+		DocumentId: documentId,
+		Operation:  operation,
+		Actor:      metadata.Actor,
+		Method:     metadata.Method,
+		RequestId:  metadata.RequestId,
+		Changes:    changes,
+		// O MongoDB guarda milissegundos; truncar antes do hash permite recalculá-lo depois.
+		OccurredAt: time.Now().UTC().Truncate(time.Millisecond),
+	}
+	if last != nil {
+		event.Sequence = last.Sequence + 1
+		event.PrevHash = last.Hash
+	}
+	event.Hash = ComputeHash(event)
+
+	if err := r.repo.InsertAuditEvent(ctx, event); err != nil {
+		return err
+	}
+	return r.notify(ctx, event)
+}
+
+func (r *Recorder) notify(ctx context.Context, event *model.AuditEvent) error {
+	for _, listener := range r.listeners {
+		if err := listener.OnAuditEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// inTransaction executa a alteração e os registros dela em uma única transação, ou na transação
+// já aberta em ctx.
+func (r *Recorder) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return r.txRunner.WithTransaction(ctx, fn)
+}
@@ -0,0 +1,275 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	usersCollection        = "users"
+	personalInfoCollection = "personal_info"
+	accountInfoCollection  = "account_info"
+)
+
+// snapshotFunc lê o estado atual do documento, ou nil se ele não existir.
+type snapshotFunc func(ctx context.Context, id string) (proto.Message, error)
+
+// recordMutation lê o documento antes e depois de mutate e registra a diferença, tudo na mesma
+// transação. mutate retorna false quando nada foi alterado, como em uma atualização condicional que
+// não encontrou o documento.
+func recordMutation(ctx context.Context, recorder *Recorder, collection string, id string, snapshot snapshotFunc, mutate func(ctx context.Context) (bool, error)) (bool, error) {
+	var changed bool
+	err := recorder.inTransaction(ctx, func(ctx context.Context) error {
+		before, err := snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		changed, err = mutate(ctx)
+		if err != nil || !changed {
+			return err
+		}
+
+		after, err := snapshot(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		operation := model.AuditOperationUpdate
+		switch {
+		case before == nil:
+			operation = model.AuditOperationCreate
+		case after == nil:
+			operation = model.AuditOperationDelete
+		}
+		return recorder.Record(ctx, collection, id, operation, Diff(before, after))
+	})
+	return changed, err
+}
+
+// userRepository registra as alterações em users. Os dados pessoais e de conta embutidos são
+// auditados nas próprias coleções; aqui são registradas a criação e as marcações de remoção.
+type userRepository struct {
+	repositories.IUserRepository
+	recorder *Recorder
+}
+
+func NewUserRepository(repo repositories.IUserRepository, recorder *Recorder) repositories.IUserRepository {
+	return &userRepository{IUserRepository: repo, recorder: recorder}
+}
+
+func (r *userRepository) CreateUser(ctx context.Context, user *model.User) (*model.User, error) {
+	var created *model.User
+	err := r.recorder.inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = r.IUserRepository.CreateUser(ctx, user)
+		if err != nil {
+			return err
+		}
+		return r.recorder.Record(ctx, usersCollection, created.Id.Hex(), model.AuditOperationCreate, Diff(nil, created.ToProto()))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *userRepository) SoftDeleteUser(ctx context.Context, id string, deletedAt time.Time) error {
+	return r.recorder.inTransaction(ctx, func(ctx context.Context) error {
+		if err := r.IUserRepository.SoftDeleteUser(ctx, id, deletedAt); err != nil {
+			return err
+		}
+		changes := []model.AuditChange{{Field: "deletedAt", After: deletedAt.UTC().Format(time.RFC3339Nano)}}
+		return r.recorder.Record(ctx, usersCollection, id, model.AuditOperationUpdate, changes)
+	})
+}
+
+func (r *userRepository) RestoreUser(ctx context.Context, id string) error {
+	return r.recorder.inTransaction(ctx, func(ctx context.Context) error {
+		if err := r.IUserRepository.RestoreUser(ctx, id); err != nil {
+			return err
+		}
+		// A data da remoção não é lida antes da restauração; o evento registra apenas que ela foi apagada.
+		changes := []model.AuditChange{{Field: "deletedAt"}}
+		return r.recorder.Record(ctx, usersCollection, id, model.AuditOperationUpdate, changes)
+	})
+}
+
+func (r *userRepository) PurgeUser(ctx context.Context, id string, deletedBefore time.Time) error {
+	return r.recorder.inTransaction(ctx, func(ctx context.Context) error {
+		if err := r.IUserRepository.PurgeUser(ctx, id, deletedBefore); err != nil {
+			return err
+		}
+		return r.recorder.Record(ctx, usersCollection, id, model.AuditOperationDelete, nil)
+	})
+}
+
+type personalInfoRepository struct {
+	repositories.IPersonalInfoRepository
+	recorder *Recorder
+}
+
+func NewPersonalInfoRepository(repo repositories.IPersonalInfoRepository, recorder *Recorder) repositories.IPersonalInfoRepository {
+	return &personalInfoRepository{IPersonalInfoRepository: repo, recorder: recorder}
+}
+
+func (r *personalInfoRepository) snapshot(ctx context.Context, id string) (proto.Message, error) {
+	personalInfo, err := r.IPersonalInfoRepository.GetPersonalInfo(ctx, id)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return personalInfo, nil
+}
+
+func (r *personalInfoRepository) CreatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error) {
+	var created *api.PersonalInfo
+	err := r.recorder.inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = r.IPersonalInfoRepository.CreatePersonalInfo(ctx, personalInfo)
+		if err != nil {
+			return err
+		}
+		return r.recorder.Record(ctx, personalInfoCollection, created.UserId, model.AuditOperationCreate, Diff(nil, created))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *personalInfoRepository) UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo, fields []string) (*api.PersonalInfo, error) {
+	var updated *api.PersonalInfo
+	_, err := recordMutation(ctx, r.recorder, personalInfoCollection, personalInfo.UserId, r.snapshot, func(ctx context.Context) (bool, error) {
+		var err error
+		updated, err = r.IPersonalInfoRepository.UpdatePersonalInfo(ctx, personalInfo, fields)
+		return err == nil, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *personalInfoRepository) MarkEmailVerified(ctx context.Context, id string, email string, verifiedAt time.Time) (bool, error) {
+	return recordMutation(ctx, r.recorder, personalInfoCollection, id, r.snapshot, func(ctx context.Context) (bool, error) {
+		return r.IPersonalInfoRepository.MarkEmailVerified(ctx, id, email, verifiedAt)
+	})
+}
+
+func (r *personalInfoRepository) DeletePersonalInfo(ctx context.Context, id string) error {
+	_, err := recordMutation(ctx, r.recorder, personalInfoCollection, id, r.snapshot, func(ctx context.Context) (bool, error) {
+		err := r.IPersonalInfoRepository.DeletePersonalInfo(ctx, id)
+		return err == nil, err
+	})
+	return err
+}
+
+type accountInfoRepository struct {
+	repositories.IAccountInfoRepository
+	recorder *Recorder
+}
+
+func NewAccountInfoRepository(repo repositories.IAccountInfoRepository, recorder *Recorder) repositories.IAccountInfoRepository {
+	return &accountInfoRepository{IAccountInfoRepository: repo, recorder: recorder}
+}
+
+func (r *accountInfoRepository) snapshot(ctx context.Context, id string) (proto.Message, error) {
+	accountInfo, err := r.IAccountInfoRepository.GetAccountInfo(ctx, id)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return accountInfo, nil
+}
+
+// mutate registra a alteração feita por uma operação que só retorna erro.
+func (r *accountInfoRepository) mutate(ctx context.Context, id string, operation func(ctx context.Context) error) error {
+	_, err := recordMutation(ctx, r.recorder, accountInfoCollection, id, r.snapshot, func(ctx context.Context) (bool, error) {
+		err := operation(ctx)
+		return err == nil, err
+	})
+	return err
+}
+
+func (r *accountInfoRepository) CreateAccountInfo(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error) {
+	var created *api.AccountInfo
+	err := r.recorder.inTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		created, err = r.IAccountInfoRepository.CreateAccountInfo(ctx, accountInfo)
+		if err != nil {
+			return err
+		}
+		return r.recorder.Record(ctx, accountInfoCollection, created.UserId, model.AuditOperationCreate, Diff(nil, created))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (r *accountInfoRepository) UpdateUserCredentials(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error) {
+	var updated *api.AccountInfo
+	err := r.mutate(ctx, accountInfo.UserId, func(ctx context.Context) error {
+		var err error
+		updated, err = r.IAccountInfoRepository.UpdateUserCredentials(ctx, accountInfo)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+func (r *accountInfoRepository) RegisterSuccessfulLogin(ctx context.Context, id string, loginAt time.Time) error {
+	return r.mutate(ctx, id, func(ctx context.Context) error {
+		return r.IAccountInfoRepository.RegisterSuccessfulLogin(ctx, id, loginAt)
+	})
+}
+
+func (r *accountInfoRepository) UpdateFailedLoginState(ctx context.Context, previous *api.AccountInfo, updated *api.AccountInfo) (bool, error) {
+	return recordMutation(ctx, r.recorder, accountInfoCollection, previous.UserId, r.snapshot, func(ctx context.Context) (bool, error) {
+		return r.IAccountInfoRepository.UpdateFailedLoginState(ctx, previous, updated)
+	})
+}
+
+func (r *accountInfoRepository) ResetPassword(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string, historySize int, updatedAt time.Time) (bool, error) {
+	return recordMutation(ctx, r.recorder, accountInfoCollection, id, r.snapshot, func(ctx context.Context) (bool, error) {
+		return r.IAccountInfoRepository.ResetPassword(ctx, id, currentHashedPassword, newHashedPassword, historySize, updatedAt)
+	})
+}
+
+func (r *accountInfoRepository) UpdatePasswordHash(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string) (bool, error) {
+	return recordMutation(ctx, r.recorder, accountInfoCollection, id, r.snapshot, func(ctx context.Context) (bool, error) {
+		return r.IAccountInfoRepository.UpdatePasswordHash(ctx, id, currentHashedPassword, newHashedPassword)
+	})
+}
+
+func (r *accountInfoRepository) ChangePassword(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string, historySize int, updatedAt time.Time) (bool, error) {
+	return recordMutation(ctx, r.recorder, accountInfoCollection, id, r.snapshot, func(ctx context.Context) (bool, error) {
+		return r.IAccountInfoRepository.ChangePassword(ctx, id, currentHashedPassword, newHashedPassword, historySize, updatedAt)
+	})
+}
+
+func (r *accountInfoRepository) ChangeAccountStatus(ctx context.Context, id string, transition *model.StatusTransition) (bool, error) {
+	return recordMutation(ctx, r.recorder, accountInfoCollection, id, r.snapshot, func(ctx context.Context) (bool, error) {
+		return r.IAccountInfoRepository.ChangeAccountStatus(ctx, id, transition)
+	})
+}
+
+func (r *accountInfoRepository) DeleteAccountInfo(ctx context.Context, id string) error {
+	return r.mutate(ctx, id, func(ctx context.Context) error {
+		return r.IAccountInfoRepository.DeleteAccountInfo(ctx, id)
+	})
+}
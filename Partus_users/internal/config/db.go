@@ -2,6 +2,8 @@ package config
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
 	"time"
 
@@ -9,6 +11,16 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+type TransactionRunner interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// CaseInsensitiveCollation compara strings sem diferenciar maiúsculas de minúsculas. Consultas que
+// a utilizam só aproveitam índices criados com a mesma collation.
+func CaseInsensitiveCollation() *options.Collation {
+	return &options.Collation{Locale: "en", Strength: 2}
+}
+
 type DBService struct {
 	Client *mongo.Client
 	DBName string
@@ -62,3 +74,49 @@ func connectToMongoDB(uri string, clientOptions *options.ClientOptions) (*mongo.
 	}
 	return client, err
 }
+
+// WithTransaction executa fn dentro de uma transação multi-documento. O contexto recebido por fn
+// carrega a sessão, então todas as operações dos repositórios feitas com ele participam da
+// transação e são desfeitas se fn retornar erro. Chamadas aninhadas participam da transação já
+// aberta em vez de iniciar outra.
+//
+// Quando uma chamada aninhada falha com TransientTransactionError (por exemplo, um WriteConflict na
+// cadeia de auditoria), o erro é guardado na tentativa em curso. Se fn falhar depois disso, mesmo
+// com o erro convertido em status, a transação inteira é repetida pelo driver.
+func (d *DBService) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		err := fn(ctx)
+		if attempt, ok := ctx.Value(transactionAttemptKey{}).(*transactionAttempt); ok && isTransientTransactionError(err) {
+			attempt.transientErr = err
+		}
+		return err
+	}
+
+	session, err := d.Client.StartSession()
+	if err != nil {
+		return fmt.Errorf("falha ao iniciar sessão no MongoDB: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		attempt := &transactionAttempt{}
+		err := fn(context.WithValue(sessionCtx, transactionAttemptKey{}, attempt))
+		if err != nil && attempt.transientErr != nil {
+			return nil, attempt.transientErr
+		}
+		return nil, err
+	})
+	return err
+}
+
+type transactionAttemptKey struct{}
+
+// transactionAttempt guarda o erro transitório visto pelas chamadas aninhadas de uma tentativa.
+type transactionAttempt struct {
+	transientErr error
+}
+
+func isTransientTransactionError(err error) bool {
+	var labeled mongo.LabeledError
+	return errors.As(err, &labeled) && labeled.HasErrorLabel("TransientTransactionError")
+}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/jonh-dev/go-logger/logger"
@@ -44,3 +46,29 @@ func (e *EnvVarGetter) Get(key string) (string, error) {
 	}
 	return val, nil
 }
+
+func (e *EnvVarGetter) GetInt(key string, defaultValue int) (int, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultValue, nil
+	}
+
+	intVal, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("%s deve ser um número inteiro: %w", key, err)
+	}
+	return intVal, nil
+}
+
+func (e *EnvVarGetter) GetDuration(key string, defaultValue time.Duration) (time.Duration, error) {
+	val := os.Getenv(key)
+	if val == "" {
+		return defaultValue, nil
+	}
+
+	duration, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("%s deve ser uma duração válida (ex: 15m, 1h): %w", key, err)
+	}
+	return duration, nil
+}
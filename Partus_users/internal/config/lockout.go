@@ -0,0 +1,65 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type LockoutPolicy struct {
+	MaxFailedAttempts int32
+	Window            time.Duration
+	BaseLockDuration  time.Duration
+	MaxLockDuration   time.Duration
+}
+
+func DefaultLockoutPolicy() *LockoutPolicy {
+	return &LockoutPolicy{
+		MaxFailedAttempts: 5,
+		Window:            15 * time.Minute,
+		BaseLockDuration:  5 * time.Minute,
+		MaxLockDuration:   24 * time.Hour,
+	}
+}
+
+func NewLockoutPolicy(envGetter *EnvVarGetter) (*LockoutPolicy, error) {
+	policy := DefaultLockoutPolicy()
+
+	maxFailedAttempts, err := envGetter.GetInt("LOCKOUT_MAX_FAILED_ATTEMPTS", int(policy.MaxFailedAttempts))
+	if err != nil {
+		return nil, err
+	}
+	if maxFailedAttempts < 1 {
+		return nil, fmt.Errorf("LOCKOUT_MAX_FAILED_ATTEMPTS deve ser maior que zero")
+	}
+	policy.MaxFailedAttempts = int32(maxFailedAttempts)
+
+	if policy.Window, err = envGetter.GetDuration("LOCKOUT_WINDOW", policy.Window); err != nil {
+		return nil, err
+	}
+
+	if policy.BaseLockDuration, err = envGetter.GetDuration("LOCKOUT_BASE_DURATION", policy.BaseLockDuration); err != nil {
+		return nil, err
+	}
+
+	if policy.MaxLockDuration, err = envGetter.GetDuration("LOCKOUT_MAX_DURATION", policy.MaxLockDuration); err != nil {
+		return nil, err
+	}
+
+	if policy.BaseLockDuration > policy.MaxLockDuration {
+		return nil, fmt.Errorf("LOCKOUT_BASE_DURATION não pode ser maior que LOCKOUT_MAX_DURATION")
+	}
+
+	return policy, nil
+}
+
+// LockDuration dobra a duração do bloqueio a cada tentativa que excede o limite, até MaxLockDuration.
+func (p *LockoutPolicy) LockDuration(failedAttempts int32) time.Duration {
+	duration := p.BaseLockDuration
+	for i := p.MaxFailedAttempts; i < failedAttempts; i++ {
+		duration *= 2
+		if duration >= p.MaxLockDuration {
+			return p.MaxLockDuration
+		}
+	}
+	return duration
+}
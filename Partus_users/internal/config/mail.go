@@ -0,0 +1,79 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewSMTPConfig retorna nil quando SMTP_HOST não está definido; nesse caso os e-mails não são enviados.
+func NewSMTPConfig(envGetter *EnvVarGetter) (*SMTPConfig, error) {
+	host, err := envGetter.Get("SMTP_HOST")
+	if err != nil {
+		return nil, nil
+	}
+
+	from, err := envGetter.Get("SMTP_FROM")
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := envGetter.Get("SMTP_PORT")
+	if err != nil {
+		port = "587"
+	}
+
+	username, _ := envGetter.Get("SMTP_USERNAME")
+	password, _ := envGetter.Get("SMTP_PASSWORD")
+
+	return &SMTPConfig{
+		Host:     host,
+		Port:     port,
+		Username: username,
+		Password: password,
+		From:     from,
+	}, nil
+}
+
+type EmailVerificationPolicy struct {
+	SigningKey []byte
+	TokenTTL   time.Duration
+	// VerificationURL recebe o token no parâmetro "token" do link enviado por e-mail.
+	VerificationURL string
+}
+
+func NewEmailVerificationPolicy(envGetter *EnvVarGetter) (*EmailVerificationPolicy, error) {
+	signingKey, err := envGetter.Get("EMAIL_VERIFICATION_KEY")
+	if err != nil {
+		return nil, err
+	}
+	if len(signingKey) < 32 {
+		return nil, fmt.Errorf("EMAIL_VERIFICATION_KEY deve ter pelo menos 32 caracteres")
+	}
+
+	tokenTTL, err := envGetter.GetDuration("EMAIL_VERIFICATION_TTL", 48*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if tokenTTL <= 0 {
+		return nil, fmt.Errorf("EMAIL_VERIFICATION_TTL deve ser maior que zero")
+	}
+
+	verificationURL, err := envGetter.Get("EMAIL_VERIFICATION_URL")
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailVerificationPolicy{
+		SigningKey:      []byte(signingKey),
+		TokenTTL:        tokenTTL,
+		VerificationURL: verificationURL,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+type MFAPolicy struct {
+	// EncryptionKey cifra os segredos TOTP armazenados (AES-256-GCM).
+	EncryptionKey        []byte
+	Issuer               string
+	ChallengeTTL         time.Duration
+	MaxChallengeAttempts int32
+}
+
+func NewMFAPolicy(envGetter *EnvVarGetter) (*MFAPolicy, error) {
+	encodedKey, err := envGetter.Get("MFA_ENCRYPTION_KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("MFA_ENCRYPTION_KEY deve ser uma chave de 32 bytes em base64")
+	}
+
+	issuer, err := envGetter.Get("MFA_ISSUER")
+	if err != nil {
+		issuer = "Partus"
+	}
+
+	challengeTTL, err := envGetter.GetDuration("MFA_CHALLENGE_TTL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAttempts, err := envGetter.GetInt("MFA_MAX_CHALLENGE_ATTEMPTS", 5)
+	if err != nil {
+		return nil, err
+	}
+
+	if challengeTTL <= 0 || maxAttempts < 1 {
+		return nil, fmt.Errorf("MFA_CHALLENGE_TTL e MFA_MAX_CHALLENGE_ATTEMPTS devem ser maiores que zero")
+	}
+
+	return &MFAPolicy{
+		EncryptionKey:        key,
+		Issuer:               issuer,
+		ChallengeTTL:         challengeTTL,
+		MaxChallengeAttempts: int32(maxAttempts),
+	}, nil
+}
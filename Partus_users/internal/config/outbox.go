@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	OutboxBrokerNATS  = "nats"
+	OutboxBrokerKafka = "kafka"
+)
+
+type OutboxPolicy struct {
+	// Broker é "nats", "kafka" ou vazio; vazio apenas registra os eventos no log.
+	Broker       string
+	NATSURL      string
+	KafkaBrokers []string
+	// Topic é o subject do NATS ou o tópico do Kafka em que todos os eventos são publicados.
+	Topic string
+	// Source é o atributo source dos CloudEvents publicados.
+	Source         string
+	PollInterval   time.Duration
+	BatchSize      int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// LeaseTimeout é por quanto tempo um evento reservado para envio fica invisível aos outros
+	// relays. Se o envio não terminar nesse prazo, o evento volta a ser entregue.
+	LeaseTimeout time.Duration
+	// Retention é por quanto tempo os eventos publicados são mantidos na coleção outbox.
+	Retention time.Duration
+}
+
+func DefaultOutboxPolicy() *OutboxPolicy {
+	return &OutboxPolicy{
+		Topic:          "partus.users.events",
+		Source:         "/partus_users",
+		PollInterval:   time.Second,
+		BatchSize:      100,
+		InitialBackoff: time.Second,
+		MaxBackoff:     5 * time.Minute,
+		LeaseTimeout:   30 * time.Second,
+		Retention:      7 * 24 * time.Hour,
+	}
+}
+
+func NewOutboxPolicy(envGetter *EnvVarGetter) (*OutboxPolicy, error) {
+	policy := DefaultOutboxPolicy()
+
+	policy.Broker, _ = envGetter.Get("OUTBOX_BROKER")
+	switch policy.Broker {
+	case "":
+		// Sem broker os eventos só vão para o log e são marcados como publicados, então isso só é
+		// aceito em desenvolvimento.
+		if envGetter.env != "development" {
+			return nil, fmt.Errorf("OUTBOX_BROKER é obrigatório no ambiente %s (use nats ou kafka)", envGetter.env)
+		}
+	case OutboxBrokerNATS:
+		natsURL, err := envGetter.Get("NATS_URL")
+		if err != nil {
+			return nil, err
+		}
+		policy.NATSURL = natsURL
+	case OutboxBrokerKafka:
+		kafkaBrokers, err := envGetter.Get("KAFKA_BROKERS")
+		if err != nil {
+			return nil, err
+		}
+		for _, broker := range strings.Split(kafkaBrokers, ",") {
+			if broker = strings.TrimSpace(broker); broker != "" {
+				policy.KafkaBrokers = append(policy.KafkaBrokers, broker)
+			}
+		}
+		if len(policy.KafkaBrokers) == 0 {
+			return nil, fmt.Errorf("KAFKA_BROKERS deve ter pelo menos um endereço")
+		}
+	default:
+		return nil, fmt.Errorf("OUTBOX_BROKER inválido: %s (use nats ou kafka)", policy.Broker)
+	}
+
+	if topic, err := envGetter.Get("OUTBOX_TOPIC"); err == nil {
+		policy.Topic = topic
+	}
+	if source, err := envGetter.Get("OUTBOX_SOURCE"); err == nil {
+		policy.Source = source
+	}
+
+	var err error
+	if policy.PollInterval, err = envGetter.GetDuration("OUTBOX_POLL_INTERVAL", policy.PollInterval); err != nil {
+		return nil, err
+	}
+	if policy.PollInterval <= 0 {
+		return nil, fmt.Errorf("OUTBOX_POLL_INTERVAL deve ser maior que zero")
+	}
+
+	if policy.BatchSize, err = envGetter.GetInt("OUTBOX_BATCH_SIZE", policy.BatchSize); err != nil {
+		return nil, err
+	}
+	if policy.BatchSize < 1 {
+		return nil, fmt.Errorf("OUTBOX_BATCH_SIZE deve ser maior que zero")
+	}
+
+	if policy.InitialBackoff, err = envGetter.GetDuration("OUTBOX_RETRY_INITIAL_BACKOFF", policy.InitialBackoff); err != nil {
+		return nil, err
+	}
+	if policy.MaxBackoff, err = envGetter.GetDuration("OUTBOX_RETRY_MAX_BACKOFF", policy.MaxBackoff); err != nil {
+		return nil, err
+	}
+	if policy.InitialBackoff <= 0 || policy.InitialBackoff > policy.MaxBackoff {
+		return nil, fmt.Errorf("OUTBOX_RETRY_INITIAL_BACKOFF deve ser maior que zero e não pode ser maior que OUTBOX_RETRY_MAX_BACKOFF")
+	}
+
+	if policy.LeaseTimeout, err = envGetter.GetDuration("OUTBOX_LEASE_TIMEOUT", policy.LeaseTimeout); err != nil {
+		return nil, err
+	}
+	if policy.LeaseTimeout <= 0 {
+		return nil, fmt.Errorf("OUTBOX_LEASE_TIMEOUT deve ser maior que zero")
+	}
+
+	if policy.Retention, err = envGetter.GetDuration("OUTBOX_RETENTION", policy.Retention); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// RetryDelay dobra o intervalo até a próxima tentativa a cada falha, até MaxBackoff.
+func (p *OutboxPolicy) RetryDelay(failedAttempts int32) time.Duration {
+	delay := p.InitialBackoff
+	for i := int32(1); i < failedAttempts; i++ {
+		delay *= 2
+		if delay >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,69 @@
+package config
+
+import "fmt"
+
+// PasswordHashingPolicy escolhe o algoritmo usado nos hashes novos. Hashes gravados com outro
+// algoritmo ou com outros parâmetros continuam válidos e são regravados no próximo login.
+type PasswordHashingPolicy struct {
+	Algorithm         string
+	BcryptCost        int
+	Argon2Memory      int
+	Argon2Iterations  int
+	Argon2Parallelism int
+	ScryptLogN        int
+	ScryptR           int
+	ScryptP           int
+}
+
+func DefaultPasswordHashingPolicy() *PasswordHashingPolicy {
+	return &PasswordHashingPolicy{
+		Algorithm:         "argon2id",
+		BcryptCost:        10,
+		Argon2Memory:      64 * 1024,
+		Argon2Iterations:  3,
+		Argon2Parallelism: 4,
+		ScryptLogN:        15,
+		ScryptR:           8,
+		ScryptP:           1,
+	}
+}
+
+func NewPasswordHashingPolicy(envGetter *EnvVarGetter) (*PasswordHashingPolicy, error) {
+	policy := DefaultPasswordHashingPolicy()
+
+	if algorithm, err := envGetter.Get("PASSWORD_HASH_ALGORITHM"); err == nil && algorithm != "" {
+		policy.Algorithm = algorithm
+	}
+	switch policy.Algorithm {
+	case "bcrypt", "argon2id", "scrypt":
+	default:
+		return nil, fmt.Errorf("PASSWORD_HASH_ALGORITHM deve ser bcrypt, argon2id ou scrypt")
+	}
+
+	intSettings := []struct {
+		key   string
+		value *int
+		min   int
+		max   int
+	}{
+		{"BCRYPT_COST", &policy.BcryptCost, 4, 31},
+		{"ARGON2_MEMORY_KIB", &policy.Argon2Memory, 8, 4 * 1024 * 1024},
+		{"ARGON2_ITERATIONS", &policy.Argon2Iterations, 1, 100},
+		{"ARGON2_PARALLELISM", &policy.Argon2Parallelism, 1, 255},
+		{"SCRYPT_LOG_N", &policy.ScryptLogN, 10, 30},
+		{"SCRYPT_R", &policy.ScryptR, 1, 64},
+		{"SCRYPT_P", &policy.ScryptP, 1, 64},
+	}
+	for _, setting := range intSettings {
+		value, err := envGetter.GetInt(setting.key, *setting.value)
+		if err != nil {
+			return nil, err
+		}
+		if value < setting.min || value > setting.max {
+			return nil, fmt.Errorf("%s deve estar entre %d e %d", setting.key, setting.min, setting.max)
+		}
+		*setting.value = value
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import "fmt"
+
+type PasswordPolicy struct {
+	// HistorySize é quantas senhas anteriores, além da atual, não podem ser reutilizadas.
+	HistorySize int
+	// BreachedCorpusDir aponta para os arquivos de senhas vazadas no formato de faixas do Have I
+	// Been Pwned. Vazio desativa a verificação.
+	BreachedCorpusDir string
+	// CommonPasswordsFile substitui a lista embutida de senhas comuns, uma por linha, da mais
+	// usada para a menos usada. Só as CommonPasswordsLimit primeiras são consideradas.
+	CommonPasswordsFile  string
+	CommonPasswordsLimit int
+	// MinStrengthScore vai de 0 (muito fraca) a 4 (muito forte).
+	MinStrengthScore int
+}
+
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		HistorySize:          5,
+		CommonPasswordsLimit: 10000,
+		MinStrengthScore:     3,
+	}
+}
+
+func NewPasswordPolicy(envGetter *EnvVarGetter) (*PasswordPolicy, error) {
+	policy := DefaultPasswordPolicy()
+
+	historySize, err := envGetter.GetInt("PASSWORD_HISTORY_SIZE", policy.HistorySize)
+	if err != nil {
+		return nil, err
+	}
+	if historySize < 0 {
+		return nil, fmt.Errorf("PASSWORD_HISTORY_SIZE não pode ser negativo")
+	}
+	policy.HistorySize = historySize
+
+	// As duas variáveis de arquivo são opcionais.
+	policy.BreachedCorpusDir, _ = envGetter.Get("PASSWORD_BREACHED_CORPUS_DIR")
+	policy.CommonPasswordsFile, _ = envGetter.Get("PASSWORD_COMMON_LIST_FILE")
+
+	commonPasswordsLimit, err := envGetter.GetInt("PASSWORD_COMMON_LIST_SIZE", policy.CommonPasswordsLimit)
+	if err != nil {
+		return nil, err
+	}
+	if commonPasswordsLimit < 1 {
+		return nil, fmt.Errorf("PASSWORD_COMMON_LIST_SIZE deve ser maior que zero")
+	}
+	policy.CommonPasswordsLimit = commonPasswordsLimit
+
+	minStrengthScore, err := envGetter.GetInt("PASSWORD_MIN_STRENGTH_SCORE", policy.MinStrengthScore)
+	if err != nil {
+		return nil, err
+	}
+	if minStrengthScore < 0 || minStrengthScore > 4 {
+		return nil, fmt.Errorf("PASSWORD_MIN_STRENGTH_SCORE deve estar entre 0 e 4")
+	}
+	policy.MinStrengthScore = minStrengthScore
+
+	return policy, nil
+}
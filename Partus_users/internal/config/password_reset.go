@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type PasswordResetPolicy struct {
+	TokenTTL time.Duration
+	// ResetURL recebe o token no parâmetro "token" do link enviado por e-mail.
+	ResetURL string
+}
+
+func NewPasswordResetPolicy(envGetter *EnvVarGetter) (*PasswordResetPolicy, error) {
+	tokenTTL, err := envGetter.GetDuration("PASSWORD_RESET_TTL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if tokenTTL <= 0 {
+		return nil, fmt.Errorf("PASSWORD_RESET_TTL deve ser maior que zero")
+	}
+
+	resetURL, err := envGetter.Get("PASSWORD_RESET_URL")
+	if err != nil {
+		return nil, err
+	}
+
+	return &PasswordResetPolicy{
+		TokenTTL: tokenTTL,
+		ResetURL: resetURL,
+	}, nil
+}
@@ -0,0 +1,32 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type PurgePolicy struct {
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+func NewPurgePolicy(envGetter *EnvVarGetter) (*PurgePolicy, error) {
+	retention, err := envGetter.GetDuration("USER_PURGE_RETENTION", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	// Sem retenção positiva o usuário seria removido de vez logo após a exclusão lógica.
+	if retention <= 0 {
+		return nil, fmt.Errorf("USER_PURGE_RETENTION deve ser maior que zero")
+	}
+
+	interval, err := envGetter.GetDuration("USER_PURGE_INTERVAL", time.Hour)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		return nil, fmt.Errorf("USER_PURGE_INTERVAL deve ser maior que zero")
+	}
+
+	return &PurgePolicy{Retention: retention, Interval: interval}, nil
+}
@@ -0,0 +1,24 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type SuspensionPolicy struct {
+	// CheckInterval é o intervalo entre as buscas por suspensões temporárias encerradas.
+	CheckInterval time.Duration
+}
+
+func NewSuspensionPolicy(envGetter *EnvVarGetter) (*SuspensionPolicy, error) {
+	interval, err := envGetter.GetDuration("SUSPENSION_CHECK_INTERVAL", 5*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	if interval <= 0 {
+		return nil, fmt.Errorf("SUSPENSION_CHECK_INTERVAL deve ser maior que zero")
+	}
+
+	return &SuspensionPolicy{CheckInterval: interval}, nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+type TokenPolicy struct {
+	PrivateKeyFile  string
+	Issuer          string
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+func NewTokenPolicy(envGetter *EnvVarGetter) (*TokenPolicy, error) {
+	privateKeyFile, err := envGetter.Get("JWT_PRIVATE_KEY_FILE")
+	if err != nil {
+		return nil, err
+	}
+
+	issuer, err := envGetter.Get("JWT_ISSUER")
+	if err != nil {
+		issuer = "partus_users"
+	}
+
+	accessTokenTTL, err := envGetter.GetDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshTokenTTL, err := envGetter.GetDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour)
+	if err != nil {
+		return nil, err
+	}
+
+	if accessTokenTTL <= 0 || refreshTokenTTL <= 0 {
+		return nil, fmt.Errorf("JWT_ACCESS_TOKEN_TTL e REFRESH_TOKEN_TTL devem ser maiores que zero")
+	}
+
+	return &TokenPolicy{
+		PrivateKeyFile:  privateKeyFile,
+		Issuer:          issuer,
+		AccessTokenTTL:  accessTokenTTL,
+		RefreshTokenTTL: refreshTokenTTL,
+	}, nil
+}
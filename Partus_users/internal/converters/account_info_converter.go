@@ -5,7 +5,9 @@ import (
 
 	"github.com/jonh-dev/partus_users/api"
 	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/proto"
 )
 
 func ToModelAccountInfo(objectId primitive.ObjectID, accountInfo *api.AccountInfo) (*model.AccountInfo, error) {
@@ -13,7 +15,7 @@ func ToModelAccountInfo(objectId primitive.ObjectID, accountInfo *api.AccountInf
 		return nil, errors.New("accountInfo não pode ser nil")
 	}
 
-	return &model.AccountInfo{
+	modelAccountInfo := &model.AccountInfo{
 		UserId:                objectId,
 		Username:              accountInfo.Username,
 		Password:              accountInfo.Password,
@@ -27,5 +29,30 @@ func ToModelAccountInfo(objectId primitive.ObjectID, accountInfo *api.AccountInf
 		LastFailedLoginReason: accountInfo.LastFailedLoginReason,
 		AccountLockedUntil:    accountInfo.AccountLockedUntil.AsTime(),
 		AccountLockedReason:   accountInfo.AccountLockedReason,
-	}, nil
+		SuspendedUntil:        utils.TimestampToTime(accountInfo.SuspendedUntil),
+	}
+	for _, transition := range accountInfo.StatusHistory {
+		modelAccountInfo.StatusHistory = append(modelAccountInfo.StatusHistory, model.StatusTransition{
+			From:           model.AccountStatus(transition.From),
+			To:             model.AccountStatus(transition.To),
+			Reason:         transition.Reason,
+			Actor:          transition.Actor,
+			ChangedAt:      utils.TimestampToTime(transition.ChangedAt),
+			SuspendedUntil: utils.TimestampToTime(transition.SuspendedUntil),
+		})
+	}
+
+	return modelAccountInfo, nil
+}
+
+// ToAccountInfoResponse devolve uma cópia do AccountInfo sem os segredos, para os serviços que
+// trabalham com o api.AccountInfo retornado pelo repositório.
+func ToAccountInfoResponse(accountInfo *api.AccountInfo) *api.AccountInfo {
+	if accountInfo == nil {
+		return nil
+	}
+
+	response := proto.Clone(accountInfo).(*api.AccountInfo)
+	response.Password = ""
+	return response
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/jonh-dev/partus_users/api"
 	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/utils"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -14,12 +15,14 @@ func ToModelPersonalInfo(objectId primitive.ObjectID, personalInfo *api.Personal
 	}
 
 	return &model.PersonalInfo{
-		UserId:       objectId,
-		FirstName:    personalInfo.FirstName,
-		LastName:     personalInfo.LastName,
-		Email:        personalInfo.Email,
-		BirthDate:    personalInfo.BirthDate.AsTime(),
-		Phone:        personalInfo.Phone,
-		ProfileImage: personalInfo.ProfileImage,
+		UserId:          objectId,
+		FirstName:       personalInfo.FirstName,
+		LastName:        personalInfo.LastName,
+		Email:           personalInfo.Email,
+		BirthDate:       personalInfo.BirthDate.AsTime(),
+		Phone:           personalInfo.Phone,
+		ProfileImage:    personalInfo.ProfileImage,
+		TimeZone:        personalInfo.TimeZone,
+		EmailVerifiedAt: utils.TimestampToTime(personalInfo.EmailVerifiedAt),
 	}, nil
 }
@@ -1,17 +1,100 @@
 package encryption
 
-import "golang.org/x/crypto/bcrypt"
+import (
+	"fmt"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+)
 
 type PasswordEncryptor interface {
 	EncryptPassword(password string) (string, error)
+	VerifyPassword(hashedPassword string, password string) (bool, error)
+	// NeedsRehash indica que o hash deve ser regravado com o algoritmo e os parâmetros atuais,
+	// o que só pode ser feito quando a senha em texto puro está disponível, como no login.
+	NeedsRehash(hashedPassword string) bool
+}
+
+// HasherRegistry gera hashes com o algoritmo atual e verifica hashes de qualquer algoritmo
+// registrado, identificado pelo prefixo do hash.
+type HasherRegistry struct {
+	current PasswordHasher
+	hashers map[string]PasswordHasher
+}
+
+func NewHasherRegistry(current PasswordHasher, others ...PasswordHasher) *HasherRegistry {
+	hashers := map[string]PasswordHasher{current.Id(): current}
+	for _, hasher := range others {
+		if _, exists := hashers[hasher.Id()]; !exists {
+			hashers[hasher.Id()] = hasher
+		}
+	}
+	return &HasherRegistry{current: current, hashers: hashers}
+}
+
+// NewPasswordEncryptor registra os três algoritmos, para que hashes de qualquer um deles possam ser
+// verificados, e usa o algoritmo da política para os hashes novos.
+func NewPasswordEncryptor(policy *config.PasswordHashingPolicy) (*HasherRegistry, error) {
+	hashers := map[string]PasswordHasher{
+		BcryptId: &BcryptHasher{Cost: policy.BcryptCost},
+		Argon2idId: &Argon2idHasher{
+			Memory:      uint32(policy.Argon2Memory),
+			Iterations:  uint32(policy.Argon2Iterations),
+			Parallelism: uint8(policy.Argon2Parallelism),
+			SaltLength:  16,
+			KeyLength:   32,
+		},
+		ScryptId: &ScryptHasher{
+			LogN:       policy.ScryptLogN,
+			R:          policy.ScryptR,
+			P:          policy.ScryptP,
+			SaltLength: 16,
+			KeyLength:  32,
+		},
+	}
+
+	current, exists := hashers[policy.Algorithm]
+	if !exists {
+		return nil, fmt.Errorf("algoritmo de hash de senha desconhecido: %s", policy.Algorithm)
+	}
+
+	others := make([]PasswordHasher, 0, len(hashers)-1)
+	for id, hasher := range hashers {
+		if id != policy.Algorithm {
+			others = append(others, hasher)
+		}
+	}
+	return NewHasherRegistry(current, others...), nil
 }
 
-type BcryptPasswordEncryptor struct{}
+func (r *HasherRegistry) EncryptPassword(password string) (string, error) {
+	return r.current.Hash(password)
+}
 
-func (b *BcryptPasswordEncryptor) EncryptPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+func (r *HasherRegistry) VerifyPassword(hashedPassword string, password string) (bool, error) {
+	hasher, err := r.hasherFor(hashedPassword)
 	if err != nil {
-		return "", err
+		return false, err
+	}
+	return hasher.Verify(hashedPassword, password)
+}
+
+func (r *HasherRegistry) NeedsRehash(hashedPassword string) bool {
+	hasher, err := r.hasherFor(hashedPassword)
+	if err != nil || hasher.Id() != r.current.Id() {
+		return true
+	}
+	return hasher.NeedsRehash(hashedPassword)
+}
+
+func (r *HasherRegistry) hasherFor(hashedPassword string) (PasswordHasher, error) {
+	id := phcId(hashedPassword)
+	if isBcryptHash(hashedPassword) {
+		id = BcryptId
+	}
+
+	hasher, exists := r.hashers[id]
+	if !exists {
+		return nil, fmt.Errorf("%w: algoritmo %q não registrado", ErrInvalidPasswordHash, id)
 	}
-	return string(hashedPassword), nil
+	return hasher, nil
 }
@@ -0,0 +1,247 @@
+package encryption
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PasswordHasher é um algoritmo de hash de senha. Hash e Verify trabalham com o hash codificado,
+// que carrega o algoritmo e os parâmetros usados, para que hashes antigos continuem verificáveis
+// depois de uma mudança de configuração.
+type PasswordHasher interface {
+	Id() string
+	Hash(password string) (string, error)
+	Verify(encoded string, password string) (bool, error)
+	// NeedsRehash indica que o hash foi gerado com parâmetros diferentes dos configurados.
+	NeedsRehash(encoded string) bool
+}
+
+const (
+	BcryptId   = "bcrypt"
+	Argon2idId = "argon2id"
+	ScryptId   = "scrypt"
+)
+
+// BcryptHasher mantém o formato próprio do bcrypt ($2a$, $2b$, $2y$), usado pelos hashes
+// gravados antes do formato PHC.
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h *BcryptHasher) Id() string {
+	return BcryptId
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hashedPassword), nil
+}
+
+func (h *BcryptHasher) Verify(encoded string, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (h *BcryptHasher) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost != h.Cost
+}
+
+func isBcryptHash(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// Argon2idHasher gera hashes no formato $argon2id$v=19$m=<KiB>,t=<iterações>,p=<paralelismo>$salt$hash.
+type Argon2idHasher struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+func (h *Argon2idHasher) Id() string {
+	return Argon2idId
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt, err := newSalt(h.SaltLength)
+	if err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+	encoded := &phcHash{
+		id:      Argon2idId,
+		version: argon2.Version,
+		params: []phcParam{
+			{name: "m", value: int(h.Memory)},
+			{name: "t", value: int(h.Iterations)},
+			{name: "p", value: int(h.Parallelism)},
+		},
+		salt: salt,
+		hash: key,
+	}
+	return encoded.String(), nil
+}
+
+func (h *Argon2idHasher) Verify(encoded string, password string) (bool, error) {
+	parsed, memory, iterations, parallelism, err := parseArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(password), parsed.salt, iterations, memory, parallelism, uint32(len(parsed.hash)))
+	return subtle.ConstantTimeCompare(key, parsed.hash) == 1, nil
+}
+
+func (h *Argon2idHasher) NeedsRehash(encoded string) bool {
+	parsed, memory, iterations, parallelism, err := parseArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+	return memory != h.Memory || iterations != h.Iterations || parallelism != h.Parallelism ||
+		uint32(len(parsed.salt)) != h.SaltLength || uint32(len(parsed.hash)) != h.KeyLength
+}
+
+func parseArgon2id(encoded string) (*phcHash, uint32, uint32, uint8, error) {
+	parsed, err := parsePHC(encoded)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	if parsed.id != Argon2idId {
+		return nil, 0, 0, 0, fmt.Errorf("%w: esperado %s, recebido %s", ErrInvalidPasswordHash, Argon2idId, parsed.id)
+	}
+	if parsed.version != argon2.Version {
+		return nil, 0, 0, 0, fmt.Errorf("%w: versão %d do argon2 não suportada", ErrInvalidPasswordHash, parsed.version)
+	}
+
+	memory, err := parsed.param("m")
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	iterations, err := parsed.param("t")
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	parallelism, err := parsed.param("p")
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	if memory < 1 || iterations < 1 || parallelism < 1 || parallelism > 255 {
+		return nil, 0, 0, 0, fmt.Errorf("%w: parâmetros do argon2 fora do intervalo", ErrInvalidPasswordHash)
+	}
+
+	return parsed, uint32(memory), uint32(iterations), uint8(parallelism), nil
+}
+
+// Scrypt ainda não tem um identificador PHC padronizado; o formato segue o da biblioteca passlib:
+// $scrypt$ln=<log2(N)>,r=<r>,p=<p>$salt$hash.
+type ScryptHasher struct {
+	LogN       int
+	R          int
+	P          int
+	SaltLength uint32
+	KeyLength  int
+}
+
+func (h *ScryptHasher) Id() string {
+	return ScryptId
+}
+
+func (h *ScryptHasher) Hash(password string) (string, error) {
+	salt, err := newSalt(h.SaltLength)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := scrypt.Key([]byte(password), salt, 1<<h.LogN, h.R, h.P, h.KeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := &phcHash{
+		id: ScryptId,
+		params: []phcParam{
+			{name: "ln", value: h.LogN},
+			{name: "r", value: h.R},
+			{name: "p", value: h.P},
+		},
+		salt: salt,
+		hash: key,
+	}
+	return encoded.String(), nil
+}
+
+func (h *ScryptHasher) Verify(encoded string, password string) (bool, error) {
+	parsed, logN, r, p, err := parseScrypt(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	key, err := scrypt.Key([]byte(password), parsed.salt, 1<<logN, r, p, len(parsed.hash))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(key, parsed.hash) == 1, nil
+}
+
+func (h *ScryptHasher) NeedsRehash(encoded string) bool {
+	parsed, logN, r, p, err := parseScrypt(encoded)
+	if err != nil {
+		return true
+	}
+	return logN != h.LogN || r != h.R || p != h.P ||
+		uint32(len(parsed.salt)) != h.SaltLength || len(parsed.hash) != h.KeyLength
+}
+
+func parseScrypt(encoded string) (*phcHash, int, int, int, error) {
+	parsed, err := parsePHC(encoded)
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	if parsed.id != ScryptId {
+		return nil, 0, 0, 0, fmt.Errorf("%w: esperado %s, recebido %s", ErrInvalidPasswordHash, ScryptId, parsed.id)
+	}
+
+	logN, err := parsed.param("ln")
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	r, err := parsed.param("r")
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	p, err := parsed.param("p")
+	if err != nil {
+		return nil, 0, 0, 0, err
+	}
+	if logN < 1 || logN > 31 {
+		return nil, 0, 0, 0, fmt.Errorf("%w: ln fora do intervalo", ErrInvalidPasswordHash)
+	}
+
+	return parsed, logN, r, p, nil
+}
+
+func newSalt(length uint32) ([]byte, error) {
+	salt := make([]byte, length)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("falha ao gerar o salt: %w", err)
+	}
+	return salt, nil
+}
@@ -0,0 +1,104 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidPasswordHash = errors.New("hash de senha em formato inválido")
+
+// phcHash representa um hash no formato PHC: $<id>[$v=<versão>]$<parâmetros>$<salt>$<hash>, com
+// salt e hash em base64 sem padding.
+type phcHash struct {
+	id      string
+	version int
+	params  []phcParam
+	salt    []byte
+	hash    []byte
+}
+
+type phcParam struct {
+	name  string
+	value int
+}
+
+func (h *phcHash) String() string {
+	var builder strings.Builder
+	builder.WriteString("$" + h.id)
+	if h.version != 0 {
+		builder.WriteString("$v=" + strconv.Itoa(h.version))
+	}
+
+	params := make([]string, len(h.params))
+	for i, param := range h.params {
+		params[i] = param.name + "=" + strconv.Itoa(param.value)
+	}
+	builder.WriteString("$" + strings.Join(params, ","))
+	builder.WriteString("$" + base64.RawStdEncoding.EncodeToString(h.salt))
+	builder.WriteString("$" + base64.RawStdEncoding.EncodeToString(h.hash))
+	return builder.String()
+}
+
+// param retorna o valor do parâmetro ou erro se ele não estiver presente.
+func (h *phcHash) param(name string) (int, error) {
+	for _, param := range h.params {
+		if param.name == name {
+			return param.value, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: parâmetro %s ausente", ErrInvalidPasswordHash, name)
+}
+
+func phcId(encoded string) string {
+	if !strings.HasPrefix(encoded, "$") {
+		return ""
+	}
+	id, _, _ := strings.Cut(encoded[1:], "$")
+	return id
+}
+
+func parsePHC(encoded string) (*phcHash, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) < 5 || parts[0] != "" {
+		return nil, ErrInvalidPasswordHash
+	}
+
+	h := &phcHash{id: parts[1]}
+	fields := parts[2:]
+	if strings.HasPrefix(fields[0], "v=") {
+		version, err := strconv.Atoi(strings.TrimPrefix(fields[0], "v="))
+		if err != nil {
+			return nil, fmt.Errorf("%w: versão inválida", ErrInvalidPasswordHash)
+		}
+		h.version = version
+		fields = fields[1:]
+	}
+	if len(fields) != 3 {
+		return nil, ErrInvalidPasswordHash
+	}
+
+	for _, pair := range strings.Split(fields[0], ",") {
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("%w: parâmetro %q inválido", ErrInvalidPasswordHash, pair)
+		}
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: parâmetro %q inválido", ErrInvalidPasswordHash, pair)
+		}
+		h.params = append(h.params, phcParam{name: name, value: number})
+	}
+
+	var err error
+	if h.salt, err = base64.RawStdEncoding.DecodeString(fields[1]); err != nil {
+		return nil, fmt.Errorf("%w: salt inválido", ErrInvalidPasswordHash)
+	}
+	if h.hash, err = base64.RawStdEncoding.DecodeString(fields[2]); err != nil {
+		return nil, fmt.Errorf("%w: hash inválido", ErrInvalidPasswordHash)
+	}
+
+	return h, nil
+}
@@ -0,0 +1,38 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+)
+
+// CloudEventsContentType identifica o modo estruturado do CloudEvents: o evento inteiro, com os
+// atributos e os dados, vai no corpo da mensagem.
+const CloudEventsContentType = "application/cloudevents+json"
+
+// CloudEvent é um evento no formato JSON do CloudEvents 1.0. Id é o ID do evento na outbox, que
+// se repete nas novas tentativas e permite aos consumidores descartar entregas duplicadas.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	Id              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+func NewCloudEvent(event *model.OutboxEvent, source string) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		Id:              event.Id.Hex(),
+		Source:          source,
+		Type:            event.Type,
+		Subject:         event.Subject,
+		Time:            event.CreatedAt.UTC(),
+		DataContentType: "application/json",
+		Data:            json.RawMessage(event.Data),
+	}
+}
@@ -0,0 +1,140 @@
+package events
+
+import (
+	"strings"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+)
+
+const (
+	TypeUserCreated   = "partus.users.UserCreated"
+	TypeUserUpdated   = "partus.users.UserUpdated"
+	TypeUserDeleted   = "partus.users.UserDeleted"
+	TypeAccountLocked = "partus.users.AccountLocked"
+	TypeStatusChanged = "partus.users.StatusChanged"
+)
+
+type UserCreatedData struct {
+	UserId        string `json:"userId"`
+	Email         string `json:"email,omitempty"`
+	Username      string `json:"username,omitempty"`
+	AccountStatus string `json:"accountStatus,omitempty"`
+}
+
+// UserUpdatedData traz apenas os nomes dos campos alterados; quem precisar dos valores os consulta
+// no serviço, para que os dados pessoais não se espalhem pelos brokers.
+type UserUpdatedData struct {
+	UserId string   `json:"userId"`
+	Fields []string `json:"fields"`
+}
+
+type UserDeletedData struct {
+	UserId    string `json:"userId"`
+	DeletedAt string `json:"deletedAt"`
+}
+
+type AccountLockedData struct {
+	UserId      string `json:"userId"`
+	LockedUntil string `json:"lockedUntil"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+type StatusChangedData struct {
+	UserId string `json:"userId"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type DomainEvent struct {
+	Type string
+	Data interface{}
+}
+
+// accountBookkeepingFields são os campos de account_info que não interessam aos outros serviços,
+// como os contadores de login, ou que já são publicados como AccountLocked e StatusChanged.
+var accountBookkeepingFields = map[string]bool{
+	"updatedAt":             true,
+	"lastLogin":             true,
+	"failedLoginAttempts":   true,
+	"lastFailedLogin":       true,
+	"lastFailedLoginReason": true,
+	"accountLockedUntil":    true,
+	"accountLockedReason":   true,
+	"accountStatus":         true,
+	"statusReason":          true,
+	"statusHistory":         true,
+	"suspendedUntil":        true,
+}
+
+// DomainEvents traduz um evento de auditoria nos eventos de domínio publicados para os outros
+// serviços. A criação e a remoção de personal_info e account_info fazem parte da criação e da
+// remoção do usuário e não geram eventos próprios; a remoção definitiva também não, já que
+// UserDeleted é publicado quando o usuário é removido.
+func DomainEvents(auditEvent *model.AuditEvent) []DomainEvent {
+	changes := map[string]model.AuditChange{}
+	for _, change := range auditEvent.Changes {
+		changes[change.Field] = change
+	}
+	userId := auditEvent.DocumentId
+
+	switch {
+	case auditEvent.Collection == "users" && auditEvent.Operation == model.AuditOperationCreate:
+		return []DomainEvent{{Type: TypeUserCreated, Data: UserCreatedData{
+			UserId:        userId,
+			Email:         changes["personalInfo.email"].After,
+			Username:      changes["accountInfo.username"].After,
+			AccountStatus: changes["accountInfo.accountStatus"].After,
+		}}}
+
+	case auditEvent.Collection == "users" && auditEvent.Operation == model.AuditOperationUpdate:
+		if deletedAt := changes["deletedAt"]; deletedAt.After != "" {
+			return []DomainEvent{{Type: TypeUserDeleted, Data: UserDeletedData{UserId: userId, DeletedAt: deletedAt.After}}}
+		}
+		return userUpdated(userId, auditEvent.Changes, nil)
+
+	case auditEvent.Collection == "personal_info" && auditEvent.Operation == model.AuditOperationUpdate:
+		return userUpdated(userId, auditEvent.Changes, nil)
+
+	case auditEvent.Collection == "account_info" && auditEvent.Operation == model.AuditOperationUpdate:
+		var domainEvents []DomainEvent
+		if status, ok := changes["accountStatus"]; ok {
+			domainEvents = append(domainEvents, DomainEvent{Type: TypeStatusChanged, Data: StatusChangedData{
+				UserId: userId,
+				From:   status.Before,
+				To:     status.After,
+				Reason: changes["statusReason"].After,
+			}})
+		}
+		if lockedUntil, ok := changes["accountLockedUntil"]; ok && lockedUntil.After != "" {
+			domainEvents = append(domainEvents, DomainEvent{Type: TypeAccountLocked, Data: AccountLockedData{
+				UserId:      userId,
+				LockedUntil: lockedUntil.After,
+				Reason:      changes["accountLockedReason"].After,
+			}})
+		}
+		return append(domainEvents, userUpdated(userId, auditEvent.Changes, accountBookkeepingFields)...)
+	}
+
+	return nil
+}
+
+// userUpdated retorna UserUpdated com os campos alterados de primeiro nível, ou nada se todos
+// estiverem em ignored.
+func userUpdated(userId string, changes []model.AuditChange, ignored map[string]bool) []DomainEvent {
+	fields := []string{}
+	seen := map[string]bool{}
+	for _, change := range changes {
+		field, _, _ := strings.Cut(change.Field, ".")
+		if ignored[field] || seen[field] {
+			continue
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return []DomainEvent{{Type: TypeUserUpdated, Data: UserUpdatedData{UserId: userId, Fields: fields}}}
+}
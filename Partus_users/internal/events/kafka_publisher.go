@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher usa o subject do evento, o ID do usuário, como chave da mensagem: os eventos de um
+// mesmo usuário caem na mesma partição. Cada escrita espera a confirmação de todas as réplicas.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(brokers...),
+			Topic:        topic,
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+		},
+	}
+}
+
+func (p *KafkaPublisher) Publish(ctx context.Context, event CloudEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar o evento: %w", err)
+	}
+
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Key:     []byte(event.Subject),
+		Value:   data,
+		Headers: []kafka.Header{{Key: "content-type", Value: []byte(CloudEventsContentType)}},
+	})
+	if err != nil {
+		return fmt.Errorf("falha ao publicar o evento no Kafka: %w", err)
+	}
+	return nil
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
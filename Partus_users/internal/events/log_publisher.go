@@ -0,0 +1,20 @@
+package events
+
+import (
+	"context"
+	"log"
+)
+
+// LogPublisher registra no log o tipo e o ID dos eventos em vez de publicá-los; os dados ficam de
+// fora porque podem conter dados pessoais. É usado quando OUTBOX_BROKER não está definido, o que
+// só é aceito em desenvolvimento.
+type LogPublisher struct{}
+
+func (p *LogPublisher) Publish(ctx context.Context, event CloudEvent) error {
+	log.Printf("Evento não publicado (OUTBOX_BROKER não configurado): %s (%s)", event.Type, event.Id)
+	return nil
+}
+
+func (p *LogPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,45 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryPublisher guarda os eventos em memória em vez de publicá-los.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []CloudEvent
+	err    error
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event CloudEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.err != nil {
+		return p.err
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *MemoryPublisher) Close() error {
+	return nil
+}
+
+// FailWith faz as próximas publicações falharem com err; nil volta a aceitar os eventos.
+func (p *MemoryPublisher) FailWith(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.err = err
+}
+
+func (p *MemoryPublisher) Events() []CloudEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]CloudEvent(nil), p.events...)
+}
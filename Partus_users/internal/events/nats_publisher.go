@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher publica pelo JetStream, que confirma o armazenamento de cada mensagem. O subject
+// precisa pertencer a um stream já criado. O ID do evento vai no cabeçalho Nats-Msg-Id, então o
+// JetStream descarta as reentregas dentro da janela de deduplicação do stream.
+type NATSPublisher struct {
+	conn      *nats.Conn
+	jetStream nats.JetStreamContext
+	subject   string
+}
+
+func NewNATSPublisher(url string, subject string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("partus_users"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao conectar ao NATS: %w", err)
+	}
+
+	jetStream, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("falha ao abrir o contexto do JetStream: %w", err)
+	}
+
+	return &NATSPublisher{conn: conn, jetStream: jetStream, subject: subject}, nil
+}
+
+func (p *NATSPublisher) Publish(ctx context.Context, event CloudEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar o evento: %w", err)
+	}
+
+	msg := nats.NewMsg(p.subject)
+	msg.Header.Set("Content-Type", CloudEventsContentType)
+	msg.Header.Set(nats.MsgIdHdr, event.Id)
+	msg.Data = data
+
+	if _, err := p.jetStream.PublishMsg(msg, nats.Context(ctx)); err != nil {
+		return fmt.Errorf("falha ao publicar o evento no NATS: %w", err)
+	}
+	return nil
+}
+
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
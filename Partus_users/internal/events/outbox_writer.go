@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+)
+
+// OutboxWriter grava na outbox os eventos de domínio de cada alteração auditada. Como recebe o
+// contexto da alteração, os eventos são gravados na mesma transação e só existem se ela for
+// confirmada.
+type OutboxWriter struct {
+	outboxRepo repositories.IOutboxRepository
+}
+
+func NewOutboxWriter(outboxRepo repositories.IOutboxRepository) *OutboxWriter {
+	return &OutboxWriter{outboxRepo: outboxRepo}
+}
+
+func (w *OutboxWriter) OnAuditEvent(ctx context.Context, auditEvent *model.AuditEvent) error {
+	for _, domainEvent := range DomainEvents(auditEvent) {
+		data, err := json.Marshal(domainEvent.Data)
+		if err != nil {
+			return fmt.Errorf("falha ao serializar o evento %s: %w", domainEvent.Type, err)
+		}
+
+		event := &model.OutboxEvent{
+			Type:          domainEvent.Type,
+			Subject:       auditEvent.DocumentId,
+			Data:          string(data),
+			CreatedAt:     auditEvent.OccurredAt,
+			NextAttemptAt: auditEvent.OccurredAt,
+		}
+		if err := w.outboxRepo.InsertOutboxEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
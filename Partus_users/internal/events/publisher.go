@@ -0,0 +1,28 @@
+package events
+
+import (
+	"context"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+)
+
+// Publisher entrega os eventos aos outros serviços. A implementação é escolhida na inicialização
+// por OUTBOX_BROKER: NATSPublisher, KafkaPublisher ou LogPublisher quando nenhum broker está
+// configurado, o que só é aceito em desenvolvimento; MemoryPublisher é usado nos testes. Publish
+// só deve retornar nil depois que o broker confirmar o recebimento, já que o evento não é enviado
+// de novo.
+type Publisher interface {
+	Publish(ctx context.Context, event CloudEvent) error
+	Close() error
+}
+
+func NewPublisher(policy *config.OutboxPolicy) (Publisher, error) {
+	switch policy.Broker {
+	case config.OutboxBrokerNATS:
+		return NewNATSPublisher(policy.NATSURL, policy.Topic)
+	case config.OutboxBrokerKafka:
+		return NewKafkaPublisher(policy.KafkaBrokers, policy.Topic), nil
+	default:
+		return &LogPublisher{}, nil
+	}
+}
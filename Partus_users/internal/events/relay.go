@@ -0,0 +1,86 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/go-logger/logger"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+)
+
+// Relay publica os eventos pendentes da outbox. A entrega é at-least-once: um evento só é marcado
+// como publicado depois da confirmação do broker, e uma falha entre a publicação e a marcação faz
+// com que ele seja publicado de novo. A ordem entre eventos só é garantida enquanto não há falhas.
+type Relay struct {
+	outboxRepo repositories.IOutboxRepository
+	publisher  Publisher
+	policy     *config.OutboxPolicy
+}
+
+func NewRelay(outboxRepo repositories.IOutboxRepository, publisher Publisher, policy *config.OutboxPolicy) *Relay {
+	return &Relay{outboxRepo: outboxRepo, publisher: publisher, policy: policy}
+}
+
+// Start publica os eventos pendentes imediatamente e depois a cada policy.PollInterval, até ctx ser
+// cancelado.
+func (r *Relay) Start(ctx context.Context) {
+	logger.Info(fmt.Sprintf("Publicação dos eventos da outbox iniciada: intervalo de %s", r.policy.PollInterval))
+
+	ticker := time.NewTicker(r.policy.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		r.RelayOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Publicação dos eventos da outbox encerrada")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayOnce publica até policy.BatchSize eventos e retorna quantos foram publicados. A primeira
+// falha encerra o lote, já que o broker provavelmente está indisponível; o evento que falhou só
+// volta a ser tentado depois do intervalo de policy.RetryDelay.
+func (r *Relay) RelayOnce(ctx context.Context) int {
+	published := 0
+	for published < r.policy.BatchSize {
+		now := time.Now()
+		event, err := r.outboxRepo.ClaimNextOutboxEvent(ctx, now, now.Add(r.policy.LeaseTimeout))
+		if err != nil {
+			logger.Error("Erro ao buscar os eventos pendentes da outbox: " + err.Error())
+			break
+		}
+		if event == nil {
+			break
+		}
+
+		if err := r.publisher.Publish(ctx, NewCloudEvent(event, r.policy.Source)); err != nil {
+			attempts := event.Attempts + 1
+			nextAttemptAt := time.Now().Add(r.policy.RetryDelay(attempts))
+			logger.Error(fmt.Sprintf("Erro ao publicar o evento %s (%s), tentativa %d: %s", event.Id.Hex(), event.Type, attempts, err.Error()))
+
+			if err := r.outboxRepo.MarkOutboxEventFailed(ctx, event.Id, attempts, nextAttemptAt, err.Error()); err != nil {
+				logger.Error("Erro ao registrar a falha de publicação: " + err.Error())
+			}
+			break
+		}
+
+		publishedAt := time.Now()
+		if err := r.outboxRepo.MarkOutboxEventPublished(ctx, event.Id, publishedAt, publishedAt.Add(r.policy.Retention)); err != nil {
+			// O evento será publicado de novo quando a reserva expirar.
+			logger.Error("Erro ao marcar o evento " + event.Id.Hex() + " como publicado: " + err.Error())
+			break
+		}
+		published++
+	}
+
+	if published > 0 {
+		logger.Info(fmt.Sprintf("%d evento(s) da outbox publicado(s)", published))
+	}
+	return published
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/jonh-dev/go-error/errors"
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/converters"
+	"github.com/jonh-dev/partus_users/internal/services"
+	"google.golang.org/grpc/codes"
+)
+
+type AccountInfoHandler struct {
+	accountInfoService services.IAccountInfoService
+}
+
+func NewAccountInfoHandler(accountInfoService services.IAccountInfoService) *AccountInfoHandler {
+	return &AccountInfoHandler{accountInfoService: accountInfoService}
+}
+
+func (h *AccountInfoHandler) CreateAccountInfo(ctx context.Context, req *api.CreateAccountInfoRequest) (*api.AccountInfoResponse, error) {
+	if req.AccountInfo == nil {
+		return nil, errors.New(codes.InvalidArgument, "accountInfo não pode ser nil")
+	}
+
+	accountInfo, err := h.accountInfoService.CreateAccountInfo(ctx, req.AccountInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AccountInfoResponse{
+		AccountInfo: converters.ToAccountInfoResponse(accountInfo),
+		Message:     "AccountInfo criado com sucesso",
+	}, nil
+}
+
+func (h *AccountInfoHandler) GetAccountInfo(ctx context.Context, req *api.GetAccountInfoRequest) (*api.AccountInfoResponse, error) {
+	if req.UserId == "" {
+		return nil, errors.New(codes.InvalidArgument, "userId é obrigatório")
+	}
+
+	accountInfo, err := h.accountInfoService.GetAccountInfo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AccountInfoResponse{
+		AccountInfo: converters.ToAccountInfoResponse(accountInfo),
+		Message:     "AccountInfo obtido com sucesso",
+	}, nil
+}
+
+func (h *AccountInfoHandler) UpdateAccountInfo(ctx context.Context, req *api.UpdateAccountInfoRequest) (*api.AccountInfoResponse, error) {
+	if req.AccountInfo == nil {
+		return nil, errors.New(codes.InvalidArgument, "accountInfo não pode ser nil")
+	}
+
+	accountInfo, err := h.accountInfoService.UpdateUserCredentials(ctx, req.AccountInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AccountInfoResponse{
+		AccountInfo: converters.ToAccountInfoResponse(accountInfo),
+		Message:     "AccountInfo atualizado com sucesso",
+	}, nil
+}
+
+func (h *AccountInfoHandler) ChangeAccountStatus(ctx context.Context, req *api.ChangeAccountStatusRequest) (*api.AccountInfoResponse, error) {
+	accountInfo, err := h.accountInfoService.ChangeAccountStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AccountInfoResponse{
+		AccountInfo: converters.ToAccountInfoResponse(accountInfo),
+		Message:     "Status da conta alterado com sucesso",
+	}, nil
+}
+
+func (h *AccountInfoHandler) ChangePassword(ctx context.Context, req *api.ChangePasswordRequest) (*api.ChangePasswordResponse, error) {
+	if req.UserId == "" {
+		return nil, errors.New(codes.InvalidArgument, "userId é obrigatório")
+	}
+
+	err := h.accountInfoService.ChangePassword(ctx, req.UserId, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ChangePasswordResponse{
+		Message: "Senha alterada com sucesso",
+	}, nil
+}
+
+func (h *AccountInfoHandler) DeleteAccountInfo(ctx context.Context, req *api.DeleteAccountInfoRequest) (*api.AccountInfoResponse, error) {
+	if req.UserId == "" {
+		return nil, errors.New(codes.InvalidArgument, "userId é obrigatório")
+	}
+
+	err := h.accountInfoService.DeleteAccountInfo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.AccountInfoResponse{
+		Message: "AccountInfo removido com sucesso",
+	}, nil
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/jonh-dev/go-error/errors"
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/services"
+	"google.golang.org/grpc/codes"
+)
+
+type PersonalInfoHandler struct {
+	personalInfoService services.IPersonalInfoService
+}
+
+func NewPersonalInfoHandler(personalInfoService services.IPersonalInfoService) *PersonalInfoHandler {
+	return &PersonalInfoHandler{personalInfoService: personalInfoService}
+}
+
+func (h *PersonalInfoHandler) CreatePersonalInfo(ctx context.Context, req *api.CreatePersonalInfoRequest) (*api.PersonalInfoResponse, error) {
+	if req.PersonalInfo == nil {
+		return nil, errors.New(codes.InvalidArgument, "personalInfo não pode ser nil")
+	}
+
+	personalInfo, err := h.personalInfoService.CreatePersonalInfo(ctx, req.PersonalInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.PersonalInfoResponse{
+		PersonalInfo: personalInfo,
+		Message:      "PersonalInfo criado com sucesso",
+	}, nil
+}
+
+func (h *PersonalInfoHandler) GetPersonalInfo(ctx context.Context, req *api.GetPersonalInfoRequest) (*api.PersonalInfoResponse, error) {
+	if req.UserId == "" {
+		return nil, errors.New(codes.InvalidArgument, "userId é obrigatório")
+	}
+
+	personalInfo, err := h.personalInfoService.GetPersonalInfo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.PersonalInfoResponse{
+		PersonalInfo: personalInfo,
+		Message:      "PersonalInfo obtido com sucesso",
+	}, nil
+}
+
+func (h *PersonalInfoHandler) UpdatePersonalInfo(ctx context.Context, req *api.UpdatePersonalInfoRequest) (*api.PersonalInfoResponse, error) {
+	if req.PersonalInfo == nil {
+		return nil, errors.New(codes.InvalidArgument, "personalInfo não pode ser nil")
+	}
+
+	personalInfo, err := h.personalInfoService.UpdatePersonalInfo(ctx, req.PersonalInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.PersonalInfoResponse{
+		PersonalInfo: personalInfo,
+		Message:      "PersonalInfo atualizado com sucesso",
+	}, nil
+}
+
+func (h *PersonalInfoHandler) DeletePersonalInfo(ctx context.Context, req *api.DeletePersonalInfoRequest) (*api.PersonalInfoResponse, error) {
+	if req.UserId == "" {
+		return nil, errors.New(codes.InvalidArgument, "userId é obrigatório")
+	}
+
+	err := h.personalInfoService.DeletePersonalInfo(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.PersonalInfoResponse{
+		Message: "PersonalInfo removido com sucesso",
+	}, nil
+}
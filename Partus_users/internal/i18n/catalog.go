@@ -0,0 +1,187 @@
+package i18n
+
+import "google.golang.org/grpc/codes"
+
+// catalog guarda as mensagens de cada código de erro por idioma. Os códigos são os motivos dos
+// ErrorInfo anexados pelos serviços (ver validation.Reason* e passwordpolicy.Code*).
+var catalog = map[string]map[string]string{
+	"INVALID_USERNAME": {
+		Portuguese: "O nome de usuário deve começar e terminar com um caractere alfanumérico, pode conter letras, números, pontos, hifens e sublinhados, não pode conter caracteres especiais consecutivos, e deve ter entre 3 e 20 caracteres",
+		English:    "The username must start and end with an alphanumeric character, may contain letters, numbers, dots, hyphens and underscores, cannot contain consecutive special characters, and must be between 3 and 20 characters long",
+		Spanish:    "El nombre de usuario debe empezar y terminar con un carácter alfanumérico, puede contener letras, números, puntos, guiones y guiones bajos, no puede contener caracteres especiales consecutivos y debe tener entre 3 y 20 caracteres",
+	},
+	"INVALID_PASSWORD": {
+		Portuguese: "A senha deve ter entre 8 e 64 caracteres, conter pelo menos uma letra maiúscula, uma letra minúscula, um número e um caractere especial",
+		English:    "The password must be between 8 and 64 characters long and contain at least one uppercase letter, one lowercase letter, one number and one special character",
+		Spanish:    "La contraseña debe tener entre 8 y 64 caracteres y contener al menos una letra mayúscula, una letra minúscula, un número y un carácter especial",
+	},
+	"INVALID_ACCOUNT_STATUS": {
+		Portuguese: "O status da conta deve ser ACTIVE, INACTIVE, PENDING ou SUSPENDED",
+		English:    "The account status must be ACTIVE, INACTIVE, PENDING or SUSPENDED",
+		Spanish:    "El estado de la cuenta debe ser ACTIVE, INACTIVE, PENDING o SUSPENDED",
+	},
+	"MISSING_STATUS_REASON": {
+		Portuguese: "A razão do status é obrigatória quando o status da conta não é ACTIVE",
+		English:    "A status reason is required when the account status is not ACTIVE",
+		Spanish:    "El motivo del estado es obligatorio cuando el estado de la cuenta no es ACTIVE",
+	},
+	"IMMUTABLE_FIELD": {
+		Portuguese: "Este campo não pode ser alterado",
+		English:    "This field cannot be changed",
+		Spanish:    "Este campo no se puede modificar",
+	},
+	"FUTURE_TIMESTAMP": {
+		Portuguese: "A data não pode estar no futuro",
+		English:    "The date cannot be in the future",
+		Spanish:    "La fecha no puede estar en el futuro",
+	},
+	"NEGATIVE_VALUE": {
+		Portuguese: "O valor não pode ser negativo",
+		English:    "The value cannot be negative",
+		Spanish:    "El valor no puede ser negativo",
+	},
+	"MISSING_FAILED_LOGIN_REASON": {
+		Portuguese: "A razão da última tentativa de login falhada é obrigatória",
+		English:    "The reason for the last failed login attempt is required",
+		Spanish:    "El motivo del último intento de inicio de sesión fallido es obligatorio",
+	},
+	"INVALID_FIRST_NAME": {
+		Portuguese: "O primeiro nome deve começar com uma letra maiúscula, conter apenas uma palavra e ter no máximo 20 caracteres",
+		English:    "The first name must start with an uppercase letter, contain a single word and be at most 20 characters long",
+		Spanish:    "El nombre debe empezar con una letra mayúscula, contener una sola palabra y tener como máximo 20 caracteres",
+	},
+	"INVALID_LAST_NAME": {
+		Portuguese: "O sobrenome deve começar com uma letra maiúscula em cada palavra e ter no máximo 50 caracteres",
+		English:    "Each word of the last name must start with an uppercase letter, and the last name must be at most 50 characters long",
+		Spanish:    "Cada palabra del apellido debe empezar con una letra mayúscula y el apellido debe tener como máximo 50 caracteres",
+	},
+	"INVALID_EMAIL": {
+		Portuguese: "O e-mail deve ser um endereço de e-mail válido",
+		English:    "The email must be a valid email address",
+		Spanish:    "El correo electrónico debe ser una dirección válida",
+	},
+	"INVALID_BIRTH_DATE": {
+		Portuguese: "A data de nascimento deve estar no passado, o usuário deve ter pelo menos 13 anos e o ano deve ser entre 1900 e o ano atual",
+		English:    "The birth date must be in the past, the user must be at least 13 years old and the year must be between 1900 and the current year",
+		Spanish:    "La fecha de nacimiento debe estar en el pasado, el usuario debe tener al menos 13 años y el año debe estar entre 1900 y el año actual",
+	},
+	"INVALID_PHONE": {
+		Portuguese: "O telefone deve estar no formato correto, com DDD válido e, opcionalmente, o código do país",
+		English:    "The phone number must be in the correct format, with a valid area code and, optionally, the country code",
+		Spanish:    "El teléfono debe tener el formato correcto, con un código de área válido y, opcionalmente, el código del país",
+	},
+	"INVALID_PROFILE_IMAGE": {
+		Portuguese: "A imagem do perfil deve ser um URL válido",
+		English:    "The profile image must be a valid URL",
+		Spanish:    "La imagen de perfil debe ser una URL válida",
+	},
+	"INVALID_TIME_ZONE": {
+		Portuguese: "O fuso horário deve ser um identificador IANA válido, como America/Sao_Paulo",
+		English:    "The time zone must be a valid IANA identifier, such as America/Sao_Paulo",
+		Spanish:    "La zona horaria debe ser un identificador IANA válido, como America/Sao_Paulo",
+	},
+	"FIELD_NOT_UPDATABLE": {
+		Portuguese: "Este campo não pode ser atualizado",
+		English:    "This field cannot be updated",
+		Spanish:    "Este campo no se puede actualizar",
+	},
+	"PASSWORD_REUSED": {
+		Portuguese: "A nova senha não pode ser igual à senha atual nem às senhas usadas recentemente",
+		English:    "The new password cannot match the current password or any recently used password",
+		Spanish:    "La nueva contraseña no puede coincidir con la contraseña actual ni con las usadas recientemente",
+	},
+	"MISSING_TRANSITION_REASON": {
+		Portuguese: "A razão da mudança de status é obrigatória",
+		English:    "A reason for the status change is required",
+		Spanish:    "El motivo del cambio de estado es obligatorio",
+	},
+	"MISSING_ACTOR": {
+		Portuguese: "O responsável pela mudança de status é obrigatório",
+		English:    "The actor responsible for the status change is required",
+		Spanish:    "El responsable del cambio de estado es obligatorio",
+	},
+	"INVALID_SUSPENSION_END": {
+		Portuguese: "O fim da suspensão só pode ser informado para SUSPENDED e deve estar no futuro",
+		English:    "The suspension end can only be set for SUSPENDED and must be in the future",
+		Spanish:    "El fin de la suspensión solo puede indicarse para SUSPENDED y debe estar en el futuro",
+	},
+	"COMMON_PASSWORD": {
+		Portuguese: "Esta senha está entre as mais usadas; escolha outra",
+		English:    "This password is among the most commonly used; choose another one",
+		Spanish:    "Esta contraseña está entre las más usadas; elige otra",
+	},
+	"BREACHED_PASSWORD": {
+		Portuguese: "Esta senha apareceu em vazamentos de dados conhecidos; escolha outra",
+		English:    "This password has appeared in known data breaches; choose another one",
+		Spanish:    "Esta contraseña ha aparecido en filtraciones de datos conocidas; elige otra",
+	},
+	"WEAK_PASSWORD": {
+		Portuguese: "A senha é fácil de adivinhar; use uma senha mais longa, sem palavras comuns, sequências ou dados pessoais",
+		English:    "The password is easy to guess; use a longer password without common words, sequences or personal data",
+		Spanish:    "La contraseña es fácil de adivinar; usa una contraseña más larga, sin palabras comunes, secuencias ni datos personales",
+	},
+}
+
+// codeMessages são usadas quando o erro não tem um código do catálogo e o idioma pedido não é o
+// das mensagens do código.
+var codeMessages = map[codes.Code]map[string]string{
+	codes.InvalidArgument: {
+		English: "The request contains invalid data",
+		Spanish: "La solicitud contiene datos no válidos",
+	},
+	codes.NotFound: {
+		English: "The requested resource was not found",
+		Spanish: "No se encontró el recurso solicitado",
+	},
+	codes.AlreadyExists: {
+		English: "The resource already exists",
+		Spanish: "El recurso ya existe",
+	},
+	codes.Unauthenticated: {
+		English: "Authentication failed",
+		Spanish: "La autenticación falló",
+	},
+	codes.PermissionDenied: {
+		English: "You do not have permission to perform this operation",
+		Spanish: "No tienes permiso para realizar esta operación",
+	},
+	codes.FailedPrecondition: {
+		English: "The operation cannot be performed in the current state",
+		Spanish: "La operación no se puede realizar en el estado actual",
+	},
+	codes.ResourceExhausted: {
+		English: "Too many attempts; try again later",
+		Spanish: "Demasiados intentos; inténtalo de nuevo más tarde",
+	},
+	codes.Aborted: {
+		English: "The operation conflicted with another one; try again",
+		Spanish: "La operación entró en conflicto con otra; inténtalo de nuevo",
+	},
+	codes.Unavailable: {
+		English: "The service is temporarily unavailable",
+		Spanish: "El servicio no está disponible temporalmente",
+	},
+}
+
+var internalErrorMessages = map[string]string{
+	English: "An internal error occurred",
+	Spanish: "Se produjo un error interno",
+}
+
+// Message retorna a mensagem do código no idioma pedido.
+func Message(locale string, code string) (string, bool) {
+	messages, exists := catalog[code]
+	if !exists {
+		return "", false
+	}
+	message, exists := messages[locale]
+	return message, exists
+}
+
+// codeMessage retorna a mensagem genérica do código gRPC em um idioma diferente de DefaultLocale.
+func codeMessage(locale string, code codes.Code) string {
+	if message, exists := codeMessages[code][locale]; exists {
+		return message
+	}
+	return internalErrorMessages[locale]
+}
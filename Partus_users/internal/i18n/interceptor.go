@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// UnaryServerInterceptor anexa um errdetails.LocalizedMessage a todo erro devolvido pelos
+// serviços, no idioma escolhido pelo metadado accept-language. Em outros idiomas além de
+// DefaultLocale, as descrições do BadRequest também são traduzidas pelos motivos dos ErrorInfo.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			err = Localize(err, LocaleFromContext(ctx))
+		}
+		return resp, err
+	}
+}
+
+// Localize devolve o erro com um LocalizedMessage no idioma pedido. Erros que já têm um
+// LocalizedMessage são devolvidos sem alteração.
+func Localize(err error, locale string) error {
+	st := status.Convert(err)
+	if st.Code() == codes.OK {
+		return err
+	}
+
+	reasons := map[string]string{}
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.LocalizedMessage:
+			return err
+		case *errdetails.ErrorInfo:
+			if field, exists := detail.Metadata["field"]; exists {
+				reasons[field] = detail.Reason
+			}
+		}
+	}
+
+	proto := st.Proto()
+	message := st.Message()
+
+	if locale != DefaultLocale {
+		var descriptions []string
+		for i, detail := range proto.Details {
+			badRequest := &errdetails.BadRequest{}
+			if detail.UnmarshalTo(badRequest) != nil {
+				continue
+			}
+			for _, violation := range badRequest.FieldViolations {
+				if translated, exists := Message(locale, reasons[violation.Field]); exists {
+					violation.Description = translated
+				}
+				descriptions = append(descriptions, violation.Field+": "+violation.Description)
+			}
+			if translated, err := anypb.New(badRequest); err == nil {
+				proto.Details[i] = translated
+			}
+		}
+
+		message = codeMessage(locale, st.Code())
+		if len(descriptions) > 0 {
+			message += ": " + strings.Join(descriptions, "; ")
+		}
+	}
+
+	localizedMessage, detailErr := anypb.New(&errdetails.LocalizedMessage{Locale: locale, Message: message})
+	if detailErr != nil {
+		return err
+	}
+	proto.Details = append(proto.Details, localizedMessage)
+	return status.FromProto(proto).Err()
+}
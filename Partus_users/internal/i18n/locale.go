@@ -0,0 +1,82 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	Portuguese = "pt-BR"
+	English    = "en-US"
+	Spanish    = "es-ES"
+
+	// DefaultLocale é o idioma das mensagens escritas no código.
+	DefaultLocale = Portuguese
+
+	acceptLanguageMetadataKey = "accept-language"
+)
+
+var supportedLocales = []string{Portuguese, English, Spanish}
+
+// LocaleFromContext escolhe o idioma pelo metadado accept-language da chamada, no mesmo formato
+// do cabeçalho HTTP (ex: "en-US,en;q=0.9,pt;q=0.8"). Sem correspondência, usa DefaultLocale.
+func LocaleFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return DefaultLocale
+	}
+	return ParseAcceptLanguage(strings.Join(md.Get(acceptLanguageMetadataKey), ","))
+}
+
+// ParseAcceptLanguage retorna o idioma suportado de maior peso. A comparação é feita pelo idioma
+// principal, então "en-GB" e "es-MX" escolhem en-US e es-ES.
+func ParseAcceptLanguage(header string) string {
+	type preference struct {
+		language string
+		weight   float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		weight := 1.0
+		if value, found := strings.CutPrefix(strings.TrimSpace(params), "q="); found {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			weight = parsed
+		}
+		if weight <= 0 {
+			continue
+		}
+
+		language, _, _ := strings.Cut(tag, "-")
+		preferences = append(preferences, preference{language: strings.ToLower(language), weight: weight})
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].weight > preferences[j].weight
+	})
+
+	for _, preference := range preferences {
+		if preference.language == "*" {
+			return DefaultLocale
+		}
+		for _, locale := range supportedLocales {
+			if strings.HasPrefix(strings.ToLower(locale), preference.language+"-") {
+				return locale
+			}
+		}
+	}
+
+	return DefaultLocale
+}
@@ -0,0 +1,15 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+// LogMailer escreve as mensagens no log em vez de enviá-las. É usado quando o SMTP não está
+// configurado, para que o link de verificação ainda possa ser obtido em desenvolvimento.
+type LogMailer struct{}
+
+func (m *LogMailer) Send(ctx context.Context, message Message) error {
+	log.Printf("E-mail não enviado (SMTP não configurado): Para: %s, Assunto: %s\n%s", message.To, message.Subject, message.Body)
+	return nil
+}
@@ -0,0 +1,15 @@
+package mailer
+
+import "context"
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer envia e-mails transacionais. A implementação é escolhida na inicialização: SMTPMailer
+// quando o SMTP está configurado, LogMailer caso contrário e MemoryMailer nos testes.
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
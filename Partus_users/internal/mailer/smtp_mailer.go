@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+)
+
+type SMTPMailer struct {
+	config *config.SMTPConfig
+}
+
+func NewSMTPMailer(smtpConfig *config.SMTPConfig) *SMTPMailer {
+	return &SMTPMailer{config: smtpConfig}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// SMTP_FROM pode incluir o nome de exibição; o envelope SMTP usa apenas o endereço.
+	from, err := mail.ParseAddress(m.config.From)
+	if err != nil {
+		return fmt.Errorf("SMTP_FROM inválido: %w", err)
+	}
+
+	addr := net.JoinHostPort(m.config.Host, m.config.Port)
+
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	if err := smtp.SendMail(addr, auth, from.Address, []string{message.To}, buildMessage(from.String(), message)); err != nil {
+		return fmt.Errorf("falha ao enviar e-mail para %s: %w", message.To, err)
+	}
+	return nil
+}
+
+func buildMessage(from string, message Message) []byte {
+	var builder strings.Builder
+	builder.WriteString("From: " + from + "\r\n")
+	builder.WriteString("To: " + message.To + "\r\n")
+	builder.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(message.Body)
+	return []byte(builder.String())
+}
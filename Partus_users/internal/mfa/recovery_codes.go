@@ -0,0 +1,43 @@
+package mfa
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	RecoveryCodeCount = 10
+
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	recoveryCodeLength   = 10
+)
+
+// GenerateRecoveryCodes retorna códigos no formato xxxxx-xxxxx. Os códigos só são exibidos uma vez;
+// apenas os hashes são armazenados.
+func GenerateRecoveryCodes(count int) ([]string, error) {
+	codes := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		buf := make([]byte, recoveryCodeLength)
+		for j := range buf {
+			// rand.Int sorteia de forma uniforme; o resto de um byte favoreceria as primeiras letras.
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(recoveryCodeAlphabet))))
+			if err != nil {
+				return nil, fmt.Errorf("falha ao gerar os códigos de recuperação: %w", err)
+			}
+			buf[j] = recoveryCodeAlphabet[n.Int64()]
+		}
+		codes = append(codes, string(buf[:5])+"-"+string(buf[5:]))
+	}
+	return codes, nil
+}
+
+// HashRecoveryCode ignora maiúsculas, espaços e hífens, para aceitar o código como o usuário digitar.
+func HashRecoveryCode(code string) string {
+	normalized := strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,132 @@
+package mfa
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmSHA1   Algorithm = "SHA1"
+	AlgorithmSHA256 Algorithm = "SHA256"
+	AlgorithmSHA512 Algorithm = "SHA512"
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP gera e valida códigos de uso único baseados em tempo (RFC 6238).
+type TOTP struct {
+	Digits    int
+	Period    time.Duration
+	Algorithm Algorithm
+	// Skew é quantos períodos antes e depois do atual ainda são aceitos, para tolerar relógios
+	// fora de sincronia.
+	Skew int64
+}
+
+// DefaultTOTP usa os parâmetros aceitos pelos aplicativos autenticadores mais comuns.
+func DefaultTOTP() *TOTP {
+	return &TOTP{Digits: 6, Period: 30 * time.Second, Algorithm: AlgorithmSHA1, Skew: 1}
+}
+
+// GenerateSecret retorna uma chave aleatória de 160 bits, o tamanho recomendado para HMAC-SHA1.
+func GenerateSecret() ([]byte, error) {
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("falha ao gerar o segredo TOTP: %w", err)
+	}
+	return secret, nil
+}
+
+// EncodeSecret retorna o segredo em base32 sem padding, o formato digitado nos aplicativos.
+func EncodeSecret(secret []byte) string {
+	return secretEncoding.EncodeToString(secret)
+}
+
+// KeyURI monta a URI otpauth:// usada para gerar o QR code do cadastro.
+func (t *TOTP) KeyURI(secret []byte, issuer string, accountName string) string {
+	query := url.Values{}
+	query.Set("secret", EncodeSecret(secret))
+	query.Set("issuer", issuer)
+	query.Set("algorithm", string(t.Algorithm))
+	query.Set("digits", fmt.Sprint(t.Digits))
+	query.Set("period", fmt.Sprint(int64(t.Period/time.Second)))
+
+	uri := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + accountName,
+		RawQuery: query.Encode(),
+	}
+	return uri.String()
+}
+
+// Step retorna o contador de tempo (T na RFC 6238) do instante informado.
+func (t *TOTP) Step(now time.Time) int64 {
+	return now.Unix() / int64(t.Period/time.Second)
+}
+
+// GenerateCode retorna o código do período que contém now.
+func (t *TOTP) GenerateCode(secret []byte, now time.Time) string {
+	return t.codeAt(secret, t.Step(now))
+}
+
+// Validate verifica o código dentro da janela de tolerância e retorna o período em que ele foi
+// aceito, para que o chamador possa impedir que o mesmo código seja usado duas vezes.
+func (t *TOTP) Validate(secret []byte, code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != t.Digits {
+		return 0, false
+	}
+
+	current := t.Step(now)
+	for offset := -t.Skew; offset <= t.Skew; offset++ {
+		step := current + offset
+		if subtle.ConstantTimeCompare([]byte(t.codeAt(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// codeAt implementa o HOTP da RFC 4226 com truncamento dinâmico.
+func (t *TOTP) codeAt(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(t.hash(), secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for i := 0; i < t.Digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", t.Digits, value%modulo)
+}
+
+func (t *TOTP) hash() func() hash.Hash {
+	switch t.Algorithm {
+	case AlgorithmSHA256:
+		return sha256.New
+	case AlgorithmSHA512:
+		return sha512.New
+	default:
+		return sha1.New
+	}
+}
@@ -0,0 +1,214 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All lista as migrações do serviço. Novas migrações devem ser adicionadas ao final com a
+// próxima versão; versões já publicadas não podem ser alteradas.
+var All = []Migration{
+	{
+		Version:     1,
+		Description: "índice único de email em personal_info",
+		Up: createIndex("personal_info", mongo.IndexModel{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName("email_unique").SetUnique(true),
+		}),
+	},
+	{
+		Version:     2,
+		Description: "índice único de username em account_info",
+		Up: createIndex("account_info", mongo.IndexModel{
+			Keys:    bson.D{{Key: "username", Value: 1}},
+			Options: options.Index().SetName("username_unique").SetUnique(true),
+		}),
+	},
+	{
+		Version:     3,
+		Description: "índices únicos de userId em personal_info e account_info",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			for _, collection := range []string{"personal_info", "account_info"} {
+				err := createIndex(collection, mongo.IndexModel{
+					Keys:    bson.D{{Key: "userId", Value: 1}},
+					Options: options.Index().SetName("userId_unique").SetUnique(true),
+				})(ctx, db)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version:     4,
+		Description: "índice de deletedAt em users para listagem e limpeza de usuários removidos",
+		Up: createIndex("users", mongo.IndexModel{
+			Keys:    bson.D{{Key: "deletedAt", Value: 1}, {Key: "_id", Value: 1}},
+			Options: options.Index().SetName("deletedAt_id"),
+		}),
+	},
+	{
+		Version:     5,
+		Description: "índices únicos de email e username sem diferenciar maiúsculas de minúsculas",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			err := replaceIndex(ctx, db.Collection("personal_info"), "email_unique", mongo.IndexModel{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetName("email_unique_ci").SetUnique(true).SetCollation(config.CaseInsensitiveCollation()),
+			})
+			if err != nil {
+				return err
+			}
+
+			return replaceIndex(ctx, db.Collection("account_info"), "username_unique", mongo.IndexModel{
+				Keys:    bson.D{{Key: "username", Value: 1}},
+				Options: options.Index().SetName("username_unique_ci").SetUnique(true).SetCollation(config.CaseInsensitiveCollation()),
+			})
+		},
+	},
+	{
+		Version:     6,
+		Description: "índices da coleção sessions",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("sessions").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "tokenHash", Value: 1}}, Options: options.Index().SetName("tokenHash_unique").SetUnique(true)},
+				{Keys: bson.D{{Key: "usedTokenHashes", Value: 1}}, Options: options.Index().SetName("usedTokenHashes")},
+				{Keys: bson.D{{Key: "userId", Value: 1}}, Options: options.Index().SetName("userId")},
+				// Sessões expiradas são removidas pelo próprio MongoDB.
+				{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0)},
+			})
+			return err
+		},
+	},
+	{
+		Version:     7,
+		Description: "índices das coleções mfa e mfa_challenges",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("mfa").Indexes().CreateOne(ctx, mongo.IndexModel{
+				Keys:    bson.D{{Key: "userId", Value: 1}},
+				Options: options.Index().SetName("userId_unique").SetUnique(true),
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("mfa_challenges").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "tokenHash", Value: 1}}, Options: options.Index().SetName("tokenHash_unique").SetUnique(true)},
+				{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0)},
+			})
+			return err
+		},
+	},
+	{
+		Version:     8,
+		Description: "índices da coleção password_resets",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("password_resets").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "tokenHash", Value: 1}}, Options: options.Index().SetName("tokenHash_unique").SetUnique(true)},
+				{Keys: bson.D{{Key: "userId", Value: 1}}, Options: options.Index().SetName("userId")},
+				{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0)},
+			})
+			return err
+		},
+	},
+	{
+		Version:     9,
+		Description: "createdAt em UTC em account_info e users, desfazendo o ajuste de -3h de America/Sao_Paulo",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			if err := shiftCreatedAtToUTC(ctx, db.Collection("account_info"), "createdAt"); err != nil {
+				return err
+			}
+			return shiftCreatedAtToUTC(ctx, db.Collection("users"), "accountInfo.createdAt")
+		},
+	},
+	{
+		Version:     10,
+		Description: "índices da coleção audit_events",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("audit_events").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				// A sequence única impede que dois eventos concorrentes apontem para o mesmo anterior.
+				{Keys: bson.D{{Key: "sequence", Value: 1}}, Options: options.Index().SetName("sequence_unique").SetUnique(true)},
+				{Keys: bson.D{{Key: "documentId", Value: 1}, {Key: "sequence", Value: -1}}, Options: options.Index().SetName("documentId_sequence")},
+				{Keys: bson.D{{Key: "occurredAt", Value: 1}}, Options: options.Index().SetName("occurredAt")},
+			})
+			return err
+		},
+	},
+	{
+		Version:     11,
+		Description: "índices da coleção outbox",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("outbox").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "publishedAt", Value: 1}, {Key: "nextAttemptAt", Value: 1}, {Key: "_id", Value: 1}}, Options: options.Index().SetName("publishedAt_nextAttemptAt")},
+				// Eventos publicados são removidos pelo próprio MongoDB após a retenção.
+				{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetName("expiresAt_ttl").SetExpireAfterSeconds(0)},
+			})
+			return err
+		},
+	},
+	{
+		Version:     12,
+		Description: "índices de ordenação de ListUsers em personal_info e account_info",
+		Up: func(ctx context.Context, db *mongo.Database) error {
+			_, err := db.Collection("personal_info").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "email", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetName("email_userId")},
+				{Keys: bson.D{{Key: "lastName", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetName("lastName_userId")},
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = db.Collection("account_info").Indexes().CreateMany(ctx, []mongo.IndexModel{
+				{Keys: bson.D{{Key: "createdAt", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetName("createdAt_userId")},
+				{Keys: bson.D{{Key: "accountStatus", Value: 1}, {Key: "createdAt", Value: 1}, {Key: "userId", Value: 1}}, Options: options.Index().SetName("accountStatus_createdAt_userId")},
+			})
+			return err
+		},
+	},
+}
+
+// saoPauloAdjustment é o deslocamento que era subtraído de createdAt antes de gravá-lo.
+const saoPauloAdjustment = 3 * time.Hour
+
+// createdAtUTCMarker marca os documentos já corrigidos, para que uma nova execução da migração não
+// some o deslocamento duas vezes. Documentos criados depois da migração não precisam da marca:
+// as migrações são aplicadas antes de o servidor aceitar requisições.
+const createdAtUTCMarker = "createdAtMigratedToUTC"
+
+func shiftCreatedAtToUTC(ctx context.Context, collection *mongo.Collection, field string) error {
+	filter := bson.M{field: bson.M{"$type": "date"}, createdAtUTCMarker: bson.M{"$exists": false}}
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.D{
+		{Key: field, Value: bson.M{"$add": bson.A{"$" + field, saoPauloAdjustment.Milliseconds()}}},
+		{Key: createdAtUTCMarker, Value: true},
+	}}}}
+
+	_, err := collection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// replaceIndex cria o novo índice e remove o antigo, ignorando-o se ele já não existir.
+func replaceIndex(ctx context.Context, collection *mongo.Collection, oldName string, index mongo.IndexModel) error {
+	if _, err := collection.Indexes().CreateOne(ctx, index); err != nil {
+		return err
+	}
+
+	_, err := collection.Indexes().DropOne(ctx, oldName)
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) && commandErr.Name == "IndexNotFound" {
+		return nil
+	}
+	return err
+}
+
+func createIndex(collection string, index mongo.IndexModel) func(ctx context.Context, db *mongo.Database) error {
+	return func(ctx context.Context, db *mongo.Database) error {
+		_, err := db.Collection(collection).Indexes().CreateOne(ctx, index)
+		return err
+	}
+}
@@ -0,0 +1,91 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/jonh-dev/go-logger/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const migrationsCollection = "schema_migrations"
+
+// Migration é uma alteração versionada do banco. Up deve ser idempotente: se o processo cair
+// depois de Up e antes do registro, a migração é executada de novo na próxima inicialização.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, db *mongo.Database) error
+}
+
+type appliedMigration struct {
+	Version     int       `bson:"_id"`
+	Description string    `bson:"description"`
+	AppliedAt   time.Time `bson:"appliedAt"`
+}
+
+type Migrator struct {
+	db         *mongo.Database
+	migrations []Migration
+}
+
+func NewMigrator(db *mongo.Database, migrations []Migration) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+
+	return &Migrator{db: db, migrations: sorted}
+}
+
+// Migrate aplica, em ordem de versão, as migrações ainda não registradas em schema_migrations e
+// retorna quantas foram aplicadas.
+func (m *Migrator) Migrate(ctx context.Context) (int, error) {
+	applied, err := m.appliedVersions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Aplicando migração %d: %s", migration.Version, migration.Description))
+		if err := migration.Up(ctx, m.db); err != nil {
+			return count, fmt.Errorf("falha ao aplicar a migração %d (%s): %w", migration.Version, migration.Description, err)
+		}
+
+		record := appliedMigration{Version: migration.Version, Description: migration.Description, AppliedAt: time.Now()}
+		_, err := m.db.Collection(migrationsCollection).InsertOne(ctx, record)
+		if err != nil && !mongo.IsDuplicateKeyError(err) {
+			return count, fmt.Errorf("falha ao registrar a migração %d: %w", migration.Version, err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+func (m *Migrator) appliedVersions(ctx context.Context) (map[int]bool, error) {
+	cursor, err := m.db.Collection(migrationsCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar as migrações aplicadas: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []appliedMigration
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar as migrações aplicadas: %w", err)
+	}
+
+	applied := make(map[int]bool, len(records))
+	for _, record := range records {
+		applied[record.Version] = true
+	}
+	return applied, nil
+}
@@ -22,10 +22,19 @@ type AccountInfo struct {
 	LastFailedLoginReason string             `bson:"lastFailedLoginReason,omitempty"`
 	AccountLockedUntil    time.Time          `bson:"accountLockedUntil,omitempty"`
 	AccountLockedReason   string             `bson:"accountLockedReason,omitempty"`
+	// SuspendedUntil é o fim da suspensão temporária; ao passar, a conta volta a ACTIVE.
+	SuspendedUntil time.Time `bson:"suspendedUntil,omitempty"`
+	// StatusHistory guarda as mudanças de status, da mais antiga para a mais recente.
+	StatusHistory []StatusTransition `bson:"statusHistory,omitempty"`
+	// PasswordHistory guarda os hashes das senhas anteriores, da mais antiga para a mais recente.
+	// Nunca é exposto no proto.
+	PasswordHistory []string `bson:"passwordHistory,omitempty"`
 }
 
+// ToProto converte o AccountInfo com o hash da senha, para uso entre as camadas do serviço. Para
+// respostas, use ToResponseProto.
 func (a *AccountInfo) ToProto() *api.AccountInfo {
-	return &api.AccountInfo{
+	accountInfo := &api.AccountInfo{
 		UserId:                a.UserId.Hex(),
 		Username:              a.Username,
 		Password:              a.Password,
@@ -40,4 +49,18 @@ func (a *AccountInfo) ToProto() *api.AccountInfo {
 		AccountLockedUntil:    timestamppb.New(a.AccountLockedUntil),
 		AccountLockedReason:   a.AccountLockedReason,
 	}
+	if !a.SuspendedUntil.IsZero() {
+		accountInfo.SuspendedUntil = timestamppb.New(a.SuspendedUntil)
+	}
+	for i := range a.StatusHistory {
+		accountInfo.StatusHistory = append(accountInfo.StatusHistory, a.StatusHistory[i].ToProto())
+	}
+	return accountInfo
+}
+
+// ToResponseProto é a visão de saída do AccountInfo: igual a ToProto, mas sem os segredos.
+func (a *AccountInfo) ToResponseProto() *api.AccountInfo {
+	accountInfo := a.ToProto()
+	accountInfo.Password = ""
+	return accountInfo
 }
@@ -1,5 +1,12 @@
 package model
 
+import (
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
 type AccountStatus int32
 
 const (
@@ -8,3 +15,51 @@ const (
 	AccountStatus_PENDING   AccountStatus = 2
 	AccountStatus_SUSPENDED AccountStatus = 3
 )
+
+// accountStatusTransitions lista, para cada status, os status para os quais a conta pode ir.
+// Qualquer status pode ir para INACTIVE, que é definitivo.
+var accountStatusTransitions = map[AccountStatus][]AccountStatus{
+	AccountStatus_PENDING:   {AccountStatus_ACTIVE, AccountStatus_INACTIVE},
+	AccountStatus_ACTIVE:    {AccountStatus_SUSPENDED, AccountStatus_INACTIVE},
+	AccountStatus_SUSPENDED: {AccountStatus_ACTIVE, AccountStatus_INACTIVE},
+	AccountStatus_INACTIVE:  {},
+}
+
+func (s AccountStatus) String() string {
+	return api.AccountStatus(s).String()
+}
+
+// CanTransition indica se a conta pode passar de from para to.
+func CanTransition(from AccountStatus, to AccountStatus) bool {
+	for _, allowed := range accountStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTransition é uma entrada do statusHistory do AccountInfo.
+type StatusTransition struct {
+	From      AccountStatus `bson:"from"`
+	To        AccountStatus `bson:"to"`
+	Reason    string        `bson:"reason"`
+	Actor     string        `bson:"actor"`
+	ChangedAt time.Time     `bson:"changedAt"`
+	// SuspendedUntil só é gravado nas suspensões temporárias.
+	SuspendedUntil time.Time `bson:"suspendedUntil,omitempty"`
+}
+
+func (t *StatusTransition) ToProto() *api.AccountStatusTransition {
+	transition := &api.AccountStatusTransition{
+		From:      api.AccountStatus(t.From),
+		To:        api.AccountStatus(t.To),
+		Reason:    t.Reason,
+		Actor:     t.Actor,
+		ChangedAt: timestamppb.New(t.ChangedAt),
+	}
+	if !t.SuspendedUntil.IsZero() {
+		transition.SuspendedUntil = timestamppb.New(t.SuspendedUntil)
+	}
+	return transition
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	AuditOperationCreate = "create"
+	AuditOperationUpdate = "update"
+	AuditOperationDelete = "delete"
+)
+
+// AuditEvent registra uma alteração em users, personal_info ou account_info. Os eventos formam uma
+// cadeia: Hash cobre todos os campos do evento e o PrevHash do anterior, na ordem de Sequence, de
+// modo que alterar ou remover um evento já gravado quebra a cadeia a partir dele.
+type AuditEvent struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty"`
+	Sequence   int64              `bson:"sequence"`
+	Collection string             `bson:"collection"`
+	DocumentId string             `bson:"documentId"`
+	Operation  string             `bson:"operation"`
+	Actor      string             `bson:"actor"`
+	Method     string             `bson:"method,omitempty"`
+	RequestId  string             `bson:"requestId,omitempty"`
+	Changes    []AuditChange      `bson:"changes,omitempty"`
+	OccurredAt time.Time          `bson:"occurredAt"`
+	PrevHash   string             `bson:"prevHash"`
+	Hash       string             `bson:"hash"`
+}
+
+// AuditChange é a mudança de um campo, identificado pelo caminho JSON. Campos sensíveis registram
+// apenas que mudaram: Before e After ficam vazios e Redacted é true.
+type AuditChange struct {
+	Field    string `bson:"field" json:"field"`
+	Before   string `bson:"before,omitempty" json:"before,omitempty"`
+	After    string `bson:"after,omitempty" json:"after,omitempty"`
+	Redacted bool   `bson:"redacted,omitempty" json:"redacted,omitempty"`
+}
+
+func (e *AuditEvent) ToProto() *api.AuditEvent {
+	changes := make([]*api.AuditChange, 0, len(e.Changes))
+	for _, change := range e.Changes {
+		changes = append(changes, &api.AuditChange{
+			Field:    change.Field,
+			Before:   change.Before,
+			After:    change.After,
+			Redacted: change.Redacted,
+		})
+	}
+
+	return &api.AuditEvent{
+		Id:         e.Id.Hex(),
+		Sequence:   e.Sequence,
+		Collection: e.Collection,
+		DocumentId: e.DocumentId,
+		Operation:  e.Operation,
+		Actor:      e.Actor,
+		Method:     e.Method,
+		RequestId:  e.RequestId,
+		Changes:    changes,
+		OccurredAt: timestamppb.New(e.OccurredAt),
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+	}
+}
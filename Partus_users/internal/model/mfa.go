@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MFA guarda o segundo fator do usuário. O segredo TOTP fica cifrado e os códigos de recuperação
+// são armazenados apenas como hash; cada código é removido da lista ao ser usado.
+type MFA struct {
+	Id                 primitive.ObjectID `bson:"_id,omitempty"`
+	UserId             primitive.ObjectID `bson:"userId,omitempty"`
+	EncryptedSecret    string             `bson:"encryptedSecret,omitempty"`
+	Enabled            bool               `bson:"enabled"`
+	RecoveryCodeHashes []string           `bson:"recoveryCodeHashes,omitempty"`
+	// LastUsedStep é o último período TOTP aceito, para que um código não seja usado duas vezes.
+	LastUsedStep int64     `bson:"lastUsedStep,omitempty"`
+	CreatedAt    time.Time `bson:"createdAt,omitempty"`
+	ConfirmedAt  time.Time `bson:"confirmedAt,omitempty"`
+}
+
+// MFAChallenge é emitido pelo login quando a senha está correta e o usuário tem MFA ativo. Só o
+// hash do token é armazenado, como nas sessões.
+type MFAChallenge struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	UserId    primitive.ObjectID `bson:"userId,omitempty"`
+	TokenHash string             `bson:"tokenHash,omitempty"`
+	Attempts  int32              `bson:"attempts"`
+	CreatedAt time.Time          `bson:"createdAt,omitempty"`
+	ExpiresAt time.Time          `bson:"expiresAt,omitempty"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// OutboxEvent é um evento de domínio aguardando publicação. É gravado na mesma transação da
+// alteração que o originou e removido pelo MongoDB depois de ExpiresAt, preenchido ao ser publicado.
+// NextAttemptAt é o próximo momento em que o relay pode reservá-lo.
+type OutboxEvent struct {
+	Id            primitive.ObjectID `bson:"_id,omitempty"`
+	Type          string             `bson:"type"`
+	Subject       string             `bson:"subject"`
+	Data          string             `bson:"data"`
+	CreatedAt     time.Time          `bson:"createdAt"`
+	NextAttemptAt time.Time          `bson:"nextAttemptAt"`
+	Attempts      int32              `bson:"attempts"`
+	LastError     string             `bson:"lastError,omitempty"`
+	PublishedAt   time.Time          `bson:"publishedAt,omitempty"`
+	ExpiresAt     time.Time          `bson:"expiresAt,omitempty"`
+}
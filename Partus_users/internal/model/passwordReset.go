@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// PasswordReset é um pedido de redefinição de senha. Só o hash do token é armazenado e o token vale
+// uma única vez: UsedAt é preenchido ao ser consumido ou quando um pedido mais novo o substitui.
+type PasswordReset struct {
+	Id        primitive.ObjectID `bson:"_id,omitempty"`
+	UserId    primitive.ObjectID `bson:"userId,omitempty"`
+	TokenHash string             `bson:"tokenHash,omitempty"`
+	CreatedAt time.Time          `bson:"createdAt,omitempty"`
+	ExpiresAt time.Time          `bson:"expiresAt,omitempty"`
+	UsedAt    time.Time          `bson:"usedAt,omitempty"`
+}
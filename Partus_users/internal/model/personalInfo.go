@@ -16,10 +16,15 @@ type PersonalInfo struct {
 	BirthDate    time.Time          `bson:"birthDate,omitempty"`
 	Phone        string             `bson:"phone,omitempty"`
 	ProfileImage string             `bson:"profileImage,omitempty"`
+	TimeZone     string             `bson:"timeZone,omitempty"`
+	// EmailVerifiedAt só é gravado pela verificação de e-mail e é removido quando o e-mail muda.
+	EmailVerifiedAt time.Time `bson:"emailVerifiedAt,omitempty"`
 }
 
+var PersonalInfoFields = []string{"firstName", "lastName", "email", "birthDate", "phone", "profileImage", "timeZone"}
+
 func (p *PersonalInfo) ToProto() *api.PersonalInfo {
-	return &api.PersonalInfo{
+	personalInfo := &api.PersonalInfo{
 		UserId:       p.UserId.Hex(),
 		FirstName:    p.FirstName,
 		LastName:     p.LastName,
@@ -27,5 +32,10 @@ func (p *PersonalInfo) ToProto() *api.PersonalInfo {
 		BirthDate:    timestamppb.New(p.BirthDate),
 		Phone:        p.Phone,
 		ProfileImage: p.ProfileImage,
+		TimeZone:     p.TimeZone,
+	}
+	if !p.EmailVerifiedAt.IsZero() {
+		personalInfo.EmailVerifiedAt = timestamppb.New(p.EmailVerifiedAt)
 	}
+	return personalInfo
 }
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Session representa um login e a cadeia de refresh tokens derivada dele. Apenas o hash do token
+// atual é válido; os hashes já trocados ficam em UsedTokenHashes para detectar reutilização.
+type Session struct {
+	Id              primitive.ObjectID `bson:"_id,omitempty"`
+	UserId          primitive.ObjectID `bson:"userId,omitempty"`
+	TokenHash       string             `bson:"tokenHash,omitempty"`
+	UsedTokenHashes []string           `bson:"usedTokenHashes,omitempty"`
+	Client          SessionClient      `bson:",inline"`
+	CreatedAt       time.Time          `bson:"createdAt,omitempty"`
+	LastSeenAt      time.Time          `bson:"lastSeenAt,omitempty"`
+	ExpiresAt       time.Time          `bson:"expiresAt,omitempty"`
+	RevokedAt       time.Time          `bson:"revokedAt,omitempty"`
+	RevokedReason   string             `bson:"revokedReason,omitempty"`
+}
+
+// SessionClient identifica o dispositivo que abriu ou renovou a sessão, a partir dos metadados gRPC.
+type SessionClient struct {
+	Device    string `bson:"device,omitempty"`
+	IpAddress string `bson:"ipAddress,omitempty"`
+	UserAgent string `bson:"userAgent,omitempty"`
+}
+
+func (s *Session) ToProto() *api.Session {
+	return &api.Session{
+		Id:         s.Id.Hex(),
+		UserId:     s.UserId.Hex(),
+		Device:     s.Client.Device,
+		IpAddress:  s.Client.IpAddress,
+		UserAgent:  s.Client.UserAgent,
+		CreatedAt:  timestamppb.New(s.CreatedAt),
+		LastSeenAt: timestamppb.New(s.LastSeenAt),
+		ExpiresAt:  timestamppb.New(s.ExpiresAt),
+	}
+}
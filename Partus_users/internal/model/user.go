@@ -1,14 +1,27 @@
 package model
 
 import (
+	"time"
+
 	"github.com/jonh-dev/partus_users/api"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Papéis administrativos, levados no access token.
+const (
+	// RoleSupport permite listar e revogar as sessões de outros usuários.
+	RoleSupport = "support"
+	// RoleAdmin permite, além do que o suporte faz, alterar o status das contas.
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	Id           primitive.ObjectID `bson:"_id,omitempty"`
 	PersonalInfo PersonalInfo       `bson:"personalInfo,omitempty"`
 	AccountInfo  AccountInfo        `bson:"accountInfo,omitempty"`
+	DeletedAt    time.Time          `bson:"deletedAt,omitempty"`
+	// Roles são atribuídos diretamente no banco; nenhuma RPC os altera e eles não são expostos no proto.
+	Roles []string `bson:"roles,omitempty"`
 }
 
 func (u *User) ToProto() *api.User {
@@ -18,3 +31,12 @@ func (u *User) ToProto() *api.User {
 		AccountInfo:  u.AccountInfo.ToProto(),
 	}
 }
+
+// ToResponseProto é a visão de saída do User, com o AccountInfo sem os segredos.
+func (u *User) ToResponseProto() *api.User {
+	return &api.User{
+		Id:           u.Id.Hex(),
+		PersonalInfo: u.PersonalInfo.ToProto(),
+		AccountInfo:  u.AccountInfo.ToResponseProto(),
+	}
+}
@@ -0,0 +1,66 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// BreachedCorpus consulta uma cópia local da base de senhas vazadas no formato de faixas do Have I
+// Been Pwned: um arquivo <PREFIXO>.txt para cada prefixo de 5 caracteres do SHA-1, com linhas
+// SUFIXO:OCORRÊNCIAS. Só o arquivo do prefixo da senha é lido, e nada é enviado pela rede.
+type BreachedCorpus struct {
+	dir string
+}
+
+func NewBreachedCorpus(dir string) (*BreachedCorpus, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir a base de senhas vazadas: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("a base de senhas vazadas deve ser um diretório: %s", dir)
+	}
+	return &BreachedCorpus{dir: dir}, nil
+}
+
+// Occurrences retorna quantas vezes a senha aparece na base. A ausência do arquivo da faixa é
+// tratada como zero ocorrências, o que permite usar uma base parcial.
+func (c *BreachedCorpus) Occurrences(password string) (int, error) {
+	digest := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(digest[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	file, err := os.Open(filepath.Join(c.dir, prefix+".txt"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("falha ao abrir a faixa %s da base de senhas vazadas: %w", prefix, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lineSuffix, count, found := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !found || !strings.EqualFold(lineSuffix, suffix) {
+			continue
+		}
+		occurrences, err := strconv.Atoi(count)
+		if err != nil {
+			return 0, fmt.Errorf("linha inválida na faixa %s da base de senhas vazadas: %w", prefix, err)
+		}
+		return occurrences, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, fmt.Errorf("falha ao ler a faixa %s da base de senhas vazadas: %w", prefix, err)
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,101 @@
+package passwordpolicy
+
+import (
+	"fmt"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+)
+
+// Códigos das recusas, no mesmo formato dos motivos do pacote validation.
+const (
+	CodeCommonPassword   = "COMMON_PASSWORD"
+	CodeBreachedPassword = "BREACHED_PASSWORD"
+	CodeWeakPassword     = "WEAK_PASSWORD"
+)
+
+// Rejection é o erro de uma senha recusada pela política. Reason pode ser mostrado ao usuário e
+// Code identifica o motivo para o cliente.
+type Rejection struct {
+	Code   string
+	Reason string
+}
+
+func (r *Rejection) Error() string {
+	return r.Reason
+}
+
+// Checker complementa as regras de composição da senha recusando senhas vazadas, senhas comuns e
+// senhas fáceis de adivinhar.
+type Checker struct {
+	breached         *BreachedCorpus
+	common           *CommonPasswords
+	minStrengthScore int
+}
+
+// NewChecker aceita breached nil quando não há base de senhas vazadas disponível.
+func NewChecker(breached *BreachedCorpus, common *CommonPasswords, minStrengthScore int) *Checker {
+	return &Checker{breached: breached, common: common, minStrengthScore: minStrengthScore}
+}
+
+func NewCheckerFromPolicy(policy *config.PasswordPolicy) (*Checker, error) {
+	common, err := LoadCommonPasswords(policy.CommonPasswordsFile, policy.CommonPasswordsLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	var breached *BreachedCorpus
+	if policy.BreachedCorpusDir != "" {
+		if breached, err = NewBreachedCorpus(policy.BreachedCorpusDir); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewChecker(breached, common, policy.MinStrengthScore), nil
+}
+
+// Check retorna *Rejection quando a senha é recusada e outro erro se a base de senhas vazadas não
+// puder ser lida. userInputs são dados do usuário, como nome de usuário e e-mail, que não devem
+// aparecer na senha.
+func (c *Checker) Check(password string, userInputs ...string) error {
+	if c.common.Contains(password) {
+		return &Rejection{Code: CodeCommonPassword, Reason: "Esta senha está entre as mais usadas; escolha outra"}
+	}
+
+	if c.breached != nil {
+		occurrences, err := c.breached.Occurrences(password)
+		if err != nil {
+			return err
+		}
+		if occurrences > 0 {
+			return &Rejection{Code: CodeBreachedPassword, Reason: "Esta senha apareceu em vazamentos de dados conhecidos; escolha outra"}
+		}
+	}
+
+	strength := EstimateStrength(password, c.common, userInputs...)
+	if strength.Score < c.minStrengthScore {
+		return &Rejection{Code: CodeWeakPassword, Reason: fmt.Sprintf("A senha é fácil de adivinhar (força %d de 4, mínimo %d). %s", strength.Score, c.minStrengthScore, suggestion(strength.Patterns))}
+	}
+
+	return nil
+}
+
+// suggestion aponta o primeiro padrão previsível encontrado na senha.
+func suggestion(patterns []PatternKind) string {
+	for _, pattern := range patterns {
+		switch pattern {
+		case PatternUserInput:
+			return "Não use o nome de usuário ou o e-mail na senha."
+		case PatternDictionary:
+			return "Evite palavras e senhas comuns, mesmo com letras trocadas por números ou símbolos."
+		case PatternSequence:
+			return "Evite sequências como abc ou 123."
+		case PatternRepeat:
+			return "Evite caracteres repetidos."
+		case PatternKeyboard:
+			return "Evite sequências do teclado, como qwerty."
+		case PatternYear:
+			return "Evite anos e datas."
+		}
+	}
+	return "Use uma senha mais longa, com palavras pouco comuns."
+}
@@ -0,0 +1,74 @@
+package passwordpolicy
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+//go:embed common_passwords.txt
+var embeddedCommonPasswords string
+
+// CommonPasswords guarda a posição de cada senha na lista, começando em 1, para que o estimador
+// de força trate as mais usadas como mais fáceis de adivinhar.
+type CommonPasswords struct {
+	ranks map[string]int
+}
+
+// DefaultCommonPasswords usa a lista embutida no binário.
+func DefaultCommonPasswords() *CommonPasswords {
+	common, _ := readCommonPasswords(strings.NewReader(embeddedCommonPasswords), 0)
+	return common
+}
+
+// LoadCommonPasswords lê as limit primeiras senhas do arquivo. Com path vazio, usa a lista embutida.
+func LoadCommonPasswords(path string, limit int) (*CommonPasswords, error) {
+	if path == "" {
+		return readCommonPasswords(strings.NewReader(embeddedCommonPasswords), limit)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao abrir a lista de senhas comuns: %w", err)
+	}
+	defer file.Close()
+
+	return readCommonPasswords(file, limit)
+}
+
+func readCommonPasswords(reader io.Reader, limit int) (*CommonPasswords, error) {
+	common := &CommonPasswords{ranks: make(map[string]int)}
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		if limit > 0 && len(common.ranks) >= limit {
+			break
+		}
+		password := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if password == "" {
+			continue
+		}
+		if _, exists := common.ranks[password]; !exists {
+			common.ranks[password] = len(common.ranks) + 1
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao ler a lista de senhas comuns: %w", err)
+	}
+
+	return common, nil
+}
+
+// Contains compara sem diferenciar maiúsculas de minúsculas.
+func (c *CommonPasswords) Contains(password string) bool {
+	_, exists := c.ranks[strings.ToLower(password)]
+	return exists
+}
+
+func (c *CommonPasswords) rank(word string) (int, bool) {
+	rank, exists := c.ranks[word]
+	return rank, exists
+}
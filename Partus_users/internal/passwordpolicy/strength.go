@@ -0,0 +1,335 @@
+package passwordpolicy
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Estimativa de força no estilo do zxcvbn, simplificada: a senha é coberta pela combinação de
+// padrões (palavras comuns, sequências, repetições, teclado e anos) que exige menos tentativas, e
+// o que sobra é contado como força bruta. O número de tentativas vira uma nota de 0 a 4.
+
+type PatternKind string
+
+const (
+	PatternDictionary PatternKind = "dictionary"
+	PatternUserInput  PatternKind = "user_input"
+	PatternSequence   PatternKind = "sequence"
+	PatternRepeat     PatternKind = "repeat"
+	PatternKeyboard   PatternKind = "keyboard"
+	PatternYear       PatternKind = "year"
+	PatternBruteforce PatternKind = "bruteforce"
+)
+
+type Strength struct {
+	Score   int
+	Guesses float64
+	// Patterns lista, em ordem, os padrões usados na melhor cobertura da senha.
+	Patterns []PatternKind
+}
+
+const (
+	bruteforceCardinality = 10
+	minSubmatchGuesses    = 50
+	keyboardStartingKeys  = 94
+	keyboardAverageDegree = 4.6
+)
+
+var keyboardRows = []string{"1234567890-=", "qwertyuiop", "asdfghjkl", "zxcvbnm", "!@#$%^&*()_+"}
+
+var leetSubstitutions = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z',
+}
+
+type patternMatch struct {
+	start   int
+	end     int
+	kind    PatternKind
+	guesses float64
+}
+
+// EstimateStrength calcula a força da senha. userInputs são dados do próprio usuário, como nome
+// de usuário e e-mail, tratados como as palavras mais fáceis de adivinhar.
+func EstimateStrength(password string, dictionary *CommonPasswords, userInputs ...string) Strength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return Strength{}
+	}
+
+	matches := findMatches(runes, dictionary, userInputs)
+
+	// best[i] guarda a menor quantidade de tentativas para cobrir os i primeiros caracteres.
+	best := make([]float64, len(runes)+1)
+	choice := make([]patternMatch, len(runes)+1)
+	best[0] = 1
+	for end := 1; end <= len(runes); end++ {
+		best[end] = math.Inf(1)
+		for start := 0; start < end; start++ {
+			candidate := patternMatch{start: start, end: end, kind: PatternBruteforce, guesses: bruteforceGuesses(end - start)}
+			if guesses := best[start] * candidate.guesses; guesses < best[end] {
+				best[end], choice[end] = guesses, candidate
+			}
+		}
+		for _, match := range matches {
+			if match.end != end {
+				continue
+			}
+			if guesses := best[match.start] * match.guesses; guesses < best[end] {
+				best[end], choice[end] = guesses, match
+			}
+		}
+	}
+
+	var patterns []PatternKind
+	for end := len(runes); end > 0; end = choice[end].start {
+		patterns = append([]PatternKind{choice[end].kind}, patterns...)
+	}
+
+	guesses := best[len(runes)]
+	return Strength{Score: scoreFromGuesses(guesses), Guesses: guesses, Patterns: patterns}
+}
+
+func scoreFromGuesses(guesses float64) int {
+	switch {
+	case guesses < 1e3+5:
+		return 0
+	case guesses < 1e6+5:
+		return 1
+	case guesses < 1e8+5:
+		return 2
+	case guesses < 1e10+5:
+		return 3
+	default:
+		return 4
+	}
+}
+
+func bruteforceGuesses(length int) float64 {
+	guesses := math.Pow(bruteforceCardinality, float64(length))
+	if length == 1 {
+		return guesses + 1
+	}
+	return guesses
+}
+
+func findMatches(runes []rune, dictionary *CommonPasswords, userInputs []string) []patternMatch {
+	var matches []patternMatch
+	matches = append(matches, dictionaryMatches(runes, dictionary, userInputs)...)
+	matches = append(matches, sequenceMatches(runes)...)
+	matches = append(matches, repeatMatches(runes)...)
+	matches = append(matches, keyboardMatches(runes)...)
+	matches = append(matches, yearMatches(runes)...)
+
+	for i := range matches {
+		if matches[i].guesses < minSubmatchGuesses {
+			matches[i].guesses = minSubmatchGuesses
+		}
+	}
+	return matches
+}
+
+func dictionaryMatches(runes []rune, dictionary *CommonPasswords, userInputs []string) []patternMatch {
+	inputs := make(map[string]int)
+	for i, input := range userInputs {
+		for _, part := range strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len([]rune(part)) >= 3 {
+				if _, exists := inputs[part]; !exists {
+					inputs[part] = i + 1
+				}
+			}
+		}
+	}
+
+	var matches []patternMatch
+	for start := 0; start < len(runes); start++ {
+		for end := start + 3; end <= len(runes); end++ {
+			token := runes[start:end]
+			lower := strings.ToLower(string(token))
+			unleeted := unleet(lower)
+
+			for _, candidate := range []struct {
+				word  string
+				extra float64
+			}{{lower, 1}, {unleeted, 2}} {
+				if candidate.extra > 1 && candidate.word == lower {
+					continue
+				}
+				variations := uppercaseVariations(token) * candidate.extra
+				if rank, exists := inputs[candidate.word]; exists {
+					matches = append(matches, patternMatch{start, end, PatternUserInput, float64(rank) * variations})
+				}
+				if dictionary == nil {
+					continue
+				}
+				if rank, exists := dictionary.rank(candidate.word); exists {
+					matches = append(matches, patternMatch{start, end, PatternDictionary, float64(rank) * variations})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+func unleet(word string) string {
+	return strings.Map(func(r rune) rune {
+		if substitute, exists := leetSubstitutions[r]; exists {
+			return substitute
+		}
+		return r
+	}, word)
+}
+
+// uppercaseVariations segue o zxcvbn: só minúsculas não acrescentam nada, a primeira ou a última
+// letra maiúscula, ou todas maiúsculas, dobram as tentativas, e o resto conta as combinações.
+func uppercaseVariations(token []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range token {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(token[0]) || unicode.IsUpper(token[len(token)-1]))) {
+		return 2
+	}
+
+	variations := 0.0
+	for i := 1; i <= upper && i <= lower; i++ {
+		variations += binomial(upper+lower, i)
+	}
+	return variations
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+func sequenceMatches(runes []rune) []patternMatch {
+	var matches []patternMatch
+	start := 0
+	for start < len(runes)-2 {
+		delta := runes[start+1] - runes[start]
+		end := start + 1
+		for end < len(runes) && runes[end]-runes[end-1] == delta && sameClass(runes[end], runes[start]) {
+			end++
+		}
+		if (delta == 1 || delta == -1) && end-start >= 3 {
+			matches = append(matches, patternMatch{start, end, PatternSequence, sequenceGuesses(runes[start], end-start, delta < 0)})
+		}
+		if end-start > 1 {
+			start = end - 1
+		} else {
+			start++
+		}
+	}
+	return matches
+}
+
+func sequenceGuesses(first rune, length int, descending bool) float64 {
+	base := 26.0
+	switch {
+	case strings.ContainsRune("aAzZ019", first):
+		base = 4
+	case unicode.IsDigit(first):
+		base = 10
+	}
+	if descending {
+		base *= 2
+	}
+	return base * float64(length)
+}
+
+func sameClass(a, b rune) bool {
+	return (unicode.IsDigit(a) && unicode.IsDigit(b)) || (unicode.IsLower(a) && unicode.IsLower(b)) || (unicode.IsUpper(a) && unicode.IsUpper(b))
+}
+
+func repeatMatches(runes []rune) []patternMatch {
+	var matches []patternMatch
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && runes[end] == runes[start] {
+			end++
+		}
+		if end-start >= 3 {
+			matches = append(matches, patternMatch{start, end, PatternRepeat, characterCardinality(runes[start]) * float64(end-start)})
+		}
+		start = end
+	}
+	return matches
+}
+
+func characterCardinality(r rune) float64 {
+	switch {
+	case unicode.IsDigit(r):
+		return 10
+	case unicode.IsLetter(r):
+		return 26
+	default:
+		return 33
+	}
+}
+
+func keyboardMatches(runes []rune) []patternMatch {
+	lower := []rune(strings.ToLower(string(runes)))
+
+	var matches []patternMatch
+	for start := 0; start < len(lower); start++ {
+		for end := start + 4; end <= len(lower); end++ {
+			if !onKeyboardRow(string(lower[start:end])) {
+				break
+			}
+			guesses := float64(end-start-1) * keyboardStartingKeys * keyboardAverageDegree
+			matches = append(matches, patternMatch{start, end, PatternKeyboard, guesses * uppercaseVariations(runes[start:end])})
+		}
+	}
+	return matches
+}
+
+func onKeyboardRow(token string) bool {
+	for _, row := range keyboardRows {
+		if strings.Contains(row, token) || strings.Contains(reverse(row), token) {
+			return true
+		}
+	}
+	return false
+}
+
+func reverse(value string) string {
+	runes := []rune(value)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+func yearMatches(runes []rune) []patternMatch {
+	currentYear := time.Now().Year()
+
+	var matches []patternMatch
+	for start := 0; start+4 <= len(runes); start++ {
+		year, err := strconv.Atoi(string(runes[start : start+4]))
+		if err != nil || year < 1900 || year > 2099 {
+			continue
+		}
+		distance := math.Abs(float64(currentYear - year))
+		if distance < 20 {
+			distance = 20
+		}
+		matches = append(matches, patternMatch{start, start + 4, PatternYear, distance})
+	}
+	return matches
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jonh-dev/partus_users/api"
 	"github.com/jonh-dev/partus_users/internal/config"
@@ -10,15 +11,25 @@ import (
 	"github.com/jonh-dev/partus_users/internal/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type IAccountInfoRepository interface {
 	CreateAccountInfo(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error)
 	GetAccountInfo(ctx context.Context, id string) (*api.AccountInfo, error)
 	UpdateUserCredentials(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error)
+	GetAccountInfoByUsername(ctx context.Context, username string) (*api.AccountInfo, error)
+	RegisterSuccessfulLogin(ctx context.Context, id string, loginAt time.Time) error
+	UpdateFailedLoginState(ctx context.Context, previous *api.AccountInfo, updated *api.AccountInfo) (bool, error)
+	ResetPassword(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string, historySize int, updatedAt time.Time) (bool, error)
+	GetPasswordHistory(ctx context.Context, id string) ([]string, error)
+	UpdatePasswordHash(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string) (bool, error)
+	ChangePassword(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string, historySize int, updatedAt time.Time) (bool, error)
+	ChangeAccountStatus(ctx context.Context, id string, transition *model.StatusTransition) (bool, error)
+	FindExpiredSuspensions(ctx context.Context, now time.Time) ([]string, error)
+	DeleteAccountInfo(ctx context.Context, id string) error
 }
 
 type AccountInfoRepository struct {
@@ -57,6 +68,9 @@ func (r *AccountInfoRepository) CreateAccountInfo(ctx context.Context, accountIn
 
 	_, err = collection.InsertOne(ctx, dbAccountInfo)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "O nome de usuário já existe")
+		}
 		return nil, fmt.Errorf("falha ao inserir AccountInfo no banco de dados: %w", err)
 	}
 
@@ -81,23 +95,11 @@ func (r *AccountInfoRepository) GetAccountInfo(ctx context.Context, id string) (
 		return nil, fmt.Errorf("falha ao buscar AccountInfo do banco de dados: %w", err)
 	}
 
-	accountInfo := &api.AccountInfo{
-		UserId:                id,
-		Username:              dbAccountInfo.Username,
-		Password:              dbAccountInfo.Password,
-		AccountStatus:         api.AccountStatus(dbAccountInfo.AccountStatus),
-		StatusReason:          dbAccountInfo.StatusReason,
-		CreatedAt:             timestamppb.New(dbAccountInfo.CreatedAt),
-		UpdatedAt:             timestamppb.New(dbAccountInfo.UpdatedAt),
-		LastLogin:             timestamppb.New(dbAccountInfo.LastLogin),
-		FailedLoginAttempts:   dbAccountInfo.FailedLoginAttempts,
-		LastFailedLogin:       timestamppb.New(dbAccountInfo.LastFailedLogin),
-		LastFailedLoginReason: dbAccountInfo.LastFailedLoginReason,
-		AccountLockedUntil:    timestamppb.New(dbAccountInfo.AccountLockedUntil),
-		AccountLockedReason:   dbAccountInfo.AccountLockedReason,
+	if err := ensureUserNotDeleted(ctx, r.dbService, dbAccountInfo.UserId, "AccountInfo não encontrado"); err != nil {
+		return nil, err
 	}
 
-	return accountInfo, nil
+	return dbAccountInfo.ToProto(), nil
 }
 
 func (r *AccountInfoRepository) UpdateUserCredentials(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error) {
@@ -108,20 +110,347 @@ func (r *AccountInfoRepository) UpdateUserCredentials(ctx context.Context, accou
 		return nil, err
 	}
 
+	if err := ensureUserNotDeleted(ctx, r.dbService, userId, "AccountInfo não encontrado"); err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{"userId": userId}
 	update := bson.M{
 		"$set": bson.M{
-			"username": accountInfo.Username,
-			"password": accountInfo.Password,
+			"username":  accountInfo.Username,
+			"updatedAt": utils.TimestampToTime(accountInfo.UpdatedAt),
 		},
 	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
 
-	_, err = collection.UpdateOne(ctx, filter, update)
+	dbAccountInfo := &model.AccountInfo{}
+	err = collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(dbAccountInfo)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "AccountInfo não encontrado")
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "O nome de usuário já existe")
+		}
 		return nil, fmt.Errorf("falha ao atualizar UserCredentials no banco de dados: %w", err)
 	}
 
-	return accountInfo, nil
+	return dbAccountInfo.ToProto(), nil
+}
+
+func (r *AccountInfoRepository) GetAccountInfoByUsername(ctx context.Context, username string) (*api.AccountInfo, error) {
+	collection := r.getCollection()
+
+	filter := bson.M{"username": username}
+	opts := options.FindOne().SetCollation(config.CaseInsensitiveCollation())
+	dbAccountInfo := &model.AccountInfo{}
+	err := collection.FindOne(ctx, filter, opts).Decode(dbAccountInfo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "AccountInfo não encontrado")
+		}
+		return nil, fmt.Errorf("falha ao buscar AccountInfo do banco de dados: %w", err)
+	}
+
+	if err := ensureUserNotDeleted(ctx, r.dbService, dbAccountInfo.UserId, "AccountInfo não encontrado"); err != nil {
+		return nil, err
+	}
+
+	return dbAccountInfo.ToProto(), nil
+}
+
+func (r *AccountInfoRepository) RegisterSuccessfulLogin(ctx context.Context, id string, loginAt time.Time) error {
+	collection := r.getCollection()
+
+	userId, err := utils.ConvertToObjectId(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"userId": userId}
+	update := bson.M{
+		"$set": bson.M{
+			"lastLogin": loginAt,
+		},
+		"$unset": bson.M{
+			"failedLoginAttempts": "",
+			"accountLockedUntil":  "",
+			"accountLockedReason": "",
+		},
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("falha ao registrar login no banco de dados: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return status.Errorf(codes.NotFound, "AccountInfo não encontrado")
+	}
+
+	return nil
+}
+
+// UpdateFailedLoginState grava o novo estado de tentativas falhadas somente se o documento
+// ainda estiver no estado lido em previous, para que falhas concorrentes não ultrapassem o limite.
+func (r *AccountInfoRepository) UpdateFailedLoginState(ctx context.Context, previous *api.AccountInfo, updated *api.AccountInfo) (bool, error) {
+	collection := r.getCollection()
+
+	userId, err := utils.ConvertToObjectId(previous.UserId)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"userId":              userId,
+		"failedLoginAttempts": previous.FailedLoginAttempts,
+		"lastFailedLogin":     utils.TimestampToTime(previous.LastFailedLogin),
+	}
+	if previous.FailedLoginAttempts == 0 {
+		filter["failedLoginAttempts"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	if utils.TimestampToTime(previous.LastFailedLogin).IsZero() {
+		filter["lastFailedLogin"] = nil
+	}
+
+	set := bson.M{
+		"failedLoginAttempts":   updated.FailedLoginAttempts,
+		"lastFailedLogin":       utils.TimestampToTime(updated.LastFailedLogin),
+		"lastFailedLoginReason": updated.LastFailedLoginReason,
+	}
+	if lockedUntil := utils.TimestampToTime(updated.AccountLockedUntil); !lockedUntil.IsZero() {
+		set["accountLockedUntil"] = lockedUntil
+		set["accountLockedReason"] = updated.AccountLockedReason
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return false, fmt.Errorf("falha ao registrar tentativa de login falhada no banco de dados: %w", err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// ChangeAccountStatus aplica a transição e a acrescenta ao statusHistory, apenas se a conta ainda
+// estiver em transition.From. Retorna false se o status mudou desde a leitura. Como ACTIVE é o
+// valor zero, a volta para ACTIVE remove o status e a razão do documento.
+func (r *AccountInfoRepository) ChangeAccountStatus(ctx context.Context, id string, transition *model.StatusTransition) (bool, error) {
+	collection := r.getCollection()
+
+	userId, err := utils.ConvertToObjectId(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"userId": userId, "accountStatus": accountStatusFilter(transition.From)}
+
+	set := bson.M{"updatedAt": transition.ChangedAt}
+	unset := bson.M{}
+	if transition.To == model.AccountStatus_ACTIVE {
+		unset["accountStatus"] = ""
+		unset["statusReason"] = ""
+	} else {
+		set["accountStatus"] = transition.To
+		set["statusReason"] = transition.Reason
+	}
+	if transition.SuspendedUntil.IsZero() {
+		unset["suspendedUntil"] = ""
+	} else {
+		set["suspendedUntil"] = transition.SuspendedUntil
+	}
+
+	update := bson.M{
+		"$set":  set,
+		"$push": bson.M{"statusHistory": transition},
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("falha ao alterar o status da conta no banco de dados: %w", err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// FindExpiredSuspensions retorna os IDs das contas suspensas cuja suspensão terminou até now.
+func (r *AccountInfoRepository) FindExpiredSuspensions(ctx context.Context, now time.Time) ([]string, error) {
+	collection := r.getCollection()
+
+	filter := bson.M{
+		"accountStatus":  model.AccountStatus_SUSPENDED,
+		"suspendedUntil": bson.M{"$lte": now},
+	}
+	opts := options.Find().SetProjection(bson.M{"userId": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar as suspensões encerradas no banco de dados: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var ids []string
+	for cursor.Next(ctx) {
+		var dbAccountInfo model.AccountInfo
+		if err := cursor.Decode(&dbAccountInfo); err != nil {
+			return nil, fmt.Errorf("falha ao decodificar AccountInfo: %w", err)
+		}
+		ids = append(ids, dbAccountInfo.UserId.Hex())
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao percorrer as suspensões encerradas: %w", err)
+	}
+
+	return ids, nil
+}
+
+// accountStatusFilter compara o status considerando que ACTIVE, o valor zero, não é gravado por
+// causa do omitempty.
+func accountStatusFilter(accountStatus model.AccountStatus) interface{} {
+	if accountStatus == model.AccountStatus_ACTIVE {
+		return bson.M{"$in": bson.A{accountStatus, nil}}
+	}
+	return accountStatus
+}
+
+// ResetPassword grava a nova senha e desbloqueia a conta, já que o usuário provou ter acesso ao e-mail.
+// Assim como ChangePassword, guarda a senha anterior no histórico e só grava se a senha não tiver
+// mudado desde a leitura.
+func (r *AccountInfoRepository) ResetPassword(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string, historySize int, updatedAt time.Time) (bool, error) {
+	collection := r.getCollection()
+
+	userId, err := utils.ConvertToObjectId(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"userId": userId, "password": currentHashedPassword}
+	update := passwordChangeUpdate(currentHashedPassword, newHashedPassword, historySize, updatedAt, bson.M{
+		"failedLoginAttempts":   "",
+		"lastFailedLogin":       "",
+		"lastFailedLoginReason": "",
+		"accountLockedUntil":    "",
+		"accountLockedReason":   "",
+	})
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("falha ao redefinir a senha no banco de dados: %w", err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (r *AccountInfoRepository) GetPasswordHistory(ctx context.Context, id string) ([]string, error) {
+	collection := r.getCollection()
+
+	userId, err := utils.ConvertToObjectId(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"userId": userId}
+	opts := options.FindOne().SetProjection(bson.M{"passwordHistory": 1})
+	dbAccountInfo := &model.AccountInfo{}
+	err = collection.FindOne(ctx, filter, opts).Decode(dbAccountInfo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "AccountInfo não encontrado")
+		}
+		return nil, fmt.Errorf("falha ao buscar o histórico de senhas do banco de dados: %w", err)
+	}
+
+	return dbAccountInfo.PasswordHistory, nil
+}
+
+// ChangePassword troca a senha somente se o hash gravado ainda for currentHashedPassword, para que
+// duas trocas concorrentes não se sobrescrevam. O hash substituído entra no histórico, que mantém
+// apenas os historySize mais recentes. Retorna false se a senha mudou desde a leitura.
+func (r *AccountInfoRepository) ChangePassword(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string, historySize int, updatedAt time.Time) (bool, error) {
+	collection := r.getCollection()
+
+	userId, err := utils.ConvertToObjectId(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"userId": userId, "password": currentHashedPassword}
+	update := passwordChangeUpdate(currentHashedPassword, newHashedPassword, historySize, updatedAt, bson.M{})
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("falha ao alterar a senha no banco de dados: %w", err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// passwordChangeUpdate grava a nova senha e move a atual para o passwordHistory, mantendo apenas as
+// últimas historySize senhas. Os campos de unset também são removidos do documento.
+func passwordChangeUpdate(currentHashedPassword string, newHashedPassword string, historySize int, updatedAt time.Time, unset bson.M) bson.M {
+	update := bson.M{
+		"$set": bson.M{
+			"password":  newHashedPassword,
+			"updatedAt": updatedAt,
+		},
+	}
+	if historySize > 0 {
+		update["$push"] = bson.M{
+			"passwordHistory": bson.M{
+				"$each":  bson.A{currentHashedPassword},
+				"$slice": -historySize,
+			},
+		}
+	} else {
+		unset["passwordHistory"] = ""
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update
+}
+
+// UpdatePasswordHash troca o hash da mesma senha por um gerado com o algoritmo atual. Não mexe em
+// updatedAt nem no histórico, e só grava se a senha não tiver mudado desde a leitura.
+func (r *AccountInfoRepository) UpdatePasswordHash(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string) (bool, error) {
+	collection := r.getCollection()
+
+	userId, err := utils.ConvertToObjectId(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"userId": userId, "password": currentHashedPassword}
+	update := bson.M{"$set": bson.M{"password": newHashedPassword}}
+
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("falha ao atualizar o hash da senha no banco de dados: %w", err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (r *AccountInfoRepository) DeleteAccountInfo(ctx context.Context, id string) error {
+	collection := r.getCollection()
+
+	userId, err := utils.ConvertToObjectId(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"userId": userId}
+	result, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("falha ao remover AccountInfo do banco de dados: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return status.Errorf(codes.NotFound, "AccountInfo não encontrado")
+	}
+
+	return nil
 }
 
 func (r *AccountInfoRepository) getCollection() *mongo.Collection {
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IAuditRepository só acrescenta e lê eventos: a coleção audit_events é append-only e não há
+// operações de alteração ou remoção.
+type IAuditRepository interface {
+	GetLastAuditEvent(ctx context.Context) (*model.AuditEvent, error)
+	InsertAuditEvent(ctx context.Context, event *model.AuditEvent) error
+	ListAuditEvents(ctx context.Context, query ListAuditEventsQuery) ([]*model.AuditEvent, error)
+}
+
+// ListAuditEventsQuery descreve uma página de eventos, do mais recente para o mais antigo. Campos
+// vazios não filtram; BeforeSequence, quando informado, é a sequence do último evento da página
+// anterior.
+type ListAuditEventsQuery struct {
+	DocumentId     string
+	OccurredAfter  time.Time
+	OccurredBefore time.Time
+	BeforeSequence int64
+	Limit          int64
+}
+
+type AuditRepository struct {
+	dbService *config.DBService
+}
+
+func NewAuditRepository(dbService *config.DBService) IAuditRepository {
+	return &AuditRepository{
+		dbService: dbService,
+	}
+}
+
+// GetLastAuditEvent retorna o evento de maior sequence, ou nil se a coleção estiver vazia.
+func (r *AuditRepository) GetLastAuditEvent(ctx context.Context) (*model.AuditEvent, error) {
+	collection := r.getCollection()
+
+	event := &model.AuditEvent{}
+	opts := options.FindOne().SetSort(bson.D{{Key: "sequence", Value: -1}})
+	err := collection.FindOne(ctx, bson.M{}, opts).Decode(event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("falha ao buscar o último evento de auditoria: %w", err)
+	}
+
+	return event, nil
+}
+
+// InsertAuditEvent mantém o erro do driver encadeado, para que um conflito de sequence dentro de uma
+// transação seja repetido pelo WithTransaction.
+func (r *AuditRepository) InsertAuditEvent(ctx context.Context, event *model.AuditEvent) error {
+	collection := r.getCollection()
+
+	if event.Id.IsZero() {
+		event.Id = primitive.NewObjectID()
+	}
+
+	if _, err := collection.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("falha ao inserir o evento de auditoria %d: %w", event.Sequence, err)
+	}
+
+	return nil
+}
+
+func (r *AuditRepository) ListAuditEvents(ctx context.Context, query ListAuditEventsQuery) ([]*model.AuditEvent, error) {
+	collection := r.getCollection()
+
+	filter := bson.M{}
+	if query.DocumentId != "" {
+		filter["documentId"] = query.DocumentId
+	}
+
+	occurredAt := bson.M{}
+	if !query.OccurredAfter.IsZero() {
+		occurredAt["$gte"] = query.OccurredAfter
+	}
+	if !query.OccurredBefore.IsZero() {
+		occurredAt["$lt"] = query.OccurredBefore
+	}
+	if len(occurredAt) > 0 {
+		filter["occurredAt"] = occurredAt
+	}
+
+	if query.BeforeSequence > 0 {
+		filter["sequence"] = bson.M{"$lt": query.BeforeSequence}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "sequence", Value: -1}}).SetLimit(query.Limit)
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar os eventos de auditoria: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	events := []*model.AuditEvent{}
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar os eventos de auditoria: %w", err)
+	}
+
+	return events, nil
+}
+
+func (r *AuditRepository) getCollection() *mongo.Collection {
+	return r.dbService.Client.Database(r.dbService.DBName).Collection("audit_events")
+}
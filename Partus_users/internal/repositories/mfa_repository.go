@@ -0,0 +1,213 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type IMFARepository interface {
+	GetMFA(ctx context.Context, userId string) (*model.MFA, error)
+	SavePendingMFA(ctx context.Context, mfa *model.MFA) error
+	EnableMFA(ctx context.Context, userId string, recoveryCodeHashes []string, step int64, confirmedAt time.Time) (bool, error)
+	UseTOTPStep(ctx context.Context, userId string, step int64) (bool, error)
+	UseRecoveryCode(ctx context.Context, userId string, codeHash string) (bool, error)
+	DeleteMFA(ctx context.Context, userId string) error
+	CreateChallenge(ctx context.Context, challenge *model.MFAChallenge) error
+	GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*model.MFAChallenge, error)
+	IncrementChallengeAttempts(ctx context.Context, id primitive.ObjectID) error
+	DeleteChallenge(ctx context.Context, id primitive.ObjectID) (bool, error)
+}
+
+type MFARepository struct {
+	dbService *config.DBService
+}
+
+func NewMFARepository(dbService *config.DBService) IMFARepository {
+	return &MFARepository{
+		dbService: dbService,
+	}
+}
+
+func (r *MFARepository) GetMFA(ctx context.Context, userId string) (*model.MFA, error) {
+	objectID, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa := &model.MFA{}
+	err = r.getCollection().FindOne(ctx, bson.M{"userId": objectID}).Decode(mfa)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "MFA não configurado")
+		}
+		return nil, fmt.Errorf("falha ao buscar MFA no banco de dados: %w", err)
+	}
+
+	return mfa, nil
+}
+
+// SavePendingMFA grava um cadastro ainda não confirmado, substituindo um cadastro pendente anterior.
+// Um MFA já ativo não é substituído.
+func (r *MFARepository) SavePendingMFA(ctx context.Context, mfa *model.MFA) error {
+	filter := bson.M{"userId": mfa.UserId, "enabled": false}
+	update := bson.M{
+		"$set": bson.M{
+			"encryptedSecret": mfa.EncryptedSecret,
+			"enabled":         false,
+			"createdAt":       mfa.CreatedAt,
+		},
+		"$unset": bson.M{"recoveryCodeHashes": "", "lastUsedStep": "", "confirmedAt": ""},
+	}
+	_, err := r.getCollection().UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return status.Errorf(codes.FailedPrecondition, "A autenticação em duas etapas já está ativa")
+		}
+		return fmt.Errorf("falha ao salvar MFA no banco de dados: %w", err)
+	}
+
+	return nil
+}
+
+// EnableMFA ativa o cadastro pendente. Retorna false se ele não existir ou já estiver ativo.
+func (r *MFARepository) EnableMFA(ctx context.Context, userId string, recoveryCodeHashes []string, step int64, confirmedAt time.Time) (bool, error) {
+	objectID, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"userId": objectID, "enabled": false}
+	update := bson.M{"$set": bson.M{
+		"enabled":            true,
+		"recoveryCodeHashes": recoveryCodeHashes,
+		"lastUsedStep":       step,
+		"confirmedAt":        confirmedAt,
+	}}
+	result, err := r.getCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("falha ao ativar MFA no banco de dados: %w", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// UseTOTPStep registra o período aceito apenas se ele for posterior ao último usado, o que impede
+// a reutilização de um código mesmo em requisições simultâneas.
+func (r *MFARepository) UseTOTPStep(ctx context.Context, userId string, step int64) (bool, error) {
+	objectID, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{
+		"userId":  objectID,
+		"enabled": true,
+		"$or": bson.A{
+			bson.M{"lastUsedStep": bson.M{"$exists": false}},
+			bson.M{"lastUsedStep": bson.M{"$lt": step}},
+		},
+	}
+	result, err := r.getCollection().UpdateOne(ctx, filter, bson.M{"$set": bson.M{"lastUsedStep": step}})
+	if err != nil {
+		return false, fmt.Errorf("falha ao registrar o código TOTP no banco de dados: %w", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// UseRecoveryCode remove o código da lista. Retorna false se ele não existir ou já tiver sido usado.
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userId string, codeHash string) (bool, error) {
+	objectID, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return false, err
+	}
+
+	filter := bson.M{"userId": objectID, "enabled": true, "recoveryCodeHashes": codeHash}
+	update := bson.M{"$pull": bson.M{"recoveryCodeHashes": codeHash}}
+	result, err := r.getCollection().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("falha ao usar o código de recuperação no banco de dados: %w", err)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+func (r *MFARepository) DeleteMFA(ctx context.Context, userId string) error {
+	objectID, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.getCollection().DeleteOne(ctx, bson.M{"userId": objectID}); err != nil {
+		return fmt.Errorf("falha ao remover MFA do banco de dados: %w", err)
+	}
+
+	if _, err := r.getChallengeCollection().DeleteMany(ctx, bson.M{"userId": objectID}); err != nil {
+		return fmt.Errorf("falha ao remover os desafios de MFA do banco de dados: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MFARepository) CreateChallenge(ctx context.Context, challenge *model.MFAChallenge) error {
+	if challenge.Id.IsZero() {
+		challenge.Id = primitive.NewObjectID()
+	}
+
+	if _, err := r.getChallengeCollection().InsertOne(ctx, challenge); err != nil {
+		return fmt.Errorf("falha ao inserir desafio de MFA no banco de dados: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MFARepository) GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*model.MFAChallenge, error) {
+	challenge := &model.MFAChallenge{}
+	err := r.getChallengeCollection().FindOne(ctx, bson.M{"tokenHash": tokenHash}).Decode(challenge)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "Desafio de MFA não encontrado")
+		}
+		return nil, fmt.Errorf("falha ao buscar desafio de MFA no banco de dados: %w", err)
+	}
+
+	return challenge, nil
+}
+
+func (r *MFARepository) IncrementChallengeAttempts(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.getChallengeCollection().UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$inc": bson.M{"attempts": 1}})
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar desafio de MFA no banco de dados: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteChallenge retorna false se o desafio já tiver sido consumido por outra requisição.
+func (r *MFARepository) DeleteChallenge(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	result, err := r.getChallengeCollection().DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return false, fmt.Errorf("falha ao remover desafio de MFA do banco de dados: %w", err)
+	}
+
+	return result.DeletedCount == 1, nil
+}
+
+func (r *MFARepository) getCollection() *mongo.Collection {
+	return r.dbService.Client.Database(r.dbService.DBName).Collection("mfa")
+}
+
+func (r *MFARepository) getChallengeCollection() *mongo.Collection {
+	return r.dbService.Client.Database(r.dbService.DBName).Collection("mfa_challenges")
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IOutboxRepository interface {
+	InsertOutboxEvent(ctx context.Context, event *model.OutboxEvent) error
+	ClaimNextOutboxEvent(ctx context.Context, now time.Time, leaseUntil time.Time) (*model.OutboxEvent, error)
+	MarkOutboxEventPublished(ctx context.Context, id primitive.ObjectID, publishedAt time.Time, expiresAt time.Time) error
+	MarkOutboxEventFailed(ctx context.Context, id primitive.ObjectID, attempts int32, nextAttemptAt time.Time, lastError string) error
+}
+
+type OutboxRepository struct {
+	dbService *config.DBService
+}
+
+func NewOutboxRepository(dbService *config.DBService) IOutboxRepository {
+	return &OutboxRepository{
+		dbService: dbService,
+	}
+}
+
+func (r *OutboxRepository) InsertOutboxEvent(ctx context.Context, event *model.OutboxEvent) error {
+	collection := r.getCollection()
+
+	if event.Id.IsZero() {
+		event.Id = primitive.NewObjectID()
+	}
+
+	if _, err := collection.InsertOne(ctx, event); err != nil {
+		return fmt.Errorf("falha ao inserir o evento na outbox: %w", err)
+	}
+
+	return nil
+}
+
+// ClaimNextOutboxEvent reserva o evento pendente mais antigo adiando NextAttemptAt para leaseUntil,
+// o que o esconde dos outros relays enquanto é publicado. Retorna nil quando não há eventos prontos.
+func (r *OutboxRepository) ClaimNextOutboxEvent(ctx context.Context, now time.Time, leaseUntil time.Time) (*model.OutboxEvent, error) {
+	collection := r.getCollection()
+
+	filter := bson.M{"publishedAt": bson.M{"$exists": false}, "nextAttemptAt": bson.M{"$lte": now}}
+	update := bson.M{"$set": bson.M{"nextAttemptAt": leaseUntil}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	event := &model.OutboxEvent{}
+	err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(event)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("falha ao reservar o evento da outbox: %w", err)
+	}
+
+	return event, nil
+}
+
+func (r *OutboxRepository) MarkOutboxEventPublished(ctx context.Context, id primitive.ObjectID, publishedAt time.Time, expiresAt time.Time) error {
+	collection := r.getCollection()
+
+	update := bson.M{
+		"$set":   bson.M{"publishedAt": publishedAt, "expiresAt": expiresAt},
+		"$unset": bson.M{"lastError": ""},
+	}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("falha ao marcar o evento da outbox como publicado: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) MarkOutboxEventFailed(ctx context.Context, id primitive.ObjectID, attempts int32, nextAttemptAt time.Time, lastError string) error {
+	collection := r.getCollection()
+
+	update := bson.M{"$set": bson.M{"attempts": attempts, "nextAttemptAt": nextAttemptAt, "lastError": lastError}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": id}, update); err != nil {
+		return fmt.Errorf("falha ao registrar a falha de publicação do evento da outbox: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OutboxRepository) getCollection() *mongo.Collection {
+	return r.dbService.Client.Database(r.dbService.DBName).Collection("outbox")
+}
@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type IPasswordResetRepository interface {
+	CreatePasswordReset(ctx context.Context, reset *model.PasswordReset) error
+	ConsumePasswordReset(ctx context.Context, tokenHash string, now time.Time) (*model.PasswordReset, error)
+	InvalidateUserPasswordResets(ctx context.Context, userId string, now time.Time) error
+	DeleteUserPasswordResets(ctx context.Context, userId string) error
+}
+
+type PasswordResetRepository struct {
+	dbService *config.DBService
+}
+
+func NewPasswordResetRepository(dbService *config.DBService) IPasswordResetRepository {
+	return &PasswordResetRepository{
+		dbService: dbService,
+	}
+}
+
+func (r *PasswordResetRepository) CreatePasswordReset(ctx context.Context, reset *model.PasswordReset) error {
+	collection := r.getCollection()
+
+	if reset.Id.IsZero() {
+		reset.Id = primitive.NewObjectID()
+	}
+
+	if _, err := collection.InsertOne(ctx, reset); err != nil {
+		return fmt.Errorf("falha ao inserir pedido de redefinição de senha no banco de dados: %w", err)
+	}
+
+	return nil
+}
+
+// ConsumePasswordReset marca o token como usado na mesma operação em que o encontra, para que duas
+// requisições simultâneas não redefinam a senha com o mesmo token. Retorna NotFound se o token não
+// existir, já tiver sido usado ou estiver expirado.
+func (r *PasswordResetRepository) ConsumePasswordReset(ctx context.Context, tokenHash string, now time.Time) (*model.PasswordReset, error) {
+	collection := r.getCollection()
+
+	filter := bson.M{
+		"tokenHash": tokenHash,
+		"usedAt":    bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+	}
+	update := bson.M{"$set": bson.M{"usedAt": now}}
+
+	reset := &model.PasswordReset{}
+	err := collection.FindOneAndUpdate(ctx, filter, update).Decode(reset)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "Pedido de redefinição de senha não encontrado")
+		}
+		return nil, fmt.Errorf("falha ao consumir pedido de redefinição de senha no banco de dados: %w", err)
+	}
+
+	return reset, nil
+}
+
+// InvalidateUserPasswordResets marca como usados todos os tokens ainda válidos do usuário.
+func (r *PasswordResetRepository) InvalidateUserPasswordResets(ctx context.Context, userId string, now time.Time) error {
+	collection := r.getCollection()
+
+	objectID, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"userId": objectID, "usedAt": bson.M{"$exists": false}}
+	if _, err := collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"usedAt": now}}); err != nil {
+		return fmt.Errorf("falha ao invalidar pedidos de redefinição de senha no banco de dados: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUserPasswordResets remove todos os pedidos do usuário, usados ou não. É usado na remoção
+// definitiva do usuário, que não espera o TTL dos pedidos.
+func (r *PasswordResetRepository) DeleteUserPasswordResets(ctx context.Context, userId string) error {
+	collection := r.getCollection()
+
+	objectID, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return err
+	}
+
+	if _, err := collection.DeleteMany(ctx, bson.M{"userId": objectID}); err != nil {
+		return fmt.Errorf("falha ao remover os pedidos de redefinição de senha do banco de dados: %w", err)
+	}
+
+	return nil
+}
+
+func (r *PasswordResetRepository) getCollection() *mongo.Collection {
+	return r.dbService.Client.Database(r.dbService.DBName).Collection("password_resets")
+}
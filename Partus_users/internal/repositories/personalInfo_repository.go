@@ -3,6 +3,7 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jonh-dev/partus_users/api"
 	"github.com/jonh-dev/partus_users/internal/config"
@@ -10,6 +11,7 @@ import (
 	"github.com/jonh-dev/partus_users/internal/utils"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -18,8 +20,11 @@ import (
 type IPersonalInfoRepository interface {
 	CreatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error)
 	GetPersonalInfo(ctx context.Context, id string) (*api.PersonalInfo, error)
-	UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error)
+	GetPersonalInfoByEmail(ctx context.Context, email string) (*api.PersonalInfo, error)
+	UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo, fields []string) (*api.PersonalInfo, error)
+	MarkEmailVerified(ctx context.Context, id string, email string, verifiedAt time.Time) (bool, error)
 	DoesEmailExist(ctx context.Context, email string) (bool, error)
+	DeletePersonalInfo(ctx context.Context, id string) error
 }
 
 type PersonalInfoRepository struct {
@@ -48,10 +53,14 @@ func (r *PersonalInfoRepository) CreatePersonalInfo(ctx context.Context, persona
 		BirthDate:    personalInfo.BirthDate.AsTime(),
 		Phone:        personalInfo.Phone,
 		ProfileImage: personalInfo.ProfileImage,
+		TimeZone:     personalInfo.TimeZone,
 	}
 
 	_, err = collection.InsertOne(ctx, dbPersonalInfo)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "O e-mail já existe")
+		}
 		return nil, fmt.Errorf("falha ao inserir PersonalInfo no banco de dados: %w", err)
 	}
 
@@ -76,6 +85,10 @@ func (r *PersonalInfoRepository) GetPersonalInfo(ctx context.Context, id string)
 		return nil, fmt.Errorf("falha ao buscar PersonalInfo do banco de dados: %w", err)
 	}
 
+	if err := ensureUserNotDeleted(ctx, r.dbService, userId, "PersonalInfo não encontrado"); err != nil {
+		return nil, err
+	}
+
 	personalInfo := &api.PersonalInfo{
 		UserId:       id,
 		FirstName:    dbPersonalInfo.FirstName,
@@ -84,12 +97,42 @@ func (r *PersonalInfoRepository) GetPersonalInfo(ctx context.Context, id string)
 		BirthDate:    timestamppb.New(dbPersonalInfo.BirthDate),
 		Phone:        dbPersonalInfo.Phone,
 		ProfileImage: dbPersonalInfo.ProfileImage,
+		TimeZone:     dbPersonalInfo.TimeZone,
+	}
+	if !dbPersonalInfo.EmailVerifiedAt.IsZero() {
+		personalInfo.EmailVerifiedAt = timestamppb.New(dbPersonalInfo.EmailVerifiedAt)
 	}
 
 	return personalInfo, nil
 }
 
-func (r *PersonalInfoRepository) UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error) {
+// GetPersonalInfoByEmail busca o e-mail sem diferenciar maiúsculas de minúsculas, usando o índice
+// único de email.
+func (r *PersonalInfoRepository) GetPersonalInfoByEmail(ctx context.Context, email string) (*api.PersonalInfo, error) {
+	collection := r.getCollection()
+
+	filter := bson.M{"email": email}
+	opts := options.FindOne().SetCollation(config.CaseInsensitiveCollation())
+	dbPersonalInfo := &model.PersonalInfo{}
+	err := collection.FindOne(ctx, filter, opts).Decode(dbPersonalInfo)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "PersonalInfo não encontrado")
+		}
+		return nil, fmt.Errorf("falha ao buscar PersonalInfo do banco de dados: %w", err)
+	}
+
+	if err := ensureUserNotDeleted(ctx, r.dbService, dbPersonalInfo.UserId, "PersonalInfo não encontrado"); err != nil {
+		return nil, err
+	}
+
+	return dbPersonalInfo.ToProto(), nil
+}
+
+// UpdatePersonalInfo altera somente os campos informados em fields. Campos vazios são
+// removidos do documento com $unset em vez de gravados como valores em branco. Alterar o e-mail
+// desfaz a verificação do e-mail anterior.
+func (r *PersonalInfoRepository) UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo, fields []string) (*api.PersonalInfo, error) {
 	collection := r.getCollection()
 
 	userId, err := utils.ConvertToObjectId(personalInfo.UserId)
@@ -97,41 +140,133 @@ func (r *PersonalInfoRepository) UpdatePersonalInfo(ctx context.Context, persona
 		return nil, err
 	}
 
+	values := map[string]interface{}{
+		"firstName":    personalInfo.FirstName,
+		"lastName":     personalInfo.LastName,
+		"email":        personalInfo.Email,
+		"birthDate":    utils.TimestampToTime(personalInfo.BirthDate),
+		"phone":        personalInfo.Phone,
+		"profileImage": personalInfo.ProfileImage,
+		"timeZone":     personalInfo.TimeZone,
+	}
+
+	set := bson.M{}
+	unset := bson.M{}
+	for _, field := range fields {
+		value, ok := values[field]
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Campo de PersonalInfo desconhecido: %s", field)
+		}
+
+		if isEmptyValue(value) {
+			unset[field] = ""
+		} else {
+			set[field] = value
+		}
+
+		if field == "email" {
+			unset["emailVerifiedAt"] = ""
+		}
+	}
+
+	update := bson.M{}
+	if len(set) > 0 {
+		update["$set"] = set
+	}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	if len(update) == 0 {
+		return r.GetPersonalInfo(ctx, personalInfo.UserId)
+	}
+
+	if err := ensureUserNotDeleted(ctx, r.dbService, userId, "PersonalInfo não encontrado"); err != nil {
+		return nil, err
+	}
+
 	filter := bson.M{"userId": userId}
-	update := bson.M{
-		"$set": bson.M{
-			"firstName":    personalInfo.FirstName,
-			"lastName":     personalInfo.LastName,
-			"email":        personalInfo.Email,
-			"birthDate":    personalInfo.BirthDate.AsTime(),
-			"phone":        personalInfo.Phone,
-			"profileImage": personalInfo.ProfileImage,
-		},
-	}
-
-	_, err = collection.UpdateOne(ctx, filter, update)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	dbPersonalInfo := &model.PersonalInfo{}
+	err = collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(dbPersonalInfo)
 	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "PersonalInfo não encontrado")
+		}
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "O e-mail já existe")
+		}
 		return nil, fmt.Errorf("falha ao atualizar PersonalInfo no banco de dados: %w", err)
 	}
 
-	return personalInfo, nil
+	return dbPersonalInfo.ToProto(), nil
 }
 
-func (r *PersonalInfoRepository) DoesEmailExist(ctx context.Context, email string) (bool, error) {
+// MarkEmailVerified registra a verificação apenas se o e-mail atual ainda for o e-mail verificado.
+// Retorna false quando o e-mail mudou depois que o token foi emitido.
+func (r *PersonalInfoRepository) MarkEmailVerified(ctx context.Context, id string, email string, verifiedAt time.Time) (bool, error) {
 	collection := r.getCollection()
 
-	filter := bson.M{"email": email}
-	err := collection.FindOne(ctx, filter).Err()
+	userId, err := utils.ConvertToObjectId(id)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
+		return false, err
+	}
+
+	filter := bson.M{"userId": userId, "email": email}
+	update := bson.M{"$set": bson.M{"emailVerifiedAt": verifiedAt}}
+	opts := options.Update().SetCollation(config.CaseInsensitiveCollation())
+	result, err := collection.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return false, fmt.Errorf("falha ao registrar a verificação do e-mail no banco de dados: %w", err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// DoesEmailExist não considera os e-mails de usuários removidos.
+func (r *PersonalInfoRepository) DoesEmailExist(ctx context.Context, email string) (bool, error) {
+	_, err := r.GetPersonalInfoByEmail(ctx, email)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
 			return false, nil
 		}
-		return false, fmt.Errorf("falha ao buscar PersonalInfo do banco de dados: %w", err)
+		return false, err
 	}
 
 	return true, nil
 }
 
+func (r *PersonalInfoRepository) DeletePersonalInfo(ctx context.Context, id string) error {
+	collection := r.getCollection()
+
+	userId, err := utils.ConvertToObjectId(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"userId": userId}
+	result, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("falha ao remover PersonalInfo do banco de dados: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return status.Errorf(codes.NotFound, "PersonalInfo não encontrado")
+	}
+
+	return nil
+}
+
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == ""
+	case time.Time:
+		return v.IsZero()
+	default:
+		return value == nil
+	}
+}
+
 func (r *PersonalInfoRepository) getCollection() *mongo.Collection {
 	return r.dbService.Client.Database(r.dbService.DBName).Collection("personal_info")
 }
@@ -0,0 +1,202 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/utils"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type ISessionRepository interface {
+	CreateSession(ctx context.Context, session *model.Session) (*model.Session, error)
+	GetSession(ctx context.Context, id string) (*model.Session, error)
+	GetSessionByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error)
+	ListActiveSessions(ctx context.Context, userId string, now time.Time) ([]*model.Session, error)
+	RotateSessionToken(ctx context.Context, id string, currentHash string, newHash string, client model.SessionClient, seenAt time.Time) (bool, error)
+	RevokeSession(ctx context.Context, id string, reason string, revokedAt time.Time) error
+	RevokeUserSessions(ctx context.Context, userId string, exceptSessionId string, reason string, revokedAt time.Time) (int64, error)
+}
+
+type SessionRepository struct {
+	dbService *config.DBService
+}
+
+func NewSessionRepository(dbService *config.DBService) ISessionRepository {
+	return &SessionRepository{
+		dbService: dbService,
+	}
+}
+
+func (r *SessionRepository) CreateSession(ctx context.Context, session *model.Session) (*model.Session, error) {
+	collection := r.getCollection()
+
+	if session.Id.IsZero() {
+		session.Id = primitive.NewObjectID()
+	}
+
+	_, err := collection.InsertOne(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao inserir sessão no banco de dados: %w", err)
+	}
+
+	return session, nil
+}
+
+func (r *SessionRepository) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	collection := r.getCollection()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "ID de sessão inválido: %v", err)
+	}
+
+	session := &model.Session{}
+	err = collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "Sessão não encontrada")
+		}
+		return nil, fmt.Errorf("falha ao buscar sessão no banco de dados: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetSessionByTokenHash encontra a sessão tanto pelo token atual quanto por um token já trocado.
+func (r *SessionRepository) GetSessionByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error) {
+	collection := r.getCollection()
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"tokenHash": tokenHash},
+		bson.M{"usedTokenHashes": tokenHash},
+	}}
+	session := &model.Session{}
+	err := collection.FindOne(ctx, filter).Decode(session)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "Sessão não encontrada")
+		}
+		return nil, fmt.Errorf("falha ao buscar sessão no banco de dados: %w", err)
+	}
+
+	return session, nil
+}
+
+// ListActiveSessions retorna as sessões não revogadas e não expiradas do usuário, da mais recente
+// para a mais antiga.
+func (r *SessionRepository) ListActiveSessions(ctx context.Context, userId string, now time.Time) ([]*model.Session, error) {
+	collection := r.getCollection()
+
+	objectID, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"userId":    objectID,
+		"revokedAt": bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": now},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "lastSeenAt", Value: -1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar sessões no banco de dados: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*model.Session{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar sessões: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// RotateSessionToken troca o token atual por newHash apenas se currentHash ainda for o token atual
+// e a sessão não tiver sido revogada. Retorna false quando outra requisição trocou o token antes.
+func (r *SessionRepository) RotateSessionToken(ctx context.Context, id string, currentHash string, newHash string, client model.SessionClient, seenAt time.Time) (bool, error) {
+	collection := r.getCollection()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, status.Errorf(codes.InvalidArgument, "ID de sessão inválido: %v", err)
+	}
+
+	filter := bson.M{"_id": objectID, "tokenHash": currentHash, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{
+		"$set": bson.M{
+			"tokenHash":  newHash,
+			"lastSeenAt": seenAt,
+			"device":     client.Device,
+			"ipAddress":  client.IpAddress,
+			"userAgent":  client.UserAgent,
+		},
+		"$push": bson.M{"usedTokenHashes": currentHash},
+	}
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, fmt.Errorf("falha ao renovar sessão no banco de dados: %w", err)
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+// RevokeSession encerra a sessão. Revogar uma sessão já revogada não altera o motivo original.
+func (r *SessionRepository) RevokeSession(ctx context.Context, id string, reason string, revokedAt time.Time) error {
+	collection := r.getCollection()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "ID de sessão inválido: %v", err)
+	}
+
+	filter := bson.M{"_id": objectID, "revokedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"revokedAt": revokedAt, "revokedReason": reason}}
+	_, err = collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("falha ao revogar sessão no banco de dados: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeUserSessions revoga todas as sessões ativas do usuário, exceto exceptSessionId quando
+// informado, e retorna quantas foram revogadas.
+func (r *SessionRepository) RevokeUserSessions(ctx context.Context, userId string, exceptSessionId string, reason string, revokedAt time.Time) (int64, error) {
+	collection := r.getCollection()
+
+	objectID, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return 0, err
+	}
+
+	filter := bson.M{"userId": objectID, "revokedAt": bson.M{"$exists": false}}
+	if exceptSessionId != "" {
+		exceptId, err := primitive.ObjectIDFromHex(exceptSessionId)
+		if err != nil {
+			return 0, status.Errorf(codes.InvalidArgument, "ID de sessão inválido: %v", err)
+		}
+		filter["_id"] = bson.M{"$ne": exceptId}
+	}
+
+	update := bson.M{"$set": bson.M{"revokedAt": revokedAt, "revokedReason": reason}}
+	result, err := collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao revogar sessões no banco de dados: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (r *SessionRepository) getCollection() *mongo.Collection {
+	return r.dbService.Client.Database(r.dbService.DBName).Collection("sessions")
+}
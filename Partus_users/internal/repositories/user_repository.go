@@ -3,17 +3,57 @@ package repositories
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"time"
 
 	"github.com/jonh-dev/partus_users/internal/config"
 	"github.com/jonh-dev/partus_users/internal/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type IUserRepository interface {
 	CreateUser(ctx context.Context, user *model.User) (*model.User, error)
 	GetUser(ctx context.Context, id string) (*model.User, error)
+	ListUsers(ctx context.Context, query ListUsersQuery) ([]*model.User, error)
+	SoftDeleteUser(ctx context.Context, id string, deletedAt time.Time) error
+	RestoreUser(ctx context.Context, id string) error
+	FindDeletedUserIds(ctx context.Context, deletedBefore time.Time) ([]string, error)
+	PurgeUser(ctx context.Context, id string, deletedBefore time.Time) error
+}
+
+// UserSortField é o caminho, no documento retornado por ListUsers, do campo usado na ordenação.
+type UserSortField string
+
+const (
+	UserSortById        UserSortField = "_id"
+	UserSortByCreatedAt UserSortField = "accountInfo.createdAt"
+	UserSortByEmail     UserSortField = "personalInfo.email"
+	UserSortByLastName  UserSortField = "personalInfo.lastName"
+)
+
+// ListUsersQuery descreve uma página de ListUsers. Campos vazios não filtram. After, quando
+// informado, é o último usuário da página anterior: SortValue é o valor do campo de ordenação e
+// LastId o _id, que desempata valores iguais.
+type ListUsersQuery struct {
+	AccountStatuses []model.AccountStatus
+	CreatedAfter    time.Time
+	CreatedBefore   time.Time
+	EmailDomain     string
+	NamePrefix      string
+	SortField       UserSortField
+	Descending      bool
+	After           *UserCursor
+	Limit           int64
+}
+
+type UserCursor struct {
+	SortValue interface{}
+	LastId    primitive.ObjectID
 }
 
 type UserRepository struct {
@@ -31,6 +71,9 @@ func (r *UserRepository) CreateUser(ctx context.Context, user *model.User) (*mod
 
 	_, err := collection.InsertOne(ctx, user)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, status.Errorf(codes.AlreadyExists, "O usuário já existe")
+		}
 		return nil, fmt.Errorf("falha ao inserir usuário no banco de dados: %w", err)
 	}
 
@@ -43,17 +86,329 @@ func (r *UserRepository) GetUser(ctx context.Context, id string) (*model.User, e
 	var user model.User
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.InvalidArgument, "ID de usuário inválido: %v", err)
 	}
-	filter := bson.M{"_id": objectID}
+	filter := bson.M{"_id": objectID, "deletedAt": bson.M{"$exists": false}}
 	err = collection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
-		return nil, err
+		if err == mongo.ErrNoDocuments {
+			return nil, status.Errorf(codes.NotFound, "Usuário não encontrado")
+		}
+		return nil, fmt.Errorf("falha ao buscar usuário do banco de dados: %w", err)
 	}
 
 	return &user, nil
 }
 
+// listUsersSource é a coleção em que ListUsers começa: a dona do campo de ordenação. Os filtros
+// dessa coleção e a ordenação usam os índices dela, e os $lookup nas demais coleções só são feitos
+// para os documentos que chegam até o $limit.
+type listUsersSource struct {
+	collection string
+	// embedAs é o campo de User em que o documento da coleção é colocado; vazio para users.
+	embedAs   string
+	sortField string
+	idField   string
+}
+
+var listUsersSources = map[UserSortField]listUsersSource{
+	UserSortById:        {collection: "users", sortField: "_id", idField: "_id"},
+	UserSortByCreatedAt: {collection: "account_info", embedAs: "accountInfo", sortField: "createdAt", idField: "userId"},
+	UserSortByEmail:     {collection: "personal_info", embedAs: "personalInfo", sortField: "email", idField: "userId"},
+	UserSortByLastName:  {collection: "personal_info", embedAs: "personalInfo", sortField: "lastName", idField: "userId"},
+}
+
+// ListUsers aplica filtros, ordenação e paginação por cursor sobre os usuários ativos, com os dados
+// atualizados de personal_info e account_info.
+func (r *UserRepository) ListUsers(ctx context.Context, query ListUsersQuery) ([]*model.User, error) {
+	sortField := query.SortField
+	if sortField == "" {
+		sortField = UserSortById
+	}
+	source, ok := listUsersSources[sortField]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Campo de ordenação inválido: %s", sortField)
+	}
+
+	direction := 1
+	comparison := "$gt"
+	if query.Descending {
+		direction = -1
+		comparison = "$lt"
+	}
+
+	personalInfoConditions := listUsersPersonalInfoConditions(query)
+	accountInfoConditions := listUsersAccountInfoConditions(query)
+
+	// Filtros e cursor sobre os campos da coleção de origem, antes de qualquer $lookup.
+	sourceConditions := bson.A{}
+	switch source.collection {
+	case "users":
+		sourceConditions = append(sourceConditions, bson.M{"deletedAt": bson.M{"$exists": false}})
+	case "personal_info":
+		sourceConditions = append(sourceConditions, personalInfoConditions...)
+		personalInfoConditions = nil
+	case "account_info":
+		sourceConditions = append(sourceConditions, accountInfoConditions...)
+		accountInfoConditions = nil
+	}
+	if query.After != nil {
+		if source.sortField == source.idField {
+			sourceConditions = append(sourceConditions, bson.M{source.idField: bson.M{comparison: query.After.LastId}})
+		} else {
+			sourceConditions = append(sourceConditions, bson.M{"$or": bson.A{
+				bson.M{source.sortField: bson.M{comparison: query.After.SortValue}},
+				bson.M{source.sortField: query.After.SortValue, source.idField: bson.M{comparison: query.After.LastId}},
+			}})
+		}
+	}
+
+	sort := bson.D{{Key: source.sortField, Value: direction}}
+	if source.sortField != source.idField {
+		sort = append(sort, bson.E{Key: source.idField, Value: direction})
+	}
+
+	pipeline := mongo.Pipeline{}
+	if len(sourceConditions) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$and": sourceConditions}}})
+	}
+	pipeline = append(pipeline, bson.D{{Key: "$sort", Value: sort}})
+
+	if source.embedAs != "" {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$replaceWith", Value: bson.M{"_id": "$userId", source.embedAs: "$$ROOT"}}},
+			bson.D{{Key: "$lookup", Value: bson.M{"from": "users", "localField": "_id", "foreignField": "_id", "as": "user"}}},
+			bson.D{{Key: "$match", Value: bson.M{"user": bson.M{"$size": 1}, "user.deletedAt": bson.M{"$exists": false}}}},
+		)
+	}
+	if source.embedAs != "personalInfo" {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{"from": "personal_info", "localField": "_id", "foreignField": "userId", "as": "personalInfo"}}},
+			bson.D{{Key: "$unwind", Value: "$personalInfo"}},
+		)
+	}
+	if source.embedAs != "accountInfo" {
+		pipeline = append(pipeline,
+			bson.D{{Key: "$lookup", Value: bson.M{"from": "account_info", "localField": "_id", "foreignField": "userId", "as": "accountInfo"}}},
+			bson.D{{Key: "$unwind", Value: "$accountInfo"}},
+		)
+	}
+
+	joinedConditions := append(prefixConditions(personalInfoConditions, "personalInfo."), prefixConditions(accountInfoConditions, "accountInfo.")...)
+	if len(joinedConditions) > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$match", Value: bson.M{"$and": joinedConditions}}})
+	}
+
+	pipeline = append(pipeline,
+		bson.D{{Key: "$limit", Value: query.Limit}},
+		bson.D{{Key: "$project", Value: bson.M{"accountInfo.password": 0, "user": 0}}},
+	)
+
+	collection := r.dbService.Client.Database(r.dbService.DBName).Collection(source.collection)
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar usuários no banco de dados: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	users := []*model.User{}
+	if err := cursor.All(ctx, &users); err != nil {
+		return nil, fmt.Errorf("falha ao decodificar usuários: %w", err)
+	}
+
+	return users, nil
+}
+
+// listUsersPersonalInfoConditions retorna os filtros de ListUsers sobre os campos de personal_info.
+func listUsersPersonalInfoConditions(query ListUsersQuery) bson.A {
+	conditions := bson.A{}
+
+	if query.EmailDomain != "" {
+		pattern := "@" + regexp.QuoteMeta(query.EmailDomain) + "$"
+		conditions = append(conditions, bson.M{"email": primitive.Regex{Pattern: pattern, Options: "i"}})
+	}
+
+	if query.NamePrefix != "" {
+		pattern := primitive.Regex{Pattern: "^" + regexp.QuoteMeta(query.NamePrefix), Options: "i"}
+		conditions = append(conditions, bson.M{"$or": bson.A{
+			bson.M{"firstName": pattern},
+			bson.M{"lastName": pattern},
+		}})
+	}
+
+	return conditions
+}
+
+// listUsersAccountInfoConditions retorna os filtros de ListUsers sobre os campos de account_info.
+func listUsersAccountInfoConditions(query ListUsersQuery) bson.A {
+	conditions := bson.A{}
+
+	if len(query.AccountStatuses) > 0 {
+		statuses := bson.A{}
+		for _, accountStatus := range query.AccountStatuses {
+			statuses = append(statuses, accountStatus)
+			// ACTIVE é o valor zero e não é gravado por causa do omitempty.
+			if accountStatus == model.AccountStatus_ACTIVE {
+				statuses = append(statuses, nil)
+			}
+		}
+		conditions = append(conditions, bson.M{"accountStatus": bson.M{"$in": statuses}})
+	}
+
+	createdAt := bson.M{}
+	if !query.CreatedAfter.IsZero() {
+		createdAt["$gte"] = query.CreatedAfter
+	}
+	if !query.CreatedBefore.IsZero() {
+		createdAt["$lt"] = query.CreatedBefore
+	}
+	if len(createdAt) > 0 {
+		conditions = append(conditions, bson.M{"createdAt": createdAt})
+	}
+
+	return conditions
+}
+
+// prefixConditions reescreve os filtros de uma coleção para os campos embutidos em User, como
+// "email" para "personalInfo.email".
+func prefixConditions(conditions bson.A, prefix string) bson.A {
+	prefixed := bson.A{}
+	for _, condition := range conditions {
+		prefixed = append(prefixed, prefixCondition(condition.(bson.M), prefix))
+	}
+	return prefixed
+}
+
+func prefixCondition(condition bson.M, prefix string) bson.M {
+	prefixed := bson.M{}
+	for key, value := range condition {
+		if key == "$or" {
+			alternatives := bson.A{}
+			for _, alternative := range value.(bson.A) {
+				alternatives = append(alternatives, prefixCondition(alternative.(bson.M), prefix))
+			}
+			prefixed[key] = alternatives
+			continue
+		}
+		prefixed[prefix+key] = value
+	}
+	return prefixed
+}
+
+func (r *UserRepository) SoftDeleteUser(ctx context.Context, id string, deletedAt time.Time) error {
+	collection := r.getCollection()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "ID de usuário inválido: %v", err)
+	}
+
+	filter := bson.M{"_id": objectID, "deletedAt": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"deletedAt": deletedAt}}
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("falha ao marcar usuário como removido no banco de dados: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return status.Errorf(codes.NotFound, "Usuário não encontrado")
+	}
+
+	return nil
+}
+
+func (r *UserRepository) RestoreUser(ctx context.Context, id string) error {
+	collection := r.getCollection()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "ID de usuário inválido: %v", err)
+	}
+
+	filter := bson.M{"_id": objectID, "deletedAt": bson.M{"$exists": true}}
+	update := bson.M{"$unset": bson.M{"deletedAt": ""}}
+	result, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("falha ao restaurar usuário no banco de dados: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return status.Errorf(codes.NotFound, "Usuário removido não encontrado")
+	}
+
+	return nil
+}
+
+func (r *UserRepository) FindDeletedUserIds(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	collection := r.getCollection()
+
+	filter := bson.M{"deletedAt": bson.M{"$lte": deletedBefore}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar usuários removidos no banco de dados: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var ids []string
+	for cursor.Next(ctx) {
+		var user model.User
+		if err := cursor.Decode(&user); err != nil {
+			return nil, fmt.Errorf("falha ao decodificar usuário removido: %w", err)
+		}
+		ids = append(ids, user.Id.Hex())
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao percorrer usuários removidos: %w", err)
+	}
+
+	return ids, nil
+}
+
+// PurgeUser remove definitivamente o usuário, desde que ele tenha sido marcado como removido
+// antes de deletedBefore. Assim um usuário restaurado durante a limpeza não é apagado.
+func (r *UserRepository) PurgeUser(ctx context.Context, id string, deletedBefore time.Time) error {
+	collection := r.getCollection()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "ID de usuário inválido: %v", err)
+	}
+
+	filter := bson.M{"_id": objectID, "deletedAt": bson.M{"$lte": deletedBefore}}
+	result, err := collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("falha ao remover usuário do banco de dados: %w", err)
+	}
+
+	if result.DeletedCount == 0 {
+		return status.Errorf(codes.NotFound, "Usuário removido não encontrado")
+	}
+
+	return nil
+}
+
+// ensureUserNotDeleted retorna NotFound com message quando o usuário foi marcado como removido.
+// Apenas users guarda deletedAt, então personal_info e account_info consultam users para esconder os
+// dados de usuários removidos. Um usuário sem documento em users, como durante a criação ou depois
+// da remoção definitiva, não é considerado removido.
+func ensureUserNotDeleted(ctx context.Context, dbService *config.DBService, userId primitive.ObjectID, message string) error {
+	collection := dbService.Client.Database(dbService.DBName).Collection("users")
+
+	filter := bson.M{"_id": userId, "deletedAt": bson.M{"$exists": true}}
+	count, err := collection.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return fmt.Errorf("falha ao verificar a remoção do usuário no banco de dados: %w", err)
+	}
+
+	if count > 0 {
+		return status.Error(codes.NotFound, message)
+	}
+
+	return nil
+}
+
 func (r *UserRepository) getCollection() *mongo.Collection {
 	return r.dbService.Client.Database(r.dbService.DBName).Collection("users")
 }
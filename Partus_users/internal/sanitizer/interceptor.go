@@ -0,0 +1,20 @@
+package sanitizer
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// UnaryServerInterceptor apaga os campos sensíveis de toda resposta antes de enviá-la. É a última
+// barreira: os serviços já montam as respostas com as visões de saída, sem os segredos.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if message, ok := resp.(proto.Message); ok {
+			Scrub(message)
+		}
+		return resp, err
+	}
+}
@@ -0,0 +1,55 @@
+package sanitizer
+
+import (
+	"github.com/jonh-dev/partus_users/api"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// IsSensitive indica se o campo está marcado com a opção (api.sensitive) no proto.
+func IsSensitive(field protoreflect.FieldDescriptor) bool {
+	options := field.Options()
+	if options == nil {
+		return false
+	}
+	sensitive, _ := proto.GetExtension(options, api.E_Sensitive).(bool)
+	return sensitive
+}
+
+// Scrub apaga, em toda a árvore da mensagem, os campos marcados como sensíveis. A mensagem é
+// alterada no lugar.
+func Scrub(message proto.Message) {
+	if message == nil {
+		return
+	}
+	scrubMessage(message.ProtoReflect())
+}
+
+func scrubMessage(message protoreflect.Message) {
+	if !message.IsValid() {
+		return
+	}
+
+	message.Range(func(field protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+		if IsSensitive(field) {
+			message.Clear(field)
+			return true
+		}
+
+		switch {
+		case field.IsList() && field.Message() != nil:
+			list := value.List()
+			for i := 0; i < list.Len(); i++ {
+				scrubMessage(list.Get(i).Message())
+			}
+		case field.IsMap() && field.MapValue().Message() != nil:
+			value.Map().Range(func(_ protoreflect.MapKey, entry protoreflect.Value) bool {
+				scrubMessage(entry.Message())
+				return true
+			})
+		case field.Message() != nil && !field.IsList() && !field.IsMap():
+			scrubMessage(value.Message())
+		}
+		return true
+	})
+}
@@ -2,37 +2,75 @@ package services
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/audit"
+	"github.com/jonh-dev/partus_users/internal/config"
 	"github.com/jonh-dev/partus_users/internal/encryption"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/passwordpolicy"
 	"github.com/jonh-dev/partus_users/internal/repositories"
 	"github.com/jonh-dev/partus_users/internal/utils"
 	"github.com/jonh-dev/partus_users/internal/validation"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+const maxFailedLoginUpdateRetries = 5
+
+// PasswordChecker recusa senhas vazadas, comuns ou fáceis de adivinhar. Ver passwordpolicy.Checker.
+type PasswordChecker interface {
+	Check(password string, userInputs ...string) error
+}
+
 type IAccountInfoService interface {
 	CreateAccountInfo(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error)
 	GetAccountInfo(ctx context.Context, req *api.GetAccountInfoRequest) (*api.AccountInfo, error)
+	GetAccountInfoByUsername(ctx context.Context, username string) (*api.AccountInfo, error)
 	UpdateUserCredentials(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error)
+	Authenticate(ctx context.Context, username string, password string) (*api.AccountInfo, error)
+	RegisterSuccessfulLogin(ctx context.Context, userId string) error
+	RegisterFailedMFA(ctx context.Context, userId string) error
+	VerifyPassword(ctx context.Context, userId string, password string) error
+	ChangePassword(ctx context.Context, userId string, currentPassword string, newPassword string) error
+	ChangeAccountStatus(ctx context.Context, req *api.ChangeAccountStatusRequest) (*api.AccountInfo, error)
+	RegisterFailedLogin(ctx context.Context, username string, reason string) (*api.AccountInfo, error)
+	DeleteAccountInfo(ctx context.Context, req *api.DeleteAccountInfoRequest) error
 }
 
 type AccountInfoService struct {
 	accountInfoRepo   repositories.IAccountInfoRepository
 	passwordEncryptor encryption.PasswordEncryptor
+	passwordChecker   PasswordChecker
+	lockoutPolicy     *config.LockoutPolicy
+	passwordPolicy    *config.PasswordPolicy
+	sessionRevoker    SessionRevoker
+	txRunner          config.TransactionRunner
 }
 
-func NewAccountInfoService(accountInfoRepo repositories.IAccountInfoRepository, passwordEncryptor encryption.PasswordEncryptor) *AccountInfoService {
-	return &AccountInfoService{accountInfoRepo: accountInfoRepo, passwordEncryptor: passwordEncryptor}
+func NewAccountInfoService(accountInfoRepo repositories.IAccountInfoRepository, passwordEncryptor encryption.PasswordEncryptor, passwordChecker PasswordChecker, lockoutPolicy *config.LockoutPolicy, passwordPolicy *config.PasswordPolicy, sessionRevoker SessionRevoker, txRunner config.TransactionRunner) *AccountInfoService {
+	return &AccountInfoService{accountInfoRepo: accountInfoRepo, passwordEncryptor: passwordEncryptor, passwordChecker: passwordChecker, lockoutPolicy: lockoutPolicy, passwordPolicy: passwordPolicy, sessionRevoker: sessionRevoker, txRunner: txRunner}
 }
 
+// CreateAccountInfo ignora os campos de status, bloqueio e histórico enviados pelo cliente: toda
+// conta nova fica pendente até o e-mail ser verificado.
 func (s *AccountInfoService) CreateAccountInfo(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error) {
+	resetServerOwnedFields(accountInfo)
+
 	err := validation.ValidateAccountInfo(accountInfo, validation.Create, nil)
 	if err != nil {
 		log.Printf("Erro ao validar AccountInfo: %v", err)
-		return nil, status.Errorf(codes.InvalidArgument, "Erro ao validar AccountInfo: %v", err)
+		return nil, validation.ToStatus("Erro ao validar AccountInfo", err)
+	}
+
+	if err := checkPasswordStrength(s.passwordChecker, "password", accountInfo.Password, accountInfo.Username); err != nil {
+		return nil, err
 	}
 
 	encryptedPassword, err := s.passwordEncryptor.EncryptPassword(accountInfo.Password)
@@ -41,40 +79,590 @@ func (s *AccountInfoService) CreateAccountInfo(ctx context.Context, accountInfo
 		return nil, status.Errorf(codes.Internal, "Erro ao criptografar a senha: %v", err)
 	}
 	accountInfo.Password = encryptedPassword
-	now := utils.GetCurrentTimestamp()
-	accountInfo.CreatedAt = utils.AdjustToSaoPaulo(now)
+	accountInfo.CreatedAt = utils.GetCurrentTimestamp()
 
 	createdAccountInfo, err := s.accountInfoRepo.CreateAccountInfo(ctx, accountInfo)
 	if err != nil {
 		log.Printf("Erro ao criar AccountInfo: %v", err)
+		if status.Code(err) == codes.AlreadyExists {
+			return nil, err
+		}
 		return nil, status.Errorf(codes.Internal, "Erro ao criar AccountInfo: %v", err)
 	}
 
 	return createdAccountInfo, nil
 }
 
+// resetServerOwnedFields deixa em accountInfo apenas o que o cliente pode escolher ao criar a conta.
+func resetServerOwnedFields(accountInfo *api.AccountInfo) {
+	accountInfo.AccountStatus = api.AccountStatus_PENDING
+	accountInfo.StatusReason = pendingEmailVerificationReason
+	accountInfo.CreatedAt = nil
+	accountInfo.UpdatedAt = nil
+	accountInfo.LastLogin = nil
+	accountInfo.FailedLoginAttempts = 0
+	accountInfo.LastFailedLogin = nil
+	accountInfo.LastFailedLoginReason = ""
+	accountInfo.AccountLockedUntil = nil
+	accountInfo.AccountLockedReason = ""
+	accountInfo.SuspendedUntil = nil
+	accountInfo.StatusHistory = nil
+}
+
 func (s *AccountInfoService) GetAccountInfo(ctx context.Context, req *api.GetAccountInfoRequest) (*api.AccountInfo, error) {
 	accountInfo, err := s.accountInfoRepo.GetAccountInfo(ctx, req.UserId)
 	if err != nil {
 		log.Printf("Erro ao obter AccountInfo: %v", err)
+		if status.Code(err) == codes.NotFound {
+			return nil, err
+		}
 		return nil, status.Errorf(codes.Internal, "Erro ao obter AccountInfo: %v", err)
 	}
 
 	return accountInfo, nil
 }
 
-func (s *AccountInfoService) UpdateUserCredentials(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error) {
-	err := validation.ValidateAccountInfo(accountInfo, validation.Update, nil)
+func (s *AccountInfoService) GetAccountInfoByUsername(ctx context.Context, username string) (*api.AccountInfo, error) {
+	accountInfo, err := s.accountInfoRepo.GetAccountInfoByUsername(ctx, strings.TrimSpace(username))
 	if err != nil {
+		log.Printf("Erro ao obter AccountInfo por username: %v", err)
+		if status.Code(err) == codes.NotFound {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "Erro ao obter AccountInfo: %v", err)
+	}
+
+	return accountInfo, nil
+}
+
+// UpdateUserCredentials altera apenas o username. A senha não é aceita aqui: ela só muda por
+// ChangePassword, que exige a senha atual e consulta o histórico.
+func (s *AccountInfoService) UpdateUserCredentials(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error) {
+	if accountInfo.Password != "" {
+		return nil, status.Errorf(codes.InvalidArgument, "A senha não pode ser alterada por UpdateAccountInfo; use ChangePassword")
+	}
+
+	if err := validation.ValidateUsername("username", accountInfo.Username); err != nil {
 		log.Printf("Erro ao validar AccountInfo: %v", err)
-		return nil, status.Errorf(codes.InvalidArgument, "Erro ao validar AccountInfo: %v", err)
+		return nil, validation.ToStatus("Erro ao validar AccountInfo", err)
+	}
+
+	if _, err := s.GetAccountInfo(ctx, &api.GetAccountInfoRequest{UserId: accountInfo.UserId}); err != nil {
+		return nil, err
 	}
 
-	updatedAccountInfo, err := s.accountInfoRepo.UpdateUserCredentials(ctx, accountInfo)
+	updatedAccountInfo, err := s.accountInfoRepo.UpdateUserCredentials(ctx, &api.AccountInfo{
+		UserId:    accountInfo.UserId,
+		Username:  accountInfo.Username,
+		UpdatedAt: utils.GetCurrentTimestamp(),
+	})
 	if err != nil {
 		log.Printf("Erro ao atualizar AccountInfo: %v", err)
+		if code := status.Code(err); code == codes.AlreadyExists || code == codes.NotFound {
+			return nil, err
+		}
 		return nil, status.Errorf(codes.Internal, "Erro ao atualizar AccountInfo: %v", err)
 	}
 
 	return updatedAccountInfo, nil
 }
+
+// VerifyPassword confirma a senha de um usuário já autenticado antes de operações sensíveis. Como
+// no login, a conta bloqueada é recusada e a senha errada conta para o bloqueio, para que um token
+// roubado não permita adivinhar a senha sem limite.
+func (s *AccountInfoService) VerifyPassword(ctx context.Context, userId string, password string) error {
+	accountInfo, err := s.GetAccountInfo(ctx, &api.GetAccountInfoRequest{UserId: userId})
+	if err != nil {
+		return err
+	}
+
+	if err := checkAccountLock(accountInfo, time.Now()); err != nil {
+		return err
+	}
+
+	validPassword, err := s.passwordEncryptor.VerifyPassword(accountInfo.Password, password)
+	if err != nil {
+		log.Printf("Erro ao verificar a senha: %v", err)
+		return status.Errorf(codes.Internal, "Erro ao verificar a senha: %v", err)
+	}
+	if !validPassword {
+		updatedAccountInfo, err := s.recordFailedLogin(ctx, accountInfo, "senha inválida na reautenticação")
+		if err != nil {
+			return err
+		}
+		if err := checkAccountLock(updatedAccountInfo, time.Now()); err != nil {
+			return err
+		}
+		return status.Errorf(codes.Unauthenticated, "Senha inválida")
+	}
+
+	return nil
+}
+
+// ChangePassword exige a senha atual e recusa a nova senha se ela for igual à atual ou a alguma
+// das senhas guardadas no histórico. As outras sessões do usuário são encerradas em seguida.
+func (s *AccountInfoService) ChangePassword(ctx context.Context, userId string, currentPassword string, newPassword string) error {
+	if err := validation.ValidatePassword("newPassword", newPassword); err != nil {
+		return validation.ToStatus("Erro ao validar a senha", err)
+	}
+
+	accountInfo, err := s.GetAccountInfo(ctx, &api.GetAccountInfoRequest{UserId: userId})
+	if err != nil {
+		return err
+	}
+
+	validPassword, err := s.passwordEncryptor.VerifyPassword(accountInfo.Password, currentPassword)
+	if err != nil {
+		log.Printf("Erro ao verificar a senha: %v", err)
+		return status.Errorf(codes.Internal, "Erro ao verificar a senha: %v", err)
+	}
+	if !validPassword {
+		return status.Errorf(codes.Unauthenticated, "Senha atual inválida")
+	}
+
+	if err := checkPasswordStrength(s.passwordChecker, "newPassword", newPassword, accountInfo.Username); err != nil {
+		return err
+	}
+
+	if err := checkPasswordReuse(ctx, s.accountInfoRepo, s.passwordEncryptor, s.passwordPolicy, accountInfo, newPassword); err != nil {
+		return err
+	}
+
+	encryptedPassword, err := s.passwordEncryptor.EncryptPassword(newPassword)
+	if err != nil {
+		log.Printf("Erro ao criptografar a senha: %v", err)
+		return status.Errorf(codes.Internal, "Erro ao criptografar a senha: %v", err)
+	}
+
+	changed, err := s.accountInfoRepo.ChangePassword(ctx, userId, accountInfo.Password, encryptedPassword, s.passwordPolicy.HistorySize, time.Now())
+	if err != nil {
+		log.Printf("Erro ao alterar a senha: %v", err)
+		return status.Errorf(codes.Internal, "Erro ao alterar a senha: %v", err)
+	}
+	if !changed {
+		return status.Errorf(codes.Aborted, "A senha foi alterada por outra operação; tente novamente")
+	}
+
+	if _, err := s.sessionRevoker.RevokeOtherSessions(ctx, userId); err != nil {
+		log.Printf("Erro ao revogar as sessões após a troca de senha: %v", err)
+		return status.Errorf(codes.Internal, "A senha foi alterada, mas houve erro ao encerrar as outras sessões: %v", err)
+	}
+
+	log.Printf("Senha alterada para o usuário %s", userId)
+	return nil
+}
+
+// checkPasswordReuse recusa a nova senha se ela for igual à senha atual de accountInfo ou a alguma
+// das senhas guardadas no histórico.
+func checkPasswordReuse(ctx context.Context, accountInfoRepo repositories.IAccountInfoRepository, passwordEncryptor encryption.PasswordEncryptor, policy *config.PasswordPolicy, accountInfo *api.AccountInfo, newPassword string) error {
+	passwordHistory, err := accountInfoRepo.GetPasswordHistory(ctx, accountInfo.UserId)
+	if err != nil {
+		log.Printf("Erro ao obter o histórico de senhas: %v", err)
+		if status.Code(err) == codes.NotFound {
+			return err
+		}
+		return status.Errorf(codes.Internal, "Erro ao obter o histórico de senhas: %v", err)
+	}
+
+	for _, hashedPassword := range append([]string{accountInfo.Password}, passwordHistory...) {
+		reused, err := passwordEncryptor.VerifyPassword(hashedPassword, newPassword)
+		if err != nil {
+			log.Printf("Erro ao comparar a senha com o histórico: %v", err)
+			return status.Errorf(codes.Internal, "Erro ao comparar a senha com o histórico: %v", err)
+		}
+		if reused {
+			reuseErr := fmt.Errorf("a nova senha não pode ser igual à senha atual nem às últimas %d senhas", policy.HistorySize)
+			return validation.ToStatus("Senha recusada", validation.NewFieldError("newPassword", validation.ReasonPasswordReused, reuseErr))
+		}
+	}
+
+	return nil
+}
+
+// checkPasswordStrength converte a recusa da política em InvalidArgument com o motivo para o
+// usuário, apontando field como o campo inválido.
+func checkPasswordStrength(checker PasswordChecker, field string, password string, userInputs ...string) error {
+	err := checker.Check(password, userInputs...)
+	if err == nil {
+		return nil
+	}
+
+	var rejection *passwordpolicy.Rejection
+	if errors.As(err, &rejection) {
+		return validation.ToStatus("Senha recusada", validation.NewFieldError(field, rejection.Code, rejection))
+	}
+
+	log.Printf("Erro ao verificar a força da senha: %v", err)
+	return status.Errorf(codes.Internal, "Erro ao verificar a força da senha: %v", err)
+}
+
+// Authenticate verifica apenas a senha, o bloqueio e o status da conta. O contador de falhas só é
+// zerado por RegisterSuccessfulLogin, depois que todos os fatores do login foram verificados.
+func (s *AccountInfoService) Authenticate(ctx context.Context, username string, password string) (*api.AccountInfo, error) {
+	accountInfo, err := s.accountInfoRepo.GetAccountInfoByUsername(ctx, username)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.Unauthenticated, "Usuário ou senha inválidos")
+		}
+		log.Printf("Erro ao obter AccountInfo: %v", err)
+		return nil, status.Errorf(codes.Internal, "Erro ao obter AccountInfo: %v", err)
+	}
+
+	if err := checkAccountLock(accountInfo, time.Now()); err != nil {
+		return nil, err
+	}
+
+	validPassword, err := s.passwordEncryptor.VerifyPassword(accountInfo.Password, password)
+	if err != nil {
+		log.Printf("Erro ao verificar a senha: %v", err)
+		return nil, status.Errorf(codes.Internal, "Erro ao verificar a senha: %v", err)
+	}
+
+	if !validPassword {
+		updatedAccountInfo, err := s.recordFailedLogin(ctx, accountInfo, "senha inválida")
+		if err != nil {
+			return nil, err
+		}
+		if err := checkAccountLock(updatedAccountInfo, time.Now()); err != nil {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "Usuário ou senha inválidos")
+	}
+
+	// Uma suspensão temporária vencida é encerrada aqui, sem esperar o SuspensionReactivator.
+	if _, err := s.reactivateIfSuspensionExpired(ctx, accountInfo, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := checkAccountStatusForLogin(accountInfo); err != nil {
+		return nil, err
+	}
+
+	if s.passwordEncryptor.NeedsRehash(accountInfo.Password) {
+		s.rehashPassword(ctx, accountInfo, password)
+	}
+
+	return accountInfo, nil
+}
+
+// RegisterSuccessfulLogin registra o login e zera o contador de falhas e o bloqueio da conta.
+func (s *AccountInfoService) RegisterSuccessfulLogin(ctx context.Context, userId string) error {
+	err := s.accountInfoRepo.RegisterSuccessfulLogin(ctx, userId, utils.GetCurrentTimestamp().AsTime())
+	if err != nil {
+		log.Printf("Erro ao registrar login: %v", err)
+		return status.Errorf(codes.Internal, "Erro ao registrar login: %v", err)
+	}
+
+	return nil
+}
+
+// RegisterFailedMFA conta um código MFA errado como uma tentativa de login falhada, para que a
+// senha correta não permita testar códigos sem limite. Retorna o erro de bloqueio quando a
+// tentativa bloqueia a conta.
+func (s *AccountInfoService) RegisterFailedMFA(ctx context.Context, userId string) error {
+	accountInfo, err := s.GetAccountInfo(ctx, &api.GetAccountInfoRequest{UserId: userId})
+	if err != nil {
+		return err
+	}
+
+	updatedAccountInfo, err := s.recordFailedLogin(ctx, accountInfo, "código MFA inválido")
+	if err != nil {
+		return err
+	}
+
+	return checkAccountLock(updatedAccountInfo, time.Now())
+}
+
+// rehashPassword regrava o hash com o algoritmo atual aproveitando a senha recebida no login.
+// Falhas só são registradas no log: o hash antigo continua válido e a troca é tentada no próximo login.
+func (s *AccountInfoService) rehashPassword(ctx context.Context, accountInfo *api.AccountInfo, password string) {
+	hashedPassword, err := s.passwordEncryptor.EncryptPassword(password)
+	if err != nil {
+		log.Printf("Erro ao gerar o novo hash da senha do usuário %s: %v", accountInfo.UserId, err)
+		return
+	}
+
+	updated, err := s.accountInfoRepo.UpdatePasswordHash(ctx, accountInfo.UserId, accountInfo.Password, hashedPassword)
+	if err != nil {
+		log.Printf("Erro ao atualizar o hash da senha do usuário %s: %v", accountInfo.UserId, err)
+		return
+	}
+	if updated {
+		accountInfo.Password = hashedPassword
+	}
+}
+
+func (s *AccountInfoService) RegisterFailedLogin(ctx context.Context, username string, reason string) (*api.AccountInfo, error) {
+	if reason == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "A razão da tentativa de login falhada não pode estar vazia")
+	}
+
+	accountInfo, err := s.accountInfoRepo.GetAccountInfoByUsername(ctx, username)
+	if err != nil {
+		log.Printf("Erro ao obter AccountInfo: %v", err)
+		if status.Code(err) == codes.NotFound {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "Erro ao obter AccountInfo: %v", err)
+	}
+
+	return s.recordFailedLogin(ctx, accountInfo, reason)
+}
+
+func (s *AccountInfoService) recordFailedLogin(ctx context.Context, accountInfo *api.AccountInfo, reason string) (*api.AccountInfo, error) {
+	for attempt := 0; attempt < maxFailedLoginUpdateRetries; attempt++ {
+		updatedAccountInfo := applyFailedLogin(s.lockoutPolicy, accountInfo, reason, utils.GetCurrentTimestamp().AsTime())
+
+		updated, err := s.accountInfoRepo.UpdateFailedLoginState(ctx, accountInfo, updatedAccountInfo)
+		if err != nil {
+			log.Printf("Erro ao registrar tentativa de login falhada: %v", err)
+			return nil, status.Errorf(codes.Internal, "Erro ao registrar tentativa de login falhada: %v", err)
+		}
+
+		if updated {
+			if isAccountLocked(updatedAccountInfo, time.Now()) && !isAccountLocked(accountInfo, time.Now()) {
+				log.Printf("Conta %s bloqueada até %s: %s", updatedAccountInfo.UserId, updatedAccountInfo.AccountLockedUntil.AsTime().Format(time.RFC3339), updatedAccountInfo.AccountLockedReason)
+			}
+			return updatedAccountInfo, nil
+		}
+
+		accountInfo, err = s.accountInfoRepo.GetAccountInfo(ctx, accountInfo.UserId)
+		if err != nil {
+			log.Printf("Erro ao recarregar AccountInfo: %v", err)
+			return nil, status.Errorf(codes.Internal, "Erro ao recarregar AccountInfo: %v", err)
+		}
+	}
+
+	return nil, status.Errorf(codes.Aborted, "Não foi possível registrar a tentativa de login falhada devido a atualizações concorrentes")
+}
+
+// applyFailedLogin calcula o novo estado de bloqueio. A janela de contagem é ancorada na última
+// falha ou no fim do último bloqueio, o que for mais recente, para que a duração continue
+// crescendo quando as falhas recomeçam logo após um bloqueio expirar.
+func applyFailedLogin(policy *config.LockoutPolicy, accountInfo *api.AccountInfo, reason string, now time.Time) *api.AccountInfo {
+	lastFailedLogin := utils.TimestampToTime(accountInfo.LastFailedLogin)
+	lockedUntil := utils.TimestampToTime(accountInfo.AccountLockedUntil)
+
+	windowStart := lastFailedLogin
+	if lockedUntil.After(windowStart) {
+		windowStart = lockedUntil
+	}
+
+	failedLoginAttempts := accountInfo.FailedLoginAttempts
+	if windowStart.IsZero() || now.Sub(windowStart) > policy.Window {
+		failedLoginAttempts = 0
+	}
+	failedLoginAttempts++
+
+	updatedAccountInfo := &api.AccountInfo{
+		UserId:                accountInfo.UserId,
+		Username:              accountInfo.Username,
+		AccountStatus:         accountInfo.AccountStatus,
+		StatusReason:          accountInfo.StatusReason,
+		FailedLoginAttempts:   failedLoginAttempts,
+		LastFailedLogin:       timestamppb.New(now),
+		LastFailedLoginReason: reason,
+		AccountLockedUntil:    accountInfo.AccountLockedUntil,
+		AccountLockedReason:   accountInfo.AccountLockedReason,
+	}
+
+	if failedLoginAttempts >= policy.MaxFailedAttempts {
+		updatedAccountInfo.AccountLockedUntil = timestamppb.New(now.Add(policy.LockDuration(failedLoginAttempts)))
+		updatedAccountInfo.AccountLockedReason = fmt.Sprintf("%d tentativas de login falhadas consecutivas", failedLoginAttempts)
+	}
+
+	return updatedAccountInfo
+}
+
+func isAccountLocked(accountInfo *api.AccountInfo, now time.Time) bool {
+	return utils.TimestampToTime(accountInfo.AccountLockedUntil).After(now)
+}
+
+func checkAccountLock(accountInfo *api.AccountInfo, now time.Time) error {
+	if !isAccountLocked(accountInfo, now) {
+		return nil
+	}
+	return status.Errorf(codes.ResourceExhausted, "A conta está bloqueada até %s: %s", accountInfo.AccountLockedUntil.AsTime().Format(time.RFC3339), accountInfo.AccountLockedReason)
+}
+
+// ChangeAccountStatus aplica uma transição permitida do status da conta e a registra no
+// statusHistory. Exige um administrador, exceto nas chamadas internas (ver statusChangeActor). A
+// ativação de uma conta pendente fica com VerifyEmail. Suspender ou desativar a conta encerra todas
+// as sessões do usuário.
+func (s *AccountInfoService) ChangeAccountStatus(ctx context.Context, req *api.ChangeAccountStatusRequest) (*api.AccountInfo, error) {
+	if req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O ID do usuário é obrigatório")
+	}
+
+	actor, err := statusChangeActor(ctx, req.Actor)
+	if err != nil {
+		log.Printf("Mudança de status recusada para a conta %s: %v", req.UserId, err)
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := validation.ValidateStatusChange(req, actor, now); err != nil {
+		log.Printf("Erro ao validar a mudança de status: %v", err)
+		return nil, validation.ToStatus("Erro ao validar a mudança de status", err)
+	}
+
+	accountInfo, err := s.GetAccountInfo(ctx, &api.GetAccountInfoRequest{UserId: req.UserId})
+	if err != nil {
+		return nil, err
+	}
+
+	transition := &model.StatusTransition{
+		From:           model.AccountStatus(accountInfo.AccountStatus),
+		To:             model.AccountStatus(req.AccountStatus),
+		Reason:         strings.TrimSpace(req.Reason),
+		Actor:          actor,
+		ChangedAt:      now,
+		SuspendedUntil: utils.TimestampToTime(req.SuspendedUntil),
+	}
+	if transition.From == model.AccountStatus_PENDING && transition.To == model.AccountStatus_ACTIVE {
+		return nil, status.Errorf(codes.FailedPrecondition, "A conta pendente só é ativada pela verificação do e-mail")
+	}
+	// As sessões são revogadas na mesma transação para que uma conta suspensa ou desativada não
+	// continue com tokens de renovação válidos.
+	err = s.txRunner.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.applyStatusTransition(ctx, req.UserId, transition); err != nil {
+			return err
+		}
+		if transition.To != model.AccountStatus_SUSPENDED && transition.To != model.AccountStatus_INACTIVE {
+			return nil
+		}
+		if _, err := s.sessionRevoker.RevokeAllUserSessions(ctx, req.UserId, "status da conta alterado para "+transition.To.String()); err != nil {
+			log.Printf("Erro ao revogar as sessões na mudança de status: %v", err)
+			return status.Errorf(codes.Internal, "Erro ao encerrar as sessões: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetAccountInfo(ctx, &api.GetAccountInfoRequest{UserId: req.UserId})
+}
+
+// statusChangeActor autoriza a mudança de status e retorna o responsável por ela. Pelo gRPC, só um
+// access token de administrador pode mudar o status, e o responsável é o usuário do token. O actor
+// do pedido só é aceito nas chamadas internas, que não passam pelo interceptor de auditoria.
+func statusChangeActor(ctx context.Context, requestActor string) (string, error) {
+	metadata := audit.MetadataFromContext(ctx)
+	switch {
+	case metadata.Actor == audit.SystemActor:
+		return strings.TrimSpace(requestActor), nil
+	case metadata.Actor == audit.AnonymousActor:
+		return "", status.Errorf(codes.Unauthenticated, "Token de acesso ausente ou inválido")
+	case !metadata.HasRole(model.RoleAdmin):
+		return "", status.Errorf(codes.PermissionDenied, "Apenas administradores podem alterar o status da conta")
+	case strings.TrimSpace(requestActor) != "":
+		return "", status.Errorf(codes.InvalidArgument, "O actor só pode ser informado por chamadas internas")
+	}
+	return metadata.Actor, nil
+}
+
+// applyStatusTransition grava a transição se ela for permitida. A gravação é condicionada ao status
+// lido, para que duas mudanças concorrentes não pulem uma regra da máquina de estados.
+func (s *AccountInfoService) applyStatusTransition(ctx context.Context, userId string, transition *model.StatusTransition) error {
+	if !model.CanTransition(transition.From, transition.To) {
+		return status.Errorf(codes.FailedPrecondition, "A conta não pode passar de %s para %s", transition.From, transition.To)
+	}
+
+	changed, err := s.accountInfoRepo.ChangeAccountStatus(ctx, userId, transition)
+	if err != nil {
+		log.Printf("Erro ao alterar o status da conta: %v", err)
+		return status.Errorf(codes.Internal, "Erro ao alterar o status da conta: %v", err)
+	}
+	if !changed {
+		return status.Errorf(codes.Aborted, "O status da conta mudou durante a alteração; tente novamente")
+	}
+
+	log.Printf("Status da conta %s alterado de %s para %s por %s: %s", userId, transition.From, transition.To, transition.Actor, transition.Reason)
+	return nil
+}
+
+// ReactivateExpiredSuspensions devolve a ACTIVE as contas cuja suspensão terminou até now. Falhas
+// individuais são registradas e não interrompem as demais.
+func (s *AccountInfoService) ReactivateExpiredSuspensions(ctx context.Context, now time.Time) (int, error) {
+	ids, err := s.accountInfoRepo.FindExpiredSuspensions(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	reactivated := 0
+	for _, id := range ids {
+		accountInfo, err := s.accountInfoRepo.GetAccountInfo(ctx, id)
+		if err != nil {
+			log.Printf("Erro ao obter a conta suspensa %s: %v", id, err)
+			continue
+		}
+
+		ok, err := s.reactivateIfSuspensionExpired(ctx, accountInfo, now)
+		if err != nil {
+			log.Printf("Erro ao reativar a conta suspensa %s: %v", id, err)
+			continue
+		}
+		if ok {
+			reactivated++
+		}
+	}
+
+	return reactivated, nil
+}
+
+// reactivateIfSuspensionExpired encerra a suspensão temporária vencida e atualiza accountInfo.
+// Retorna false se a conta não estiver em uma suspensão vencida.
+func (s *AccountInfoService) reactivateIfSuspensionExpired(ctx context.Context, accountInfo *api.AccountInfo, now time.Time) (bool, error) {
+	suspendedUntil := utils.TimestampToTime(accountInfo.SuspendedUntil)
+	if accountInfo.AccountStatus != api.AccountStatus_SUSPENDED || suspendedUntil.IsZero() || suspendedUntil.After(now) {
+		return false, nil
+	}
+
+	transition := &model.StatusTransition{
+		From:      model.AccountStatus_SUSPENDED,
+		To:        model.AccountStatus_ACTIVE,
+		Reason:    "Suspensão encerrada em " + suspendedUntil.UTC().Format(time.RFC3339),
+		Actor:     audit.SystemActor,
+		ChangedAt: now,
+	}
+	if err := s.applyStatusTransition(ctx, accountInfo.UserId, transition); err != nil {
+		return false, err
+	}
+
+	accountInfo.AccountStatus = api.AccountStatus_ACTIVE
+	accountInfo.StatusReason = ""
+	accountInfo.SuspendedUntil = nil
+	return true, nil
+}
+
+func (s *AccountInfoService) DeleteAccountInfo(ctx context.Context, req *api.DeleteAccountInfoRequest) error {
+	err := s.accountInfoRepo.DeleteAccountInfo(ctx, req.UserId)
+	if err != nil {
+		log.Printf("Erro ao remover AccountInfo: %v", err)
+		if status.Code(err) == codes.NotFound {
+			return err
+		}
+		return status.Errorf(codes.Internal, "Erro ao remover AccountInfo: %v", err)
+	}
+
+	return nil
+}
+
+func checkAccountStatusForLogin(accountInfo *api.AccountInfo) error {
+	switch accountInfo.AccountStatus {
+	case api.AccountStatus_ACTIVE:
+		return nil
+	case api.AccountStatus_PENDING:
+		return status.Errorf(codes.FailedPrecondition, "A conta ainda não foi ativada: %s", accountInfo.StatusReason)
+	case api.AccountStatus_SUSPENDED:
+		if accountInfo.SuspendedUntil != nil {
+			return status.Errorf(codes.PermissionDenied, "A conta está suspensa até %s: %s", accountInfo.SuspendedUntil.AsTime().Format(time.RFC3339), accountInfo.StatusReason)
+		}
+		return status.Errorf(codes.PermissionDenied, "A conta está suspensa: %s", accountInfo.StatusReason)
+	case api.AccountStatus_INACTIVE:
+		return status.Errorf(codes.NotFound, "A conta está inativa: %s", accountInfo.StatusReason)
+	default:
+		return status.Errorf(codes.Internal, "Status da conta desconhecido: %v", accountInfo.AccountStatus)
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultListAuditEventsPageSize = 50
+	maxListAuditEventsPageSize     = 500
+)
+
+type IAuditService interface {
+	ListAuditEvents(ctx context.Context, req *api.ListAuditEventsRequest) (*api.ListAuditEventsResponse, error)
+}
+
+type AuditService struct {
+	auditRepo repositories.IAuditRepository
+}
+
+func NewAuditService(auditRepo repositories.IAuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// ListAuditEvents lista os eventos do mais recente para o mais antigo. O pageToken guarda a
+// sequence do último evento da página, que é única e não muda com novas inserções.
+func (s *AuditService) ListAuditEvents(ctx context.Context, req *api.ListAuditEventsRequest) (*api.ListAuditEventsResponse, error) {
+	pageSize := int(req.PageSize)
+	if pageSize < 0 || pageSize > maxListAuditEventsPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "O page_size deve estar entre 0 e %d", maxListAuditEventsPageSize)
+	}
+	if pageSize == 0 {
+		pageSize = defaultListAuditEventsPageSize
+	}
+
+	userId := strings.TrimSpace(req.UserId)
+	if userId != "" && !primitive.IsValidObjectID(userId) {
+		return nil, status.Errorf(codes.InvalidArgument, "ID de usuário inválido")
+	}
+
+	query := repositories.ListAuditEventsQuery{
+		DocumentId: userId,
+		// Um evento a mais indica se existe uma próxima página.
+		Limit: int64(pageSize) + 1,
+	}
+	if req.OccurredAfter != nil {
+		query.OccurredAfter = req.OccurredAfter.AsTime()
+	}
+	if req.OccurredBefore != nil {
+		query.OccurredBefore = req.OccurredBefore.AsTime()
+	}
+	if !query.OccurredAfter.IsZero() && !query.OccurredBefore.IsZero() && !query.OccurredAfter.Before(query.OccurredBefore) {
+		return nil, status.Errorf(codes.InvalidArgument, "O occurredAfter deve ser anterior ao occurredBefore")
+	}
+
+	if req.PageToken != "" {
+		sequence, ok := decodeAuditPageToken(req.PageToken)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "pageToken inválido")
+		}
+		query.BeforeSequence = sequence
+	}
+
+	events, err := s.auditRepo.ListAuditEvents(ctx, query)
+	if err != nil {
+		log.Printf("Erro ao listar os eventos de auditoria: %v", err)
+		return nil, status.Errorf(codes.Internal, "Erro ao listar os eventos de auditoria: %v", err)
+	}
+
+	response := &api.ListAuditEventsResponse{}
+	if len(events) > pageSize {
+		events = events[:pageSize]
+		response.NextPageToken = encodeAuditPageToken(events[len(events)-1].Sequence)
+	}
+
+	for _, event := range events {
+		response.Events = append(response.Events, event.ToProto())
+	}
+	return response, nil
+}
+
+func encodeAuditPageToken(sequence int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(sequence, 10)))
+}
+
+func decodeAuditPageToken(raw string) (int64, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return 0, false
+	}
+
+	sequence, err := strconv.ParseInt(string(data), 10, 64)
+	if err != nil || sequence <= 0 {
+		return 0, false
+	}
+	return sequence, true
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/audit"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/mailer"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/jonh-dev/partus_users/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const pendingEmailVerificationReason = "Aguardando verificação do e-mail"
+
+// emailVerifiedReason é a razão registrada no statusHistory quando a verificação ativa a conta.
+const emailVerifiedReason = "E-mail verificado"
+
+type IEmailVerificationService interface {
+	SendVerificationEmail(ctx context.Context, userId string, email string, firstName string, timeZone string) error
+	VerifyEmail(ctx context.Context, req *api.VerifyEmailRequest) (*api.VerifyEmailResponse, error)
+	ResendVerificationEmail(ctx context.Context, req *api.ResendVerificationEmailRequest) (*api.ResendVerificationEmailResponse, error)
+}
+
+type EmailVerificationService struct {
+	personalInfoRepo repositories.IPersonalInfoRepository
+	accountInfoRepo  repositories.IAccountInfoRepository
+	txRunner         config.TransactionRunner
+	signer           *tokens.EmailVerificationSigner
+	mailer           mailer.Mailer
+	policy           *config.EmailVerificationPolicy
+}
+
+func NewEmailVerificationService(personalInfoRepo repositories.IPersonalInfoRepository, accountInfoRepo repositories.IAccountInfoRepository, txRunner config.TransactionRunner, mailer mailer.Mailer, policy *config.EmailVerificationPolicy) *EmailVerificationService {
+	return &EmailVerificationService{
+		personalInfoRepo: personalInfoRepo,
+		accountInfoRepo:  accountInfoRepo,
+		txRunner:         txRunner,
+		signer:           tokens.NewEmailVerificationSigner(policy.SigningKey, policy.TokenTTL),
+		mailer:           mailer,
+		policy:           policy,
+	}
+}
+
+// SendVerificationEmail envia o link de verificação com o prazo exibido no fuso horário do usuário.
+func (s *EmailVerificationService) SendVerificationEmail(ctx context.Context, userId string, email string, firstName string, timeZone string) error {
+	token, expiresAt, err := s.signer.Sign(userId, email, time.Now())
+	if err != nil {
+		log.Printf("Erro ao gerar o token de verificação: %v", err)
+		return status.Errorf(codes.Internal, "Erro ao gerar o token de verificação: %v", err)
+	}
+
+	link, err := url.Parse(s.policy.VerificationURL)
+	if err != nil {
+		return status.Errorf(codes.Internal, "EMAIL_VERIFICATION_URL inválida: %v", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	err = s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Confirme seu e-mail",
+		Body: fmt.Sprintf("Olá, %s!\n\nPara ativar sua conta, confirme seu e-mail acessando o link abaixo até %s:\n\n%s\n\nSe você não criou esta conta, ignore esta mensagem.\n",
+			firstName, utils.FormatInTimeZone(expiresAt, timeZone), link.String()),
+	})
+	if err != nil {
+		log.Printf("Erro ao enviar o e-mail de verificação: %v", err)
+		return status.Errorf(codes.Unavailable, "Erro ao enviar o e-mail de verificação: %v", err)
+	}
+
+	return nil
+}
+
+// VerifyEmail registra a verificação no PersonalInfo e ativa a conta pendente na mesma transação,
+// registrando a ativação no statusHistory. Verificar de novo um e-mail já verificado não é um erro.
+func (s *EmailVerificationService) VerifyEmail(ctx context.Context, req *api.VerifyEmailRequest) (*api.VerifyEmailResponse, error) {
+	if req.Token == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O token de verificação é obrigatório")
+	}
+
+	claims, err := s.signer.Verify(req.Token)
+	if err != nil {
+		log.Printf("Token de verificação rejeitado: %v", err)
+		return nil, status.Errorf(codes.InvalidArgument, "Token de verificação inválido ou expirado")
+	}
+
+	userId := claims.Subject
+	err = s.txRunner.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		marked, err := s.personalInfoRepo.MarkEmailVerified(ctx, userId, claims.Email, now)
+		if err != nil {
+			return status.Errorf(codes.Internal, "Erro ao registrar a verificação do e-mail: %v", err)
+		}
+		if !marked {
+			return status.Errorf(codes.FailedPrecondition, "O e-mail do usuário mudou depois do envio do token de verificação")
+		}
+
+		// A transição só é gravada se a conta ainda estiver pendente.
+		transition := &model.StatusTransition{
+			From:      model.AccountStatus_PENDING,
+			To:        model.AccountStatus_ACTIVE,
+			Reason:    emailVerifiedReason,
+			Actor:     audit.SystemActor,
+			ChangedAt: now,
+		}
+		if _, err := s.accountInfoRepo.ChangeAccountStatus(ctx, userId, transition); err != nil {
+			return status.Errorf(codes.Internal, "Erro ao ativar a conta: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Erro ao verificar o e-mail do usuário %s: %v", userId, err)
+		return nil, err
+	}
+
+	log.Printf("E-mail verificado: usuário %s", userId)
+	return &api.VerifyEmailResponse{
+		UserId:  userId,
+		Message: "E-mail verificado com sucesso",
+	}, nil
+}
+
+func (s *EmailVerificationService) ResendVerificationEmail(ctx context.Context, req *api.ResendVerificationEmailRequest) (*api.ResendVerificationEmailResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O ID do usuário é obrigatório")
+	}
+
+	personalInfo, err := s.personalInfoRepo.GetPersonalInfo(ctx, req.UserId)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "Erro ao obter PersonalInfo: %v", err)
+	}
+
+	if personalInfo.EmailVerifiedAt != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "O e-mail já foi verificado")
+	}
+
+	if err := s.SendVerificationEmail(ctx, req.UserId, personalInfo.Email, personalInfo.FirstName, personalInfo.TimeZone); err != nil {
+		return nil, err
+	}
+
+	return &api.ResendVerificationEmailResponse{Message: "E-mail de verificação reenviado"}, nil
+}
@@ -0,0 +1,383 @@
+package services
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/encryption"
+	"github.com/jonh-dev/partus_users/internal/mfa"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/jonh-dev/partus_users/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type IMFAService interface {
+	EnrollTOTP(ctx context.Context, req *api.EnrollTOTPRequest) (*api.EnrollTOTPResponse, error)
+	ConfirmTOTP(ctx context.Context, req *api.ConfirmTOTPRequest) (*api.ConfirmTOTPResponse, error)
+	DisableMFA(ctx context.Context, req *api.DisableMFARequest) (*api.DisableMFAResponse, error)
+	IsMFAEnabled(ctx context.Context, userId string) (bool, error)
+	CreateChallenge(ctx context.Context, userId string) (string, time.Time, error)
+	VerifyChallenge(ctx context.Context, challengeToken string, code string) (string, error)
+}
+
+type MFAService struct {
+	mfaRepo            repositories.IMFARepository
+	accountInfoService IAccountInfoService
+	secretCipher       encryption.SecretCipher
+	policy             *config.MFAPolicy
+	totp               *mfa.TOTP
+	issuer             tokens.Issuer
+}
+
+func NewMFAService(mfaRepo repositories.IMFARepository, accountInfoService IAccountInfoService, secretCipher encryption.SecretCipher, policy *config.MFAPolicy, issuer tokens.Issuer) *MFAService {
+	return &MFAService{
+		mfaRepo:            mfaRepo,
+		accountInfoService: accountInfoService,
+		secretCipher:       secretCipher,
+		policy:             policy,
+		totp:               mfa.DefaultTOTP(),
+		issuer:             issuer,
+	}
+}
+
+// EnrollTOTP gera um novo segredo pendente. O MFA só passa a valer depois de ConfirmTOTP, o que
+// garante que o usuário conseguiu cadastrar o segredo no aplicativo autenticador. Exige o token de
+// acesso do próprio usuário e a senha atual.
+func (s *MFAService) EnrollTOTP(ctx context.Context, req *api.EnrollTOTPRequest) (*api.EnrollTOTPResponse, error) {
+	if req.UserId == "" || req.Password == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O ID do usuário e a senha são obrigatórios")
+	}
+
+	if _, err := authorizeUser(ctx, s.issuer, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.accountInfoService.VerifyPassword(ctx, req.UserId, req.Password); err != nil {
+		return nil, err
+	}
+
+	accountInfo, err := s.accountInfoService.GetAccountInfo(ctx, &api.GetAccountInfoRequest{UserId: req.UserId})
+	if err != nil {
+		return nil, err
+	}
+
+	userId, err := utils.ConvertToObjectId(req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := mfa.GenerateSecret()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Erro ao gerar o segredo TOTP: %v", err)
+	}
+
+	encryptedSecret, err := s.secretCipher.Encrypt(secret)
+	if err != nil {
+		log.Printf("Erro ao cifrar o segredo TOTP: %v", err)
+		return nil, status.Errorf(codes.Internal, "Erro ao cifrar o segredo TOTP: %v", err)
+	}
+
+	err = s.mfaRepo.SavePendingMFA(ctx, &model.MFA{
+		UserId:          userId,
+		EncryptedSecret: encryptedSecret,
+		CreatedAt:       time.Now(),
+	})
+	if err != nil {
+		log.Printf("Erro ao salvar o cadastro de MFA: %v", err)
+		if status.Code(err) == codes.FailedPrecondition {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "Erro ao salvar o cadastro de MFA: %v", err)
+	}
+
+	return &api.EnrollTOTPResponse{
+		Secret:     mfa.EncodeSecret(secret),
+		OtpauthUri: s.totp.KeyURI(secret, s.policy.Issuer, accountInfo.Username),
+		Message:    "Escaneie o QR code no aplicativo autenticador e confirme com um código",
+	}, nil
+}
+
+// ConfirmTOTP ativa o MFA e devolve os códigos de recuperação, que não podem ser consultados depois.
+// Assim como EnrollTOTP, exige o token de acesso do próprio usuário.
+func (s *MFAService) ConfirmTOTP(ctx context.Context, req *api.ConfirmTOTPRequest) (*api.ConfirmTOTPResponse, error) {
+	if req.UserId == "" || req.Code == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O ID do usuário e o código são obrigatórios")
+	}
+
+	if _, err := authorizeUser(ctx, s.issuer, req.UserId); err != nil {
+		return nil, err
+	}
+
+	userMFA, err := s.getMFA(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if userMFA.Enabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "A autenticação em duas etapas já está ativa")
+	}
+
+	secret, err := s.secretCipher.Decrypt(userMFA.EncryptedSecret)
+	if err != nil {
+		log.Printf("Erro ao decifrar o segredo TOTP: %v", err)
+		return nil, status.Errorf(codes.Internal, "Erro ao decifrar o segredo TOTP: %v", err)
+	}
+
+	step, ok := s.totp.Validate(secret, req.Code, time.Now())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "Código inválido")
+	}
+
+	recoveryCodes, err := mfa.GenerateRecoveryCodes(mfa.RecoveryCodeCount)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Erro ao gerar os códigos de recuperação: %v", err)
+	}
+	hashes := make([]string, len(recoveryCodes))
+	for i, code := range recoveryCodes {
+		hashes[i] = mfa.HashRecoveryCode(code)
+	}
+
+	enabled, err := s.mfaRepo.EnableMFA(ctx, req.UserId, hashes, step, time.Now())
+	if err != nil {
+		log.Printf("Erro ao ativar o MFA: %v", err)
+		return nil, status.Errorf(codes.Internal, "Erro ao ativar o MFA: %v", err)
+	}
+	if !enabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "A autenticação em duas etapas já está ativa")
+	}
+
+	log.Printf("MFA ativado para o usuário %s", req.UserId)
+	return &api.ConfirmTOTPResponse{
+		RecoveryCodes: recoveryCodes,
+		Message:       "Autenticação em duas etapas ativada. Guarde os códigos de recuperação em local seguro",
+	}, nil
+}
+
+// DisableMFA exige o token de acesso do próprio usuário, a senha e um código válido (TOTP ou de
+// recuperação), para que uma sessão roubada não consiga remover o segundo fator sozinha. Senhas e
+// códigos errados contam para o bloqueio da conta, como no login.
+func (s *MFAService) DisableMFA(ctx context.Context, req *api.DisableMFARequest) (*api.DisableMFAResponse, error) {
+	if req.UserId == "" || req.Password == "" || req.Code == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O ID do usuário, a senha e o código são obrigatórios")
+	}
+
+	if _, err := authorizeUser(ctx, s.issuer, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.accountInfoService.VerifyPassword(ctx, req.UserId, req.Password); err != nil {
+		return nil, err
+	}
+
+	userMFA, err := s.getMFA(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if !userMFA.Enabled {
+		return nil, status.Errorf(codes.FailedPrecondition, "A autenticação em duas etapas não está ativa")
+	}
+
+	valid, err := s.verifyCode(ctx, userMFA, req.Code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		if err := s.accountInfoService.RegisterFailedMFA(ctx, req.UserId); err != nil {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Unauthenticated, "Código inválido")
+	}
+
+	if err := s.mfaRepo.DeleteMFA(ctx, req.UserId); err != nil {
+		log.Printf("Erro ao desativar o MFA: %v", err)
+		return nil, status.Errorf(codes.Internal, "Erro ao desativar o MFA: %v", err)
+	}
+
+	log.Printf("MFA desativado para o usuário %s", req.UserId)
+	return &api.DisableMFAResponse{Message: "Autenticação em duas etapas desativada"}, nil
+}
+
+func (s *MFAService) IsMFAEnabled(ctx context.Context, userId string) (bool, error) {
+	userMFA, err := s.mfaRepo.GetMFA(ctx, userId)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return false, nil
+		}
+		log.Printf("Erro ao buscar o MFA: %v", err)
+		return false, status.Errorf(codes.Internal, "Erro ao buscar o MFA: %v", err)
+	}
+
+	return userMFA.Enabled, nil
+}
+
+// CreateChallenge é chamado pelo login depois que a senha foi verificada e retorna o token que deve
+// ser enviado junto com o código MFA.
+func (s *MFAService) CreateChallenge(ctx context.Context, userId string) (string, time.Time, error) {
+	objectId, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	challengeToken, err := tokens.NewMFAChallengeToken()
+	if err != nil {
+		return "", time.Time{}, status.Errorf(codes.Internal, "Erro ao gerar o desafio de MFA: %v", err)
+	}
+
+	now := time.Now()
+	challenge := &model.MFAChallenge{
+		UserId:    objectId,
+		TokenHash: tokens.HashMFAChallengeToken(challengeToken),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.policy.ChallengeTTL),
+	}
+	if err := s.mfaRepo.CreateChallenge(ctx, challenge); err != nil {
+		log.Printf("Erro ao criar o desafio de MFA: %v", err)
+		return "", time.Time{}, status.Errorf(codes.Internal, "Erro ao criar o desafio de MFA: %v", err)
+	}
+
+	return challengeToken, challenge.ExpiresAt, nil
+}
+
+// VerifyChallenge consome o desafio e retorna o ID do usuário se o código for válido. Cada desafio
+// aceita no máximo MaxChallengeAttempts códigos errados; depois disso é preciso refazer o login.
+// Os códigos errados também contam para o bloqueio da conta, como as senhas erradas.
+func (s *MFAService) VerifyChallenge(ctx context.Context, challengeToken string, code string) (string, error) {
+	if challengeToken == "" || code == "" {
+		return "", status.Errorf(codes.InvalidArgument, "O token do desafio e o código são obrigatórios")
+	}
+
+	challenge, err := s.mfaRepo.GetChallengeByTokenHash(ctx, tokens.HashMFAChallengeToken(challengeToken))
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return "", status.Errorf(codes.Unauthenticated, "Desafio de MFA inválido ou expirado")
+		}
+		log.Printf("Erro ao buscar o desafio de MFA: %v", err)
+		return "", status.Errorf(codes.Internal, "Erro ao buscar o desafio de MFA: %v", err)
+	}
+
+	if !time.Now().Before(challenge.ExpiresAt) || challenge.Attempts >= s.policy.MaxChallengeAttempts {
+		s.deleteChallenge(ctx, challenge)
+		return "", status.Errorf(codes.Unauthenticated, "Desafio de MFA inválido ou expirado")
+	}
+
+	userId := challenge.UserId.Hex()
+	userMFA, err := s.getMFA(ctx, userId)
+	if err != nil {
+		return "", err
+	}
+	if !userMFA.Enabled {
+		s.deleteChallenge(ctx, challenge)
+		return "", status.Errorf(codes.Unauthenticated, "Desafio de MFA inválido ou expirado")
+	}
+
+	accountInfo, err := s.accountInfoService.GetAccountInfo(ctx, &api.GetAccountInfoRequest{UserId: userId})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			s.deleteChallenge(ctx, challenge)
+			return "", status.Errorf(codes.Unauthenticated, "Desafio de MFA inválido ou expirado")
+		}
+		return "", err
+	}
+	if err := checkAccountLock(accountInfo, time.Now()); err != nil {
+		s.deleteChallenge(ctx, challenge)
+		return "", err
+	}
+
+	valid, err := s.verifyCode(ctx, userMFA, code)
+	if err != nil {
+		return "", err
+	}
+	if !valid {
+		if err := s.mfaRepo.IncrementChallengeAttempts(ctx, challenge.Id); err != nil {
+			log.Printf("Erro ao registrar tentativa de MFA: %v", err)
+		}
+		if err := s.accountInfoService.RegisterFailedMFA(ctx, userId); err != nil {
+			if status.Code(err) == codes.ResourceExhausted {
+				s.deleteChallenge(ctx, challenge)
+			}
+			return "", err
+		}
+		return "", status.Errorf(codes.Unauthenticated, "Código inválido")
+	}
+
+	deleted, err := s.mfaRepo.DeleteChallenge(ctx, challenge.Id)
+	if err != nil {
+		log.Printf("Erro ao consumir o desafio de MFA: %v", err)
+		return "", status.Errorf(codes.Internal, "Erro ao consumir o desafio de MFA: %v", err)
+	}
+	if !deleted {
+		return "", status.Errorf(codes.Unauthenticated, "Desafio de MFA inválido ou expirado")
+	}
+
+	return userId, nil
+}
+
+// verifyCode aceita um código TOTP ou um código de recuperação, que é invalidado após o uso.
+func (s *MFAService) verifyCode(ctx context.Context, userMFA *model.MFA, code string) (bool, error) {
+	userId := userMFA.UserId.Hex()
+	code = strings.TrimSpace(code)
+
+	if len(code) == s.totp.Digits && isDigits(code) {
+		secret, err := s.secretCipher.Decrypt(userMFA.EncryptedSecret)
+		if err != nil {
+			log.Printf("Erro ao decifrar o segredo TOTP: %v", err)
+			return false, status.Errorf(codes.Internal, "Erro ao decifrar o segredo TOTP: %v", err)
+		}
+
+		step, ok := s.totp.Validate(secret, code, time.Now())
+		if !ok || step <= userMFA.LastUsedStep {
+			return false, nil
+		}
+
+		used, err := s.mfaRepo.UseTOTPStep(ctx, userId, step)
+		if err != nil {
+			log.Printf("Erro ao registrar o código TOTP: %v", err)
+			return false, status.Errorf(codes.Internal, "Erro ao registrar o código TOTP: %v", err)
+		}
+		return used, nil
+	}
+
+	used, err := s.mfaRepo.UseRecoveryCode(ctx, userId, mfa.HashRecoveryCode(code))
+	if err != nil {
+		log.Printf("Erro ao usar o código de recuperação: %v", err)
+		return false, status.Errorf(codes.Internal, "Erro ao usar o código de recuperação: %v", err)
+	}
+	if used {
+		log.Printf("Código de recuperação usado pelo usuário %s", userId)
+	}
+	return used, nil
+}
+
+func (s *MFAService) getMFA(ctx context.Context, userId string) (*model.MFA, error) {
+	userMFA, err := s.mfaRepo.GetMFA(ctx, userId)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.FailedPrecondition, "A autenticação em duas etapas não está configurada")
+		}
+		log.Printf("Erro ao buscar o MFA: %v", err)
+		return nil, status.Errorf(codes.Internal, "Erro ao buscar o MFA: %v", err)
+	}
+
+	return userMFA, nil
+}
+
+func (s *MFAService) deleteChallenge(ctx context.Context, challenge *model.MFAChallenge) {
+	if _, err := s.mfaRepo.DeleteChallenge(ctx, challenge.Id); err != nil {
+		log.Printf("Erro ao remover o desafio de MFA: %v", err)
+	}
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/encryption"
+	"github.com/jonh-dev/partus_users/internal/mailer"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/jonh-dev/partus_users/internal/utils"
+	"github.com/jonh-dev/partus_users/internal/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// passwordResetRequestedMessage é a única resposta de RequestPasswordReset, exista ou não o e-mail,
+// para que o endpoint não revele quais e-mails estão cadastrados.
+const passwordResetRequestedMessage = "Se o e-mail estiver cadastrado, você receberá as instruções para redefinir a senha"
+
+// passwordResetSendTimeout limita o envio feito em segundo plano, que não depende mais do prazo da
+// chamada que o originou.
+const passwordResetSendTimeout = 30 * time.Second
+
+type IPasswordResetService interface {
+	RequestPasswordReset(ctx context.Context, req *api.RequestPasswordResetRequest) (*api.RequestPasswordResetResponse, error)
+	ResetPassword(ctx context.Context, req *api.ResetPasswordRequest) (*api.ResetPasswordResponse, error)
+}
+
+type PasswordResetService struct {
+	personalInfoRepo  repositories.IPersonalInfoRepository
+	accountInfoRepo   repositories.IAccountInfoRepository
+	passwordResetRepo repositories.IPasswordResetRepository
+	passwordEncryptor encryption.PasswordEncryptor
+	passwordChecker   PasswordChecker
+	passwordPolicy    *config.PasswordPolicy
+	sessionRevoker    SessionRevoker
+	txRunner          config.TransactionRunner
+	mailer            mailer.Mailer
+	policy            *config.PasswordResetPolicy
+	pending           sync.WaitGroup
+}
+
+func NewPasswordResetService(personalInfoRepo repositories.IPersonalInfoRepository, accountInfoRepo repositories.IAccountInfoRepository, passwordResetRepo repositories.IPasswordResetRepository, passwordEncryptor encryption.PasswordEncryptor, passwordChecker PasswordChecker, passwordPolicy *config.PasswordPolicy, sessionRevoker SessionRevoker, txRunner config.TransactionRunner, mailer mailer.Mailer, policy *config.PasswordResetPolicy) *PasswordResetService {
+	return &PasswordResetService{
+		personalInfoRepo:  personalInfoRepo,
+		accountInfoRepo:   accountInfoRepo,
+		passwordResetRepo: passwordResetRepo,
+		passwordEncryptor: passwordEncryptor,
+		passwordChecker:   passwordChecker,
+		passwordPolicy:    passwordPolicy,
+		sessionRevoker:    sessionRevoker,
+		txRunner:          txRunner,
+		mailer:            mailer,
+		policy:            policy,
+	}
+}
+
+// RequestPasswordReset responde sempre da mesma forma. O pedido e o e-mail são gerados em segundo
+// plano, para que o tempo de resposta também não revele se o e-mail existe, e as falhas são apenas
+// registradas no log pelo mesmo motivo.
+func (s *PasswordResetService) RequestPasswordReset(ctx context.Context, req *api.RequestPasswordResetRequest) (*api.RequestPasswordResetResponse, error) {
+	email := strings.TrimSpace(req.Email)
+	if email == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O e-mail é obrigatório")
+	}
+
+	response := &api.RequestPasswordResetResponse{Message: passwordResetRequestedMessage}
+
+	personalInfo, err := s.personalInfoRepo.GetPersonalInfoByEmail(ctx, email)
+	if err != nil {
+		if status.Code(err) != codes.NotFound {
+			log.Printf("Erro ao buscar o e-mail para redefinição de senha: %v", err)
+		}
+		return response, nil
+	}
+
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+
+		sendCtx, cancel := context.WithTimeout(context.Background(), passwordResetSendTimeout)
+		defer cancel()
+
+		if err := s.sendPasswordReset(sendCtx, personalInfo); err != nil {
+			log.Printf("Erro ao solicitar a redefinição de senha do usuário %s: %v", personalInfo.UserId, err)
+		}
+	}()
+
+	return response, nil
+}
+
+// Wait bloqueia até que os envios iniciados por RequestPasswordReset terminem.
+func (s *PasswordResetService) Wait() {
+	s.pending.Wait()
+}
+
+// sendPasswordReset invalida os pedidos anteriores, para que apenas o link mais recente funcione.
+func (s *PasswordResetService) sendPasswordReset(ctx context.Context, personalInfo *api.PersonalInfo) error {
+	userId, err := utils.ConvertToObjectId(personalInfo.UserId)
+	if err != nil {
+		return err
+	}
+
+	token, err := tokens.NewPasswordResetToken()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if err := s.passwordResetRepo.InvalidateUserPasswordResets(ctx, personalInfo.UserId, now); err != nil {
+		return err
+	}
+
+	reset := &model.PasswordReset{
+		UserId:    userId,
+		TokenHash: tokens.HashPasswordResetToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.policy.TokenTTL),
+	}
+	if err := s.passwordResetRepo.CreatePasswordReset(ctx, reset); err != nil {
+		return err
+	}
+
+	link, err := url.Parse(s.policy.ResetURL)
+	if err != nil {
+		return fmt.Errorf("PASSWORD_RESET_URL inválida: %w", err)
+	}
+	query := link.Query()
+	query.Set("token", token)
+	link.RawQuery = query.Encode()
+
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      personalInfo.Email,
+		Subject: "Redefinição de senha",
+		Body: fmt.Sprintf("Olá, %s!\n\nRecebemos um pedido para redefinir a sua senha. Para escolher uma nova senha, acesse o link abaixo até %s:\n\n%s\n\nSe você não fez este pedido, ignore esta mensagem; sua senha continua a mesma.\n",
+			personalInfo.FirstName, utils.FormatInTimeZone(reset.ExpiresAt, personalInfo.TimeZone), link.String()),
+	})
+}
+
+// ResetPassword consome o token e grava a nova senha na mesma transação, recusando as senhas do
+// histórico como ChangePassword. Depois disso todas as sessões do usuário são encerradas, já que a
+// senha antiga pode ter sido comprometida.
+func (s *PasswordResetService) ResetPassword(ctx context.Context, req *api.ResetPasswordRequest) (*api.ResetPasswordResponse, error) {
+	if req.Token == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O token de redefinição é obrigatório")
+	}
+
+	if err := validation.ValidatePassword("newPassword", req.NewPassword); err != nil {
+		return nil, validation.ToStatus("Erro ao validar a senha", err)
+	}
+
+	if err := checkPasswordStrength(s.passwordChecker, "newPassword", req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.passwordEncryptor.EncryptPassword(req.NewPassword)
+	if err != nil {
+		log.Printf("Erro ao criptografar a senha: %v", err)
+		return nil, status.Errorf(codes.Internal, "Erro ao criptografar a senha: %v", err)
+	}
+
+	var userId string
+	err = s.txRunner.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+
+		reset, err := s.passwordResetRepo.ConsumePasswordReset(ctx, tokens.HashPasswordResetToken(req.Token), now)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return status.Errorf(codes.InvalidArgument, "Token de redefinição inválido ou expirado")
+			}
+			return status.Errorf(codes.Internal, "Erro ao consumir o token de redefinição: %v", err)
+		}
+		userId = reset.UserId.Hex()
+
+		accountInfo, err := s.accountInfoRepo.GetAccountInfo(ctx, userId)
+		if err != nil {
+			if status.Code(err) == codes.NotFound {
+				return err
+			}
+			return status.Errorf(codes.Internal, "Erro ao obter AccountInfo: %v", err)
+		}
+
+		if err := checkPasswordReuse(ctx, s.accountInfoRepo, s.passwordEncryptor, s.passwordPolicy, accountInfo, req.NewPassword); err != nil {
+			return err
+		}
+
+		changed, err := s.accountInfoRepo.ResetPassword(ctx, userId, accountInfo.Password, hashedPassword, s.passwordPolicy.HistorySize, now)
+		if err != nil {
+			return status.Errorf(codes.Internal, "Erro ao redefinir a senha: %v", err)
+		}
+		if !changed {
+			return status.Errorf(codes.Aborted, "A senha foi alterada por outra operação; tente novamente")
+		}
+
+		if err := s.passwordResetRepo.InvalidateUserPasswordResets(ctx, userId, now); err != nil {
+			return status.Errorf(codes.Internal, "Erro ao invalidar os outros pedidos de redefinição: %v", err)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("Erro ao redefinir a senha: %v", err)
+		return nil, err
+	}
+
+	if _, err := s.sessionRevoker.RevokeAllUserSessions(ctx, userId, "senha redefinida"); err != nil {
+		log.Printf("Erro ao revogar as sessões após a redefinição de senha: %v", err)
+		return nil, status.Errorf(codes.Internal, "A senha foi redefinida, mas houve erro ao encerrar as sessões: %v", err)
+	}
+
+	log.Printf("Senha redefinida para o usuário %s", userId)
+	return &api.ResetPasswordResponse{Message: "Senha redefinida com sucesso"}, nil
+}
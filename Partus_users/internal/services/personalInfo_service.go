@@ -3,9 +3,11 @@ package services
 import (
 	"context"
 	"log"
+	"strings"
 
 	"github.com/jonh-dev/go-error/errors"
 	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/model"
 	"github.com/jonh-dev/partus_users/internal/repositories"
 	"github.com/jonh-dev/partus_users/internal/validation"
 	"google.golang.org/grpc/codes"
@@ -15,21 +17,28 @@ import (
 type IPersonalInfoService interface {
 	CreatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error)
 	GetPersonalInfo(ctx context.Context, req *api.GetPersonalInfoRequest) (*api.PersonalInfo, error)
+	GetPersonalInfoByEmail(ctx context.Context, email string) (*api.PersonalInfo, error)
 	UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error)
+	DeletePersonalInfo(ctx context.Context, req *api.DeletePersonalInfoRequest) error
+	PatchPersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo, fields []string) (*api.PersonalInfo, error)
 }
 
 type PersonalInfoService struct {
-	personalInfoRepo repositories.IPersonalInfoRepository
+	personalInfoRepo  repositories.IPersonalInfoRepository
+	emailVerification IEmailVerificationService
 }
 
-func NewPersonalInfoService(personalInfoRepo repositories.IPersonalInfoRepository) *PersonalInfoService {
-	return &PersonalInfoService{personalInfoRepo: personalInfoRepo}
+func NewPersonalInfoService(personalInfoRepo repositories.IPersonalInfoRepository, emailVerification IEmailVerificationService) *PersonalInfoService {
+	return &PersonalInfoService{personalInfoRepo: personalInfoRepo, emailVerification: emailVerification}
 }
 
 func (s *PersonalInfoService) CreatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error) {
+	// O e-mail só é dado como verificado pelo VerifyEmail.
+	personalInfo.EmailVerifiedAt = nil
+
 	err := validation.ValidatePersonalInfo(personalInfo, validation.Create)
 	if err != nil {
-		return nil, errors.New(codes.InvalidArgument, "Erro na validação das informações pessoais: "+err.Error())
+		return nil, validation.ToStatus("Erro na validação das informações pessoais", err)
 	}
 
 	emailExists, err := s.personalInfoRepo.DoesEmailExist(ctx, personalInfo.Email)
@@ -43,6 +52,9 @@ func (s *PersonalInfoService) CreatePersonalInfo(ctx context.Context, personalIn
 
 	createdPersonalInfo, err := s.personalInfoRepo.CreatePersonalInfo(ctx, personalInfo)
 	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return nil, errors.New(codes.AlreadyExists, "O e-mail já existe")
+		}
 		return nil, errors.New(codes.Internal, "Erro ao criar PersonalInfo: "+err.Error())
 	}
 
@@ -53,24 +65,150 @@ func (s *PersonalInfoService) GetPersonalInfo(ctx context.Context, req *api.GetP
 	personalInfo, err := s.personalInfoRepo.GetPersonalInfo(ctx, req.UserId)
 	if err != nil {
 		log.Printf("Erro ao obter PersonalInfo: %v", err)
+		if status.Code(err) == codes.NotFound {
+			return nil, err
+		}
 		return nil, status.Errorf(codes.Internal, "Erro ao obter PersonalInfo: %v", err)
 	}
 
 	return personalInfo, nil
 }
 
+func (s *PersonalInfoService) GetPersonalInfoByEmail(ctx context.Context, email string) (*api.PersonalInfo, error) {
+	personalInfo, err := s.personalInfoRepo.GetPersonalInfoByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		log.Printf("Erro ao obter PersonalInfo por e-mail: %v", err)
+		if status.Code(err) == codes.NotFound {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "Erro ao obter PersonalInfo: %v", err)
+	}
+
+	return personalInfo, nil
+}
+
+// UpdatePersonalInfo substitui todos os campos. Um e-mail novo volta a ficar não verificado e
+// recebe o e-mail de verificação.
 func (s *PersonalInfoService) UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error) {
 	err := validation.ValidatePersonalInfo(personalInfo, validation.Update)
 	if err != nil {
 		log.Printf("Erro ao validar PersonalInfo: %v", err)
-		return nil, status.Errorf(codes.InvalidArgument, "Erro ao validar PersonalInfo: %v", err)
+		return nil, validation.ToStatus("Erro ao validar PersonalInfo", err)
+	}
+
+	fields, emailChanged, err := s.emailUpdateFields(ctx, personalInfo, model.PersonalInfoFields)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedPersonalInfo, err := s.personalInfoRepo.UpdatePersonalInfo(ctx, personalInfo, fields)
+	if err != nil {
+		log.Printf("Erro ao atualizar PersonalInfo: %v", err)
+		if code := status.Code(err); code == codes.NotFound || code == codes.AlreadyExists {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "Erro ao atualizar PersonalInfo: %v", err)
+	}
+
+	if emailChanged {
+		s.sendVerificationEmail(ctx, updatedPersonalInfo)
+	}
+	return updatedPersonalInfo, nil
+}
+
+func (s *PersonalInfoService) DeletePersonalInfo(ctx context.Context, req *api.DeletePersonalInfoRequest) error {
+	err := s.personalInfoRepo.DeletePersonalInfo(ctx, req.UserId)
+	if err != nil {
+		log.Printf("Erro ao remover PersonalInfo: %v", err)
+		if status.Code(err) == codes.NotFound {
+			return err
+		}
+		return status.Errorf(codes.Internal, "Erro ao remover PersonalInfo: %v", err)
+	}
+
+	return nil
+}
+
+// PatchPersonalInfo atualiza apenas os campos informados. Como em UpdatePersonalInfo, um e-mail novo
+// volta a ficar não verificado e recebe o e-mail de verificação.
+func (s *PersonalInfoService) PatchPersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo, fields []string) (*api.PersonalInfo, error) {
+	err := validation.ValidatePersonalInfoFields(personalInfo, fields)
+	if err != nil {
+		log.Printf("Erro ao validar PersonalInfo: %v", err)
+		return nil, validation.ToStatus("Erro ao validar PersonalInfo", err)
 	}
 
-	updatedPersonalInfo, err := s.personalInfoRepo.UpdatePersonalInfo(ctx, personalInfo)
+	fields, emailChanged, err := s.emailUpdateFields(ctx, personalInfo, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedPersonalInfo, err := s.personalInfoRepo.UpdatePersonalInfo(ctx, personalInfo, fields)
 	if err != nil {
 		log.Printf("Erro ao atualizar PersonalInfo: %v", err)
+		if code := status.Code(err); code == codes.NotFound || code == codes.InvalidArgument || code == codes.AlreadyExists {
+			return nil, err
+		}
 		return nil, status.Errorf(codes.Internal, "Erro ao atualizar PersonalInfo: %v", err)
 	}
 
+	if emailChanged {
+		s.sendVerificationEmail(ctx, updatedPersonalInfo)
+	}
 	return updatedPersonalInfo, nil
 }
+
+// emailUpdateFields compara o e-mail da atualização com o atual. Se for o mesmo, "email" sai dos
+// campos, para que o repositório não desfaça a verificação; se for outro, confirma que ele está
+// livre e retorna emailChanged.
+func (s *PersonalInfoService) emailUpdateFields(ctx context.Context, personalInfo *api.PersonalInfo, fields []string) ([]string, bool, error) {
+	if !containsField(fields, "email") {
+		return fields, false, nil
+	}
+
+	currentPersonalInfo, err := s.GetPersonalInfo(ctx, &api.GetPersonalInfoRequest{UserId: personalInfo.UserId})
+	if err != nil {
+		return nil, false, err
+	}
+
+	if currentPersonalInfo.Email == personalInfo.Email {
+		remaining := make([]string, 0, len(fields)-1)
+		for _, field := range fields {
+			if field != "email" {
+				remaining = append(remaining, field)
+			}
+		}
+		return remaining, false, nil
+	}
+
+	if !strings.EqualFold(currentPersonalInfo.Email, personalInfo.Email) {
+		emailExists, err := s.personalInfoRepo.DoesEmailExist(ctx, personalInfo.Email)
+		if err != nil {
+			return nil, false, errors.New(codes.Internal, "Erro ao verificar a existência do e-mail: "+err.Error())
+		}
+
+		if emailExists {
+			return nil, false, errors.New(codes.AlreadyExists, "O e-mail já existe")
+		}
+	}
+
+	return fields, true, nil
+}
+
+// sendVerificationEmail envia a verificação para o e-mail novo. Como no cadastro, uma falha no envio
+// não desfaz a alteração; o e-mail pode ser reenviado.
+func (s *PersonalInfoService) sendVerificationEmail(ctx context.Context, personalInfo *api.PersonalInfo) {
+	err := s.emailVerification.SendVerificationEmail(ctx, personalInfo.UserId, personalInfo.Email, personalInfo.FirstName, personalInfo.TimeZone)
+	if err != nil {
+		log.Printf("Erro ao enviar o e-mail de verificação do usuário %s: %v", personalInfo.UserId, err)
+	}
+}
+
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const deviceMetadataKey = "x-device-name"
+
+// sessionClientFromContext lê dispositivo, IP e user agent dos metadados da chamada. Os valores são
+// informados pelo cliente e servem apenas para exibição ao usuário, nunca para autorização.
+func sessionClientFromContext(ctx context.Context) model.SessionClient {
+	client := model.SessionClient{}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		client.Device = firstMetadataValue(md, deviceMetadataKey)
+		client.UserAgent = firstMetadataValue(md, "user-agent")
+		if forwardedFor := firstMetadataValue(md, "x-forwarded-for"); forwardedFor != "" {
+			client.IpAddress = strings.TrimSpace(strings.Split(forwardedFor, ",")[0])
+		}
+	}
+
+	if client.IpAddress == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			host, _, err := net.SplitHostPort(p.Addr.String())
+			if err != nil {
+				host = p.Addr.String()
+			}
+			client.IpAddress = host
+		}
+	}
+
+	return client
+}
+
+// bearerToken retorna o token do metadado "authorization: Bearer <token>", ou "" se não houver.
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	authorization := firstMetadataValue(md, "authorization")
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// authorizeUser exige um token de acesso válido emitido para userId ou, quando staffRoles é
+// informado, para alguém com um desses papéis. As RPCs que recebem o ID do usuário na requisição
+// usam o token para garantir que a chamada seja do próprio usuário ou da equipe autorizada.
+func authorizeUser(ctx context.Context, issuer tokens.Issuer, userId string, staffRoles ...string) (*tokens.AccessTokenClaims, error) {
+	token := bearerToken(ctx)
+	if token == "" {
+		return nil, status.Errorf(codes.Unauthenticated, "Token de acesso ausente")
+	}
+
+	claims, err := issuer.VerifyAccessToken(token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Token de acesso inválido")
+	}
+
+	if claims.Subject != userId && !claims.HasAnyRole(staffRoles...) {
+		return nil, status.Errorf(codes.PermissionDenied, "O token de acesso não pertence ao usuário informado")
+	}
+
+	return claims, nil
+}
+
+func firstMetadataValue(md metadata.MD, key string) string {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/go-logger/logger"
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/jonh-dev/partus_users/internal/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type ISessionService interface {
+	CreateSession(ctx context.Context, userId string) (*api.TokenResponse, error)
+	RefreshToken(ctx context.Context, req *api.RefreshTokenRequest) (*api.TokenResponse, error)
+	GetPublicKeys(ctx context.Context, req *api.GetPublicKeysRequest) (*api.GetPublicKeysResponse, error)
+	ListSessions(ctx context.Context, req *api.ListSessionsRequest) (*api.ListSessionsResponse, error)
+	RevokeSession(ctx context.Context, req *api.RevokeSessionRequest) (*api.RevokeSessionResponse, error)
+	RevokeAllSessions(ctx context.Context, req *api.RevokeAllSessionsRequest) (*api.RevokeAllSessionsResponse, error)
+	SessionRevoker
+}
+
+// SessionRevoker encerra as sessões de um usuário quando as credenciais dele mudam.
+type SessionRevoker interface {
+	RevokeOtherSessions(ctx context.Context, userId string) (int, error)
+	RevokeAllUserSessions(ctx context.Context, userId string, reason string) (int, error)
+}
+
+// sessionStaffRoles podem listar e revogar as sessões de outros usuários, por exemplo depois que a
+// conta foi comprometida.
+var sessionStaffRoles = []string{model.RoleSupport, model.RoleAdmin}
+
+type SessionService struct {
+	sessionRepo repositories.ISessionRepository
+	userRepo    repositories.IUserRepository
+	issuer      tokens.Issuer
+	policy      *config.TokenPolicy
+}
+
+func NewSessionService(sessionRepo repositories.ISessionRepository, userRepo repositories.IUserRepository, issuer tokens.Issuer, policy *config.TokenPolicy) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		userRepo:    userRepo,
+		issuer:      issuer,
+		policy:      policy,
+	}
+}
+
+// CreateSession abre uma sessão para o usuário autenticado e emite o primeiro par de tokens.
+func (s *SessionService) CreateSession(ctx context.Context, userId string) (*api.TokenResponse, error) {
+	objectId, err := utils.ConvertToObjectId(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUser(ctx, userId)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "Erro ao obter usuário: %v", err)
+	}
+
+	refreshToken, err := tokens.NewRefreshToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Erro ao gerar refresh token: %v", err)
+	}
+
+	now := time.Now()
+	session, err := s.sessionRepo.CreateSession(ctx, &model.Session{
+		UserId:     objectId,
+		TokenHash:  tokens.HashRefreshToken(refreshToken),
+		Client:     sessionClientFromContext(ctx),
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(s.policy.RefreshTokenTTL),
+	})
+	if err != nil {
+		logger.Error("Erro ao criar sessão: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "Erro ao criar sessão: %v", err)
+	}
+
+	return s.tokenResponse(userId, user.Roles, session, refreshToken, now)
+}
+
+// RefreshToken troca um refresh token válido por um novo par de tokens. O token apresentado deixa
+// de valer; se ele for apresentado de novo, a sessão inteira é revogada, pois indica que o token
+// vazou e está sendo usado por duas partes.
+func (s *SessionService) RefreshToken(ctx context.Context, req *api.RefreshTokenRequest) (*api.TokenResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O refresh token é obrigatório")
+	}
+
+	tokenHash := tokens.HashRefreshToken(req.RefreshToken)
+	session, err := s.sessionRepo.GetSessionByTokenHash(ctx, tokenHash)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, status.Errorf(codes.Unauthenticated, "Refresh token inválido")
+		}
+		logger.Error("Erro ao buscar sessão: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "Erro ao buscar sessão: %v", err)
+	}
+
+	sessionId := session.Id.Hex()
+	userId := session.UserId.Hex()
+	now := time.Now()
+
+	if !session.RevokedAt.IsZero() {
+		return nil, status.Errorf(codes.Unauthenticated, "Sessão encerrada")
+	}
+
+	if session.TokenHash != tokenHash {
+		return nil, s.revokeForReuse(ctx, sessionId, userId, now)
+	}
+
+	if !now.Before(session.ExpiresAt) {
+		return nil, status.Errorf(codes.Unauthenticated, "Sessão expirada")
+	}
+
+	// Os papéis são relidos a cada renovação, para que a remoção de um papel valha no próximo token.
+	user, err := s.userRepo.GetUser(ctx, userId)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			s.revoke(ctx, sessionId, "usuário removido", now)
+			return nil, status.Errorf(codes.Unauthenticated, "Sessão encerrada")
+		}
+		return nil, status.Errorf(codes.Internal, "Erro ao obter usuário: %v", err)
+	}
+
+	refreshToken, err := tokens.NewRefreshToken()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Erro ao gerar refresh token: %v", err)
+	}
+
+	rotated, err := s.sessionRepo.RotateSessionToken(ctx, sessionId, tokenHash, tokens.HashRefreshToken(refreshToken), sessionClientFromContext(ctx), now)
+	if err != nil {
+		logger.Error("Erro ao renovar sessão: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "Erro ao renovar sessão: %v", err)
+	}
+	if !rotated {
+		// Outra requisição trocou este mesmo token primeiro.
+		return nil, s.revokeForReuse(ctx, sessionId, userId, now)
+	}
+
+	logger.Info("Sessão renovada: ID: " + sessionId + ", Usuário: " + userId)
+	return s.tokenResponse(userId, user.Roles, session, refreshToken, now)
+}
+
+func (s *SessionService) GetPublicKeys(ctx context.Context, req *api.GetPublicKeysRequest) (*api.GetPublicKeysResponse, error) {
+	response := &api.GetPublicKeysResponse{}
+	for _, key := range s.issuer.PublicKeys() {
+		response.Keys = append(response.Keys, &api.JsonWebKey{
+			Kty: key.Kty,
+			Kid: key.Kid,
+			Use: key.Use,
+			Alg: key.Alg,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+		})
+	}
+	return response, nil
+}
+
+func (s *SessionService) ListSessions(ctx context.Context, req *api.ListSessionsRequest) (*api.ListSessionsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O ID do usuário é obrigatório")
+	}
+
+	claims, err := authorizeUser(ctx, s.issuer, req.UserId, sessionStaffRoles...)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.ListActiveSessions(ctx, req.UserId, time.Now())
+	if err != nil {
+		logger.Error("Erro ao listar sessões: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "Erro ao listar sessões: %v", err)
+	}
+
+	response := &api.ListSessionsResponse{}
+	for _, session := range sessions {
+		apiSession := session.ToProto()
+		apiSession.Current = claims.Subject == req.UserId && apiSession.Id == claims.SessionId
+		response.Sessions = append(response.Sessions, apiSession)
+	}
+
+	return response, nil
+}
+
+func (s *SessionService) RevokeSession(ctx context.Context, req *api.RevokeSessionRequest) (*api.RevokeSessionResponse, error) {
+	if req.UserId == "" || req.SessionId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O ID do usuário e o ID da sessão são obrigatórios")
+	}
+
+	claims, err := authorizeUser(ctx, s.issuer, req.UserId, sessionStaffRoles...)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.GetSession(ctx, req.SessionId)
+	if err != nil {
+		if code := status.Code(err); code == codes.NotFound || code == codes.InvalidArgument {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Internal, "Erro ao buscar sessão: %v", err)
+	}
+
+	// Sessões de outros usuários são tratadas como inexistentes.
+	if session.UserId.Hex() != req.UserId {
+		return nil, status.Errorf(codes.NotFound, "Sessão não encontrada")
+	}
+
+	if err := s.sessionRepo.RevokeSession(ctx, req.SessionId, revokedBy(claims, req.UserId, "revogada"), time.Now()); err != nil {
+		logger.Error("Erro ao revogar sessão: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "Erro ao revogar sessão: %v", err)
+	}
+
+	logger.Info("Sessão revogada: ID: " + req.SessionId + ", Usuário: " + req.UserId)
+	return &api.RevokeSessionResponse{Message: "Sessão revogada com sucesso"}, nil
+}
+
+func (s *SessionService) RevokeAllSessions(ctx context.Context, req *api.RevokeAllSessionsRequest) (*api.RevokeAllSessionsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "O ID do usuário é obrigatório")
+	}
+
+	claims, err := authorizeUser(ctx, s.issuer, req.UserId, sessionStaffRoles...)
+	if err != nil {
+		return nil, err
+	}
+
+	// A sessão atual só pertence ao usuário quando é ele quem chama.
+	exceptSessionId := ""
+	if req.KeepCurrent && claims.Subject == req.UserId {
+		exceptSessionId = claims.SessionId
+	}
+
+	revoked, err := s.revokeUserSessions(ctx, req.UserId, exceptSessionId, revokedBy(claims, req.UserId, "todas as sessões revogadas"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.RevokeAllSessionsResponse{
+		RevokedCount: int32(revoked),
+		Message:      fmt.Sprintf("%d sessão(ões) revogada(s)", revoked),
+	}, nil
+}
+
+// RevokeOtherSessions revoga todas as sessões do usuário exceto a da chamada atual, identificada
+// pelo token de acesso enviado nos metadados.
+func (s *SessionService) RevokeOtherSessions(ctx context.Context, userId string) (int, error) {
+	return s.revokeUserSessions(ctx, userId, s.currentSessionId(ctx), "credenciais alteradas")
+}
+
+// RevokeAllUserSessions revoga todas as sessões do usuário, inclusive a da chamada atual.
+func (s *SessionService) RevokeAllUserSessions(ctx context.Context, userId string, reason string) (int, error) {
+	return s.revokeUserSessions(ctx, userId, "", reason)
+}
+
+func (s *SessionService) revokeUserSessions(ctx context.Context, userId string, exceptSessionId string, reason string) (int, error) {
+	revoked, err := s.sessionRepo.RevokeUserSessions(ctx, userId, exceptSessionId, reason, time.Now())
+	if err != nil {
+		logger.Error("Erro ao revogar sessões do usuário " + userId + ": " + err.Error())
+		return 0, status.Errorf(codes.Internal, "Erro ao revogar sessões: %v", err)
+	}
+
+	logger.Info(fmt.Sprintf("%d sessão(ões) do usuário %s revogada(s): %s", revoked, userId, reason))
+	return int(revoked), nil
+}
+
+// revokedBy completa o motivo da revogação com quem a pediu: o próprio usuário ou a equipe.
+func revokedBy(claims *tokens.AccessTokenClaims, userId string, reason string) string {
+	if claims.Subject == userId {
+		return reason + " pelo usuário"
+	}
+	return reason + " pela equipe (" + claims.Subject + ")"
+}
+
+// currentSessionId retorna a sessão do token de acesso da chamada, ou "" se não houver token válido.
+func (s *SessionService) currentSessionId(ctx context.Context) string {
+	token := bearerToken(ctx)
+	if token == "" {
+		return ""
+	}
+
+	claims, err := s.issuer.VerifyAccessToken(token)
+	if err != nil {
+		return ""
+	}
+	return claims.SessionId
+}
+
+func (s *SessionService) tokenResponse(userId string, roles []string, session *model.Session, refreshToken string, now time.Time) (*api.TokenResponse, error) {
+	accessToken, accessTokenExpiresAt, err := s.issuer.IssueAccessToken(userId, session.Id.Hex(), roles, now)
+	if err != nil {
+		logger.Error("Erro ao emitir token de acesso: " + err.Error())
+		return nil, status.Errorf(codes.Internal, "Erro ao emitir token de acesso: %v", err)
+	}
+
+	return &api.TokenResponse{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  timestamppb.New(accessTokenExpiresAt),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: timestamppb.New(session.ExpiresAt),
+		TokenType:             "Bearer",
+	}, nil
+}
+
+func (s *SessionService) revokeForReuse(ctx context.Context, sessionId string, userId string, now time.Time) error {
+	logger.Error("Reutilização de refresh token detectada: sessão " + sessionId + ", usuário " + userId + ". Sessão revogada")
+	s.revoke(ctx, sessionId, "refresh token reutilizado", now)
+	return status.Errorf(codes.Unauthenticated, "Refresh token inválido")
+}
+
+func (s *SessionService) revoke(ctx context.Context, sessionId string, reason string, now time.Time) {
+	if err := s.sessionRepo.RevokeSession(ctx, sessionId, reason, now); err != nil {
+		logger.Error("Erro ao revogar sessão " + sessionId + ": " + err.Error())
+	}
+}
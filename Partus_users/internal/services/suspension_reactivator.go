@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/go-logger/logger"
+	"github.com/jonh-dev/partus_users/internal/config"
+)
+
+type expiredSuspensionReactivator interface {
+	ReactivateExpiredSuspensions(ctx context.Context, now time.Time) (int, error)
+}
+
+// SuspensionReactivator reativa periodicamente as contas cuja suspensão temporária terminou. O
+// login também encerra a suspensão vencida, então o intervalo só atrasa o status exibido.
+type SuspensionReactivator struct {
+	accountInfoService expiredSuspensionReactivator
+	policy             *config.SuspensionPolicy
+}
+
+func NewSuspensionReactivator(accountInfoService expiredSuspensionReactivator, policy *config.SuspensionPolicy) *SuspensionReactivator {
+	return &SuspensionReactivator{accountInfoService: accountInfoService, policy: policy}
+}
+
+// Start executa a reativação imediatamente e depois a cada policy.CheckInterval, até ctx ser cancelado.
+func (r *SuspensionReactivator) Start(ctx context.Context) {
+	logger.Info(fmt.Sprintf("Reativação de contas suspensas iniciada: intervalo de %s", r.policy.CheckInterval))
+
+	ticker := time.NewTicker(r.policy.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		r.ReactivateOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Reativação de contas suspensas encerrada")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *SuspensionReactivator) ReactivateOnce(ctx context.Context) {
+	reactivated, err := r.accountInfoService.ReactivateExpiredSuspensions(ctx, time.Now())
+	if err != nil {
+		logger.Error("Erro ao reativar as contas suspensas: " + err.Error())
+		return
+	}
+
+	if reactivated > 0 {
+		logger.Info(fmt.Sprintf("%d conta(s) reativada(s) após o fim da suspensão", reactivated))
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"encoding/base64"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// pageToken é o conteúdo do pageToken de ListUsers. A ordenação faz parte do token para que ele
+// não seja reaproveitado em uma consulta ordenada de outra forma.
+type pageToken struct {
+	SortField  string             `bson:"s"`
+	Descending bool               `bson:"d"`
+	SortValue  interface{}        `bson:"v"`
+	LastId     primitive.ObjectID `bson:"id"`
+}
+
+func encodePageToken(sortField repositories.UserSortField, descending bool, last *model.User) (string, error) {
+	token := pageToken{
+		SortField:  string(sortField),
+		Descending: descending,
+		SortValue:  sortValue(sortField, last),
+		LastId:     last.Id,
+	}
+
+	data, err := bson.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodePageToken retorna ok=false quando o token é inválido ou foi gerado com outra ordenação.
+func decodePageToken(raw string, sortField repositories.UserSortField, descending bool) (*repositories.UserCursor, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, false
+	}
+
+	var token pageToken
+	if err := bson.Unmarshal(data, &token); err != nil {
+		return nil, false
+	}
+
+	if token.SortField != string(sortField) || token.Descending != descending || token.LastId.IsZero() {
+		return nil, false
+	}
+
+	return &repositories.UserCursor{SortValue: token.SortValue, LastId: token.LastId}, true
+}
+
+func sortValue(sortField repositories.UserSortField, user *model.User) interface{} {
+	switch sortField {
+	case repositories.UserSortByCreatedAt:
+		return user.AccountInfo.CreatedAt
+	case repositories.UserSortByEmail:
+		return user.PersonalInfo.Email
+	case repositories.UserSortByLastName:
+		return user.PersonalInfo.LastName
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jonh-dev/go-logger/logger"
+	"github.com/jonh-dev/partus_users/internal/config"
+)
+
+type deletedUserPurger interface {
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int, error)
+}
+
+type UserPurger struct {
+	userService deletedUserPurger
+	policy      *config.PurgePolicy
+}
+
+func NewUserPurger(userService deletedUserPurger, policy *config.PurgePolicy) *UserPurger {
+	return &UserPurger{userService: userService, policy: policy}
+}
+
+// Start executa a limpeza imediatamente e depois a cada policy.Interval, até ctx ser cancelado.
+func (p *UserPurger) Start(ctx context.Context) {
+	logger.Info(fmt.Sprintf("Limpeza de usuários removidos iniciada: retenção de %s, intervalo de %s", p.policy.Retention, p.policy.Interval))
+
+	ticker := time.NewTicker(p.policy.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.PurgeOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Limpeza de usuários removidos encerrada")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *UserPurger) PurgeOnce(ctx context.Context) {
+	deletedBefore := time.Now().Add(-p.policy.Retention)
+	logger.Info("Buscando usuários removidos antes de " + deletedBefore.Format(time.RFC3339))
+
+	purged, err := p.userService.PurgeDeletedUsers(ctx, deletedBefore)
+	if err != nil {
+		logger.Error("Erro ao remover definitivamente os usuários: " + err.Error())
+		return
+	}
+
+	logger.Info(fmt.Sprintf("Limpeza concluída: %d usuário(s) removido(s) definitivamente", purged))
+}
@@ -3,35 +3,74 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/jonh-dev/go-error/errors"
 	"github.com/jonh-dev/go-logger/logger"
 	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/config"
 	"github.com/jonh-dev/partus_users/internal/converters"
+	"github.com/jonh-dev/partus_users/internal/model"
 	"github.com/jonh-dev/partus_users/internal/repositories"
 	"github.com/jonh-dev/partus_users/internal/utils"
+	"github.com/jonh-dev/partus_users/internal/validation"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type UserService interface {
 	CreateUser(ctx context.Context, req *api.CreateUserRequest) (*api.UserResponse, error)
 	GetUser(ctx context.Context, req *api.GetUserRequest) (*api.UserResponse, error)
+	GetUserByEmail(ctx context.Context, req *api.GetUserByEmailRequest) (*api.UserResponse, error)
+	GetUserByUsername(ctx context.Context, req *api.GetUserByUsernameRequest) (*api.UserResponse, error)
+	ListUsers(ctx context.Context, req *api.ListUsersRequest) (*api.ListUsersResponse, error)
+	UpdateUser(ctx context.Context, req *api.UpdateUserRequest) (*api.UserResponse, error)
 	DeleteUser(ctx context.Context, req *api.DeleteUserRequest) (*api.UserResponse, error)
+	RestoreUser(ctx context.Context, req *api.RestoreUserRequest) (*api.UserResponse, error)
 	HandleFailedLogin(ctx context.Context, req *api.HandleFailedLoginRequest) (*api.UserResponse, error)
+	Login(ctx context.Context, req *api.LoginRequest) (*api.LoginResponse, error)
+	VerifyLoginMFA(ctx context.Context, req *api.VerifyLoginMFARequest) (*api.LoginResponse, error)
+}
+
+const (
+	defaultListUsersPageSize = 20
+	maxListUsersPageSize     = 100
+)
+
+var userSortFields = map[api.UserSortField]repositories.UserSortField{
+	api.UserSortField_SORT_BY_ID:         repositories.UserSortById,
+	api.UserSortField_SORT_BY_CREATED_AT: repositories.UserSortByCreatedAt,
+	api.UserSortField_SORT_BY_EMAIL:      repositories.UserSortByEmail,
+	api.UserSortField_SORT_BY_LAST_NAME:  repositories.UserSortByLastName,
 }
 
 type userService struct {
 	userRepo            repositories.IUserRepository
 	personalInfoService IPersonalInfoService
 	accountInfoService  IAccountInfoService
+	txRunner            config.TransactionRunner
+	sessionService      ISessionService
+	mfaService          IMFAService
+	emailVerification   IEmailVerificationService
+	mfaRepo             repositories.IMFARepository
+	passwordResetRepo   repositories.IPasswordResetRepository
 }
 
-func NewUserService(userRepo repositories.IUserRepository, personalInfoService IPersonalInfoService, accountInfoService IAccountInfoService) *userService {
+func NewUserService(userRepo repositories.IUserRepository, personalInfoService IPersonalInfoService, accountInfoService IAccountInfoService, txRunner config.TransactionRunner, sessionService ISessionService, mfaService IMFAService, emailVerification IEmailVerificationService, mfaRepo repositories.IMFARepository, passwordResetRepo repositories.IPasswordResetRepository) *userService {
 	return &userService{
 		userRepo:            userRepo,
 		personalInfoService: personalInfoService,
 		accountInfoService:  accountInfoService,
+		txRunner:            txRunner,
+		sessionService:      sessionService,
+		mfaService:          mfaService,
+		emailVerification:   emailVerification,
+		mfaRepo:             mfaRepo,
+		passwordResetRepo:   passwordResetRepo,
 	}
 }
 
@@ -42,36 +81,34 @@ func (s *userService) CreateUser(ctx context.Context, req *api.CreateUserRequest
 		return nil, errors.New(codes.Internal, "Erro ao converter o usuário para o modelo: "+err.Error())
 	}
 
-	apiPersonalInfo := modelUser.PersonalInfo.ToProto()
-	_, err = s.personalInfoService.CreatePersonalInfo(ctx, apiPersonalInfo)
-	if err != nil {
-		if e, ok := err.(*errors.Error); ok {
-			logger.Error("Erro ao criar usuário: " + e.Error())
-			return nil, errors.New(e.GRPCStatus().Code(), "Erro ao criar usuário: "+e.Error())
-		}
-		logger.Error(err.Error())
-		return nil, err
+	// Toda conta nova fica pendente até o e-mail ser verificado; os campos de status, bloqueio e
+	// histórico enviados pelo cliente são descartados.
+	modelUser.AccountInfo = model.AccountInfo{
+		UserId:        modelUser.AccountInfo.UserId,
+		Username:      modelUser.AccountInfo.Username,
+		Password:      modelUser.AccountInfo.Password,
+		AccountStatus: model.AccountStatus_PENDING,
+		StatusReason:  pendingEmailVerificationReason,
 	}
+	modelUser.PersonalInfo.EmailVerifiedAt = time.Time{}
 
-	apiAccountInfo := modelUser.AccountInfo.ToProto()
-	_, err = s.accountInfoService.CreateAccountInfo(ctx, apiAccountInfo)
+	var user *model.User
+	err = s.txRunner.WithTransaction(ctx, func(ctx context.Context) error {
+		user, err = s.createUserDocuments(ctx, modelUser)
+		return err
+	})
 	if err != nil {
-		if e, ok := err.(*errors.Error); ok {
-			return nil, e.GRPCStatus().Err()
-		}
-		return nil, errors.New(codes.Internal, "Erro desconhecido ao criar AccountInfo")
+		return nil, err
 	}
 
-	modelUser.AccountInfo.Password = apiAccountInfo.Password
-	modelUser.AccountInfo.CreatedAt = apiAccountInfo.CreatedAt.AsTime()
+	apiUser := user.ToResponseProto()
 
-	user, err := s.userRepo.CreateUser(ctx, modelUser)
+	// A conta já foi criada; uma falha no envio não desfaz o cadastro e o e-mail pode ser reenviado.
+	err = s.emailVerification.SendVerificationEmail(ctx, apiUser.Id, apiUser.PersonalInfo.Email, apiUser.PersonalInfo.FirstName, apiUser.PersonalInfo.TimeZone)
 	if err != nil {
-		return nil, errors.New(codes.Internal, "Erro ao criar o usuário: "+err.Error())
+		logger.Error("Erro ao enviar o e-mail de verificação do usuário " + apiUser.Id + ": " + err.Error())
 	}
 
-	apiUser := user.ToProto()
-
 	logger.Success(fmt.Sprintf("Usuário criado com sucesso: ID: %s, Nome: %s %s, Email: %s", apiUser.Id, apiUser.PersonalInfo.FirstName, apiUser.PersonalInfo.LastName, apiUser.PersonalInfo.Email))
 	return &api.UserResponse{
 		User:    apiUser,
@@ -79,10 +116,52 @@ func (s *userService) CreateUser(ctx context.Context, req *api.CreateUserRequest
 	}, nil
 }
 
+// createUserDocuments grava personal_info, account_info e users. Deve ser chamado dentro de uma
+// transação para que uma falha em qualquer etapa, inclusive na verificação de email duplicado,
+// não deixe documentos órfãos. Como a transação pode ser repetida, cada tentativa trabalha sobre
+// uma cópia do usuário para não aplicar o hash da senha duas vezes.
+func (s *userService) createUserDocuments(ctx context.Context, modelUser *model.User) (*model.User, error) {
+	user := *modelUser
+
+	apiPersonalInfo := user.PersonalInfo.ToProto()
+	_, err := s.personalInfoService.CreatePersonalInfo(ctx, apiPersonalInfo)
+	if err != nil {
+		logger.Error("Erro ao criar usuário: " + err.Error())
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, validation.PrefixFields(err, "user.personal_info", "Erro ao criar usuário")
+		}
+		return nil, errors.New(status.Code(err), "Erro ao criar usuário: "+err.Error())
+	}
+
+	apiAccountInfo := user.AccountInfo.ToProto()
+	_, err = s.accountInfoService.CreateAccountInfo(ctx, apiAccountInfo)
+	if err != nil {
+		logger.Error("Erro ao criar AccountInfo: " + err.Error())
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, validation.PrefixFields(err, "user.account_info", "Erro ao criar AccountInfo")
+		}
+		return nil, errors.New(status.Code(err), "Erro ao criar AccountInfo: "+err.Error())
+	}
+
+	user.AccountInfo.Password = apiAccountInfo.Password
+	user.AccountInfo.CreatedAt = apiAccountInfo.CreatedAt.AsTime()
+
+	createdUser, err := s.userRepo.CreateUser(ctx, &user)
+	if err != nil {
+		logger.Error("Erro ao criar o usuário: " + err.Error())
+		if status.Code(err) == codes.AlreadyExists {
+			return nil, errors.New(codes.AlreadyExists, "Erro ao criar o usuário: "+err.Error())
+		}
+		return nil, errors.New(codes.Internal, "Erro ao criar o usuário: "+err.Error())
+	}
+
+	return createdUser, nil
+}
+
 func (s *userService) GetUser(ctx context.Context, req *api.GetUserRequest) (*api.UserResponse, error) {
 	modelUser, err := s.userRepo.GetUser(ctx, req.Id)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao obter User: %w", err)
+		return nil, err
 	}
 
 	objectId, err := primitive.ObjectIDFromHex(req.Id)
@@ -113,9 +192,7 @@ func (s *userService) GetUser(ctx context.Context, req *api.GetUserRequest) (*ap
 	}
 	modelUser.AccountInfo = *modelAccountInfo
 
-	modelUser.AccountInfo.CreatedAt = utils.ReadjustToSaoPaulo(modelUser.AccountInfo.CreatedAt)
-
-	apiUser := modelUser.ToProto()
+	apiUser := modelUser.ToResponseProto()
 
 	return &api.UserResponse{
 		User:    apiUser,
@@ -123,14 +200,362 @@ func (s *userService) GetUser(ctx context.Context, req *api.GetUserRequest) (*ap
 	}, nil
 }
 
+func (s *userService) GetUserByEmail(ctx context.Context, req *api.GetUserByEmailRequest) (*api.UserResponse, error) {
+	if strings.TrimSpace(req.Email) == "" {
+		return nil, errors.New(codes.InvalidArgument, "O e-mail é obrigatório")
+	}
+
+	personalInfo, err := s.personalInfoService.GetPersonalInfoByEmail(ctx, req.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(ctx, &api.GetUserRequest{Id: personalInfo.UserId})
+}
+
+func (s *userService) GetUserByUsername(ctx context.Context, req *api.GetUserByUsernameRequest) (*api.UserResponse, error) {
+	if strings.TrimSpace(req.Username) == "" {
+		return nil, errors.New(codes.InvalidArgument, "O username é obrigatório")
+	}
+
+	accountInfo, err := s.accountInfoService.GetAccountInfoByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetUser(ctx, &api.GetUserRequest{Id: accountInfo.UserId})
+}
+
+func (s *userService) ListUsers(ctx context.Context, req *api.ListUsersRequest) (*api.ListUsersResponse, error) {
+	pageSize := int(req.PageSize)
+	if pageSize < 0 || pageSize > maxListUsersPageSize {
+		return nil, errors.New(codes.InvalidArgument, fmt.Sprintf("O page_size deve estar entre 0 e %d", maxListUsersPageSize))
+	}
+	if pageSize == 0 {
+		pageSize = defaultListUsersPageSize
+	}
+
+	sortField, ok := userSortFields[req.SortBy]
+	if !ok {
+		return nil, errors.New(codes.InvalidArgument, "Campo de ordenação inválido")
+	}
+
+	query := repositories.ListUsersQuery{
+		EmailDomain: strings.TrimPrefix(strings.TrimSpace(req.EmailDomain), "@"),
+		NamePrefix:  strings.TrimSpace(req.NamePrefix),
+		SortField:   sortField,
+		Descending:  req.Descending,
+		// Um usuário a mais indica se existe uma próxima página.
+		Limit: int64(pageSize) + 1,
+	}
+
+	for _, accountStatus := range req.AccountStatuses {
+		query.AccountStatuses = append(query.AccountStatuses, model.AccountStatus(accountStatus))
+	}
+
+	if req.CreatedAfter != nil {
+		query.CreatedAfter = req.CreatedAfter.AsTime()
+	}
+	if req.CreatedBefore != nil {
+		query.CreatedBefore = req.CreatedBefore.AsTime()
+	}
+	if !query.CreatedAfter.IsZero() && !query.CreatedBefore.IsZero() && !query.CreatedAfter.Before(query.CreatedBefore) {
+		return nil, errors.New(codes.InvalidArgument, "O created_after deve ser anterior ao created_before")
+	}
+
+	if req.PageToken != "" {
+		cursor, ok := decodePageToken(req.PageToken, sortField, req.Descending)
+		if !ok {
+			return nil, errors.New(codes.InvalidArgument, "O pageToken é inválido")
+		}
+		query.After = cursor
+	}
+
+	users, err := s.userRepo.ListUsers(ctx, query)
+	if err != nil {
+		logger.Error("Erro ao listar usuários: " + err.Error())
+		return nil, errors.New(codes.Internal, "Erro ao listar usuários: "+err.Error())
+	}
+
+	response := &api.ListUsersResponse{}
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		response.NextPageToken, err = encodePageToken(sortField, req.Descending, users[len(users)-1])
+		if err != nil {
+			return nil, errors.New(codes.Internal, "Erro ao gerar o pageToken: "+err.Error())
+		}
+	}
+
+	for _, user := range users {
+		response.Users = append(response.Users, user.ToResponseProto())
+	}
+
+	return response, nil
+}
+
+func (s *userService) UpdateUser(ctx context.Context, req *api.UpdateUserRequest) (*api.UserResponse, error) {
+	if req.User == nil || req.User.Id == "" {
+		return nil, errors.New(codes.InvalidArgument, "O ID do usuário é obrigatório")
+	}
+
+	fields, err := personalInfoFieldsFromMask(req.UpdateMask)
+	if err != nil {
+		return nil, errors.New(codes.InvalidArgument, err.Error())
+	}
+
+	if _, err := s.userRepo.GetUser(ctx, req.User.Id); err != nil {
+		return nil, err
+	}
+
+	personalInfo := req.User.PersonalInfo
+	if personalInfo == nil {
+		personalInfo = &api.PersonalInfo{}
+	}
+	personalInfo.UserId = req.User.Id
+
+	_, err = s.personalInfoService.PatchPersonalInfo(ctx, personalInfo, fields)
+	if err != nil {
+		logger.Error("Erro ao atualizar usuário: " + err.Error())
+		if status.Code(err) == codes.InvalidArgument {
+			return nil, validation.PrefixFields(err, "user.personal_info", "Erro ao atualizar usuário")
+		}
+		return nil, err
+	}
+
+	response, err := s.GetUser(ctx, &api.GetUserRequest{Id: req.User.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Success(fmt.Sprintf("Usuário atualizado com sucesso: ID: %s, Campos: %s", req.User.Id, strings.Join(fields, ", ")))
+	response.Message = "Usuário atualizado com sucesso"
+	return response, nil
+}
+
+// personalInfoFieldsFromMask converte os caminhos da FieldMask (ex: "personal_info.email") nos
+// nomes dos campos de PersonalInfo. Apenas campos de personal_info podem ser atualizados por aqui.
+func personalInfoFieldsFromMask(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	if mask == nil || len(mask.GetPaths()) == 0 {
+		return nil, fmt.Errorf("updateMask é obrigatório")
+	}
+
+	if !mask.IsValid(&api.User{}) {
+		return nil, fmt.Errorf("updateMask contém caminhos inválidos: %v", mask.GetPaths())
+	}
+	mask.Normalize()
+
+	var fields []string
+	for _, path := range mask.GetPaths() {
+		if path == "personal_info" {
+			return model.PersonalInfoFields, nil
+		}
+
+		field := strings.TrimPrefix(path, "personal_info.")
+		if field == path || field == "userId" {
+			return nil, fmt.Errorf("o campo %s não pode ser atualizado por UpdateUser", path)
+		}
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
 func (s *userService) DeleteUser(ctx context.Context, req *api.DeleteUserRequest) (*api.UserResponse, error) {
-	// Implemente a lógica de exclusão do usuário aqui
-	// ...
-	return nil, nil
+	if req.Id == "" {
+		return nil, errors.New(codes.InvalidArgument, "O ID do usuário é obrigatório")
+	}
+
+	// As sessões são revogadas na mesma transação para que um usuário removido não continue com
+	// tokens de renovação válidos.
+	err := s.txRunner.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.SoftDeleteUser(ctx, req.Id, utils.GetCurrentTimestamp().AsTime()); err != nil {
+			return err
+		}
+		_, err := s.sessionService.RevokeAllUserSessions(ctx, req.Id, "usuário removido")
+		return err
+	})
+	if err != nil {
+		logger.Error("Erro ao remover usuário " + req.Id + ": " + err.Error())
+		if code := status.Code(err); code == codes.NotFound || code == codes.InvalidArgument {
+			return nil, err
+		}
+		return nil, errors.New(codes.Internal, "Erro ao remover o usuário: "+err.Error())
+	}
+
+	logger.Success("Usuário marcado como removido: ID: " + req.Id)
+	return &api.UserResponse{
+		User:    &api.User{Id: req.Id},
+		Message: "Usuário removido com sucesso",
+	}, nil
+}
+
+func (s *userService) RestoreUser(ctx context.Context, req *api.RestoreUserRequest) (*api.UserResponse, error) {
+	if req.Id == "" {
+		return nil, errors.New(codes.InvalidArgument, "O ID do usuário é obrigatório")
+	}
+
+	err := s.userRepo.RestoreUser(ctx, req.Id)
+	if err != nil {
+		logger.Error("Erro ao restaurar usuário " + req.Id + ": " + err.Error())
+		if code := status.Code(err); code == codes.NotFound || code == codes.InvalidArgument {
+			return nil, err
+		}
+		return nil, errors.New(codes.Internal, "Erro ao restaurar o usuário: "+err.Error())
+	}
+
+	response, err := s.GetUser(ctx, &api.GetUserRequest{Id: req.Id})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Success("Usuário restaurado com sucesso: ID: " + req.Id)
+	response.Message = "Usuário restaurado com sucesso"
+	return response, nil
+}
+
+// PurgeDeletedUsers remove definitivamente os usuários marcados como removidos antes de
+// deletedBefore, cada um em sua própria transação. Falhas individuais são registradas e não
+// interrompem a limpeza dos demais.
+func (s *userService) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int, error) {
+	ids, err := s.userRepo.FindDeletedUserIds(ctx, deletedBefore)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		err := s.txRunner.WithTransaction(ctx, func(ctx context.Context) error {
+			return s.deleteUserCascade(ctx, id, deletedBefore)
+		})
+		if err != nil {
+			logger.Error("Erro ao remover definitivamente o usuário " + id + ": " + err.Error())
+			continue
+		}
+
+		logger.Info("Usuário removido definitivamente: ID: " + id)
+		purged++
+	}
+
+	return purged, nil
+}
+
+// deleteUserCascade remove o usuário, seus documentos de personal_info e account_info, o MFA (com o
+// segredo TOTP, os códigos de recuperação e os desafios) e os pedidos de redefinição de senha. Deve
+// ser chamado dentro de uma transação. A ausência de um subdocumento não impede a remoção.
+func (s *userService) deleteUserCascade(ctx context.Context, id string, deletedBefore time.Time) error {
+	if err := s.userRepo.PurgeUser(ctx, id, deletedBefore); err != nil {
+		return err
+	}
+
+	err := s.personalInfoService.DeletePersonalInfo(ctx, &api.DeletePersonalInfoRequest{UserId: id})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return err
+	}
+
+	err = s.accountInfoService.DeleteAccountInfo(ctx, &api.DeleteAccountInfoRequest{UserId: id})
+	if err != nil && status.Code(err) != codes.NotFound {
+		return err
+	}
+
+	if err := s.mfaRepo.DeleteMFA(ctx, id); err != nil {
+		return err
+	}
+
+	return s.passwordResetRepo.DeleteUserPasswordResets(ctx, id)
 }
 
 func (s *userService) HandleFailedLogin(ctx context.Context, req *api.HandleFailedLoginRequest) (*api.UserResponse, error) {
-	// Implemente a lógica de manipulação de falha de login aqui
-	// ...
-	return nil, nil
+	accountInfo, err := s.accountInfoService.RegisterFailedLogin(ctx, req.Username, req.Reason)
+	if err != nil {
+		logger.Error("Erro ao registrar tentativa de login falhada: " + err.Error())
+		return nil, err
+	}
+
+	logger.Info(fmt.Sprintf("Tentativa de login falhada registrada: ID: %s, Tentativas: %d", accountInfo.UserId, accountInfo.FailedLoginAttempts))
+	return &api.UserResponse{
+		User: &api.User{
+			Id:          accountInfo.UserId,
+			AccountInfo: converters.ToAccountInfoResponse(accountInfo),
+		},
+		Message: "Tentativa de login falhada registrada com sucesso",
+	}, nil
+}
+
+func (s *userService) Login(ctx context.Context, req *api.LoginRequest) (*api.LoginResponse, error) {
+	if req.Username == "" || req.Password == "" {
+		return nil, errors.New(codes.InvalidArgument, "O nome de usuário e a senha são obrigatórios")
+	}
+
+	accountInfo, err := s.accountInfoService.Authenticate(ctx, req.Username, req.Password)
+	if err != nil {
+		logger.Error("Falha no login do usuário " + req.Username + ": " + err.Error())
+		return nil, err
+	}
+
+	mfaEnabled, err := s.mfaService.IsMFAEnabled(ctx, accountInfo.UserId)
+	if err != nil {
+		logger.Error("Erro ao verificar o MFA do usuário " + accountInfo.UserId + ": " + err.Error())
+		return nil, err
+	}
+
+	// Com MFA ativo, a senha correta só libera o desafio; os tokens são emitidos por VerifyLoginMFA.
+	if mfaEnabled {
+		challengeToken, expiresAt, err := s.mfaService.CreateChallenge(ctx, accountInfo.UserId)
+		if err != nil {
+			logger.Error("Erro ao criar o desafio de MFA: " + err.Error())
+			return nil, err
+		}
+
+		logger.Info("Login aguardando verificação em duas etapas: ID: " + accountInfo.UserId)
+		return &api.LoginResponse{
+			Message:               "Informe o código de verificação em duas etapas",
+			MfaRequired:           true,
+			MfaChallengeToken:     challengeToken,
+			MfaChallengeExpiresAt: timestamppb.New(expiresAt),
+		}, nil
+	}
+
+	return s.completeLogin(ctx, accountInfo.UserId)
+}
+
+func (s *userService) VerifyLoginMFA(ctx context.Context, req *api.VerifyLoginMFARequest) (*api.LoginResponse, error) {
+	userId, err := s.mfaService.VerifyChallenge(ctx, req.MfaChallengeToken, req.Code)
+	if err != nil {
+		logger.Error("Falha na verificação em duas etapas: " + err.Error())
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, userId)
+}
+
+// completeLogin carrega o usuário, zera as falhas de login e abre a sessão depois que todos os
+// fatores foram verificados.
+func (s *userService) completeLogin(ctx context.Context, userId string) (*api.LoginResponse, error) {
+	userResponse, err := s.GetUser(ctx, &api.GetUserRequest{Id: userId})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			logger.Error("Tentativa de login em usuário removido: ID: " + userId)
+			return nil, errors.New(codes.Unauthenticated, "Usuário ou senha inválidos")
+		}
+		logger.Error("Erro ao obter o usuário após o login: " + err.Error())
+		return nil, errors.New(codes.Internal, "Erro ao obter o usuário após o login: "+err.Error())
+	}
+
+	if err := s.accountInfoService.RegisterSuccessfulLogin(ctx, userId); err != nil {
+		logger.Error("Erro ao registrar o login: " + err.Error())
+		return nil, err
+	}
+
+	tokens, err := s.sessionService.CreateSession(ctx, userId)
+	if err != nil {
+		logger.Error("Erro ao criar a sessão após o login: " + err.Error())
+		return nil, err
+	}
+
+	logger.Success(fmt.Sprintf("Login realizado com sucesso: ID: %s, Username: %s", userId, userResponse.User.AccountInfo.GetUsername()))
+	return &api.LoginResponse{
+		User:    userResponse.User,
+		Message: "Login realizado com sucesso",
+		Tokens:  tokens,
+	}, nil
 }
@@ -0,0 +1,178 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/audit"
+	"github.com/jonh-dev/partus_users/internal/model"
+	configMocks "github.com/jonh-dev/partus_users/internal/tests/mocks/config"
+	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTransactionRunner() *configMocks.MockTransactionRunner {
+	txRunner := new(configMocks.MockTransactionRunner)
+	txRunner.On("WithTransaction", mock.Anything).Return(nil)
+	return txRunner
+}
+
+type listenerFunc func(ctx context.Context, event *model.AuditEvent) error
+
+func (f listenerFunc) OnAuditEvent(ctx context.Context, event *model.AuditEvent) error {
+	return f(ctx, event)
+}
+
+func newChain(size int) []*model.AuditEvent {
+	events := []*model.AuditEvent{}
+	previousHash := ""
+	for i := 1; i <= size; i++ {
+		event := &model.AuditEvent{
+			Sequence:   int64(i),
+			Collection: "account_info",
+			DocumentId: "507f1f77bcf86cd799439011",
+			Operation:  model.AuditOperationUpdate,
+			Actor:      "507f1f77bcf86cd799439011",
+			Changes:    []model.AuditChange{{Field: "username", Before: "antigo", After: "novo"}},
+			OccurredAt: time.Date(2024, 5, 1, 12, 0, i, 0, time.UTC),
+			PrevHash:   previousHash,
+		}
+		event.Hash = audit.ComputeHash(event)
+		previousHash = event.Hash
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestVerifyChain(t *testing.T) {
+	t.Run("Cadeia íntegra", func(t *testing.T) {
+		assert.NoError(t, audit.VerifyChain(newChain(3)))
+	})
+
+	t.Run("Evento alterado", func(t *testing.T) {
+		events := newChain(3)
+		events[1].Changes[0].After = "adulterado"
+
+		assert.True(t, errors.Is(audit.VerifyChain(events), audit.ErrBrokenChain))
+	})
+
+	t.Run("Evento removido", func(t *testing.T) {
+		events := newChain(3)
+
+		assert.True(t, errors.Is(audit.VerifyChain([]*model.AuditEvent{events[0], events[2]}), audit.ErrBrokenChain))
+	})
+
+	t.Run("Evento recalculado sem atualizar o seguinte", func(t *testing.T) {
+		events := newChain(3)
+		events[1].Actor = "outro"
+		events[1].Hash = audit.ComputeHash(events[1])
+
+		assert.True(t, errors.Is(audit.VerifyChain(events), audit.ErrBrokenChain))
+	})
+
+	t.Run("Horário em outro fuso gera o mesmo hash", func(t *testing.T) {
+		events := newChain(1)
+		events[0].OccurredAt = events[0].OccurredAt.In(time.FixedZone("BRT", -3*60*60))
+
+		assert.NoError(t, audit.VerifyChain(events))
+	})
+}
+
+func TestRecorder_Record(t *testing.T) {
+	metadata := audit.Metadata{Actor: "507f1f77bcf86cd799439012", Method: "/api.AccountInfoService/UpdateAccountInfo", RequestId: "req-1"}
+	ctx := audit.WithMetadata(context.Background(), metadata)
+	changes := []model.AuditChange{{Field: "username", Before: "antigo", After: "novo"}}
+
+	t.Run("Primeiro evento da cadeia", func(t *testing.T) {
+		mockRepo := new(mocks.MockAuditRepository)
+		mockRepo.On("GetLastAuditEvent", mock.Anything).Return(nil, nil)
+		mockRepo.On("InsertAuditEvent", mock.Anything, mock.MatchedBy(func(event *model.AuditEvent) bool {
+			return event.Sequence == 1 && event.PrevHash == "" && event.Hash == audit.ComputeHash(event) &&
+				event.Actor == metadata.Actor && event.Method == metadata.Method && event.RequestId == metadata.RequestId
+		})).Return(nil)
+
+		err := audit.NewRecorder(mockRepo, newTransactionRunner()).Record(ctx, "account_info", "507f1f77bcf86cd799439011", model.AuditOperationUpdate, changes)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Conflito de sequence é devolvido para a transação repetir", func(t *testing.T) {
+		chain := newChain(1)
+		conflict := errors.New("WriteConflict")
+		mockRepo := new(mocks.MockAuditRepository)
+		mockRepo.On("GetLastAuditEvent", mock.Anything).Return(chain[0], nil).Once()
+		mockRepo.On("InsertAuditEvent", mock.Anything, mock.Anything).Return(conflict).Once()
+		notified := false
+		listener := listenerFunc(func(ctx context.Context, event *model.AuditEvent) error {
+			notified = true
+			return nil
+		})
+
+		err := audit.NewRecorder(mockRepo, newTransactionRunner(), listener).Record(ctx, "account_info", "507f1f77bcf86cd799439011", model.AuditOperationUpdate, changes)
+
+		assert.ErrorIs(t, err, conflict)
+		assert.False(t, notified)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Atualização sem mudanças não é registrada", func(t *testing.T) {
+		mockRepo := new(mocks.MockAuditRepository)
+
+		err := audit.NewRecorder(mockRepo, newTransactionRunner()).Record(ctx, "account_info", "507f1f77bcf86cd799439011", model.AuditOperationUpdate, nil)
+
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "GetLastAuditEvent", mock.Anything)
+	})
+
+	t.Run("Listeners recebem o evento gravado", func(t *testing.T) {
+		mockRepo := new(mocks.MockAuditRepository)
+		mockRepo.On("GetLastAuditEvent", mock.Anything).Return(nil, nil)
+		mockRepo.On("InsertAuditEvent", mock.Anything, mock.Anything).Return(nil)
+
+		var received *model.AuditEvent
+		listener := listenerFunc(func(ctx context.Context, event *model.AuditEvent) error {
+			received = event
+			return nil
+		})
+
+		err := audit.NewRecorder(mockRepo, newTransactionRunner(), listener).Record(ctx, "account_info", "507f1f77bcf86cd799439011", model.AuditOperationUpdate, changes)
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, received) {
+			assert.Equal(t, int64(1), received.Sequence)
+			assert.Equal(t, changes, received.Changes)
+		}
+	})
+
+	t.Run("Erro do listener é retornado", func(t *testing.T) {
+		mockRepo := new(mocks.MockAuditRepository)
+		mockRepo.On("GetLastAuditEvent", mock.Anything).Return(nil, nil)
+		mockRepo.On("InsertAuditEvent", mock.Anything, mock.Anything).Return(nil)
+		listener := listenerFunc(func(ctx context.Context, event *model.AuditEvent) error {
+			return status.Error(codes.Internal, "falha na outbox")
+		})
+
+		err := audit.NewRecorder(mockRepo, newTransactionRunner(), listener).Record(ctx, "account_info", "507f1f77bcf86cd799439011", model.AuditOperationUpdate, changes)
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("Sem metadados a alteração é do próprio serviço", func(t *testing.T) {
+		mockRepo := new(mocks.MockAuditRepository)
+		mockRepo.On("GetLastAuditEvent", mock.Anything).Return(nil, nil)
+		mockRepo.On("InsertAuditEvent", mock.Anything, mock.MatchedBy(func(event *model.AuditEvent) bool {
+			return event.Actor == audit.SystemActor && event.Operation == model.AuditOperationDelete
+		})).Return(nil)
+
+		err := audit.NewRecorder(mockRepo, newTransactionRunner()).Record(context.Background(), "users", "507f1f77bcf86cd799439011", model.AuditOperationDelete, nil)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
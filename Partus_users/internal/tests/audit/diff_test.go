@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/audit"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDiff(t *testing.T) {
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Atualização registra apenas os campos alterados", func(t *testing.T) {
+		before := &api.AccountInfo{UserId: "507f1f77bcf86cd799439011", Username: "antigo", Password: "hash-antigo", AccountStatus: api.AccountStatus_ACTIVE}
+		after := &api.AccountInfo{UserId: "507f1f77bcf86cd799439011", Username: "novo", Password: "hash-novo", AccountStatus: api.AccountStatus_SUSPENDED, UpdatedAt: timestamppb.New(updatedAt)}
+
+		assert.Equal(t, []model.AuditChange{
+			{Field: "username", Before: "antigo", After: "novo"},
+			{Field: "password", Redacted: true},
+			{Field: "accountStatus", Before: "ACTIVE", After: "SUSPENDED"},
+			{Field: "updatedAt", After: "2024-05-01T12:00:00Z"},
+		}, audit.Diff(before, after))
+	})
+
+	t.Run("Criação de usuário percorre os documentos embutidos", func(t *testing.T) {
+		var before *api.User
+		after := &api.User{
+			Id:           "507f1f77bcf86cd799439011",
+			PersonalInfo: &api.PersonalInfo{Email: "joao@example.com"},
+			AccountInfo:  &api.AccountInfo{Password: "hash"},
+		}
+
+		assert.Equal(t, []model.AuditChange{
+			{Field: "id", After: "507f1f77bcf86cd799439011"},
+			{Field: "personalInfo.email", After: "joao@example.com"},
+			{Field: "accountInfo.password", Redacted: true},
+			{Field: "accountInfo.accountStatus", After: "ACTIVE"},
+		}, audit.Diff(before, after))
+	})
+
+	t.Run("Remoção registra os valores anteriores", func(t *testing.T) {
+		before := &api.PersonalInfo{UserId: "507f1f77bcf86cd799439011", FirstName: "João"}
+
+		assert.Equal(t, []model.AuditChange{
+			{Field: "userId", Before: "507f1f77bcf86cd799439011"},
+			{Field: "firstName", Before: "João"},
+		}, audit.Diff(before, nil))
+	})
+
+	t.Run("Listas são comparadas como um valor só", func(t *testing.T) {
+		before := &api.AccountInfo{}
+		after := &api.AccountInfo{StatusHistory: []*api.AccountStatusTransition{{To: api.AccountStatus_SUSPENDED, Reason: "Spam"}}}
+
+		changes := audit.Diff(before, after)
+
+		assert.Equal(t, []model.AuditChange{{Field: "statusHistory", After: `[{"to":"SUSPENDED","reason":"Spam"}]`}}, changes)
+	})
+
+	t.Run("Documentos iguais", func(t *testing.T) {
+		accountInfo := &api.AccountInfo{Username: "joao", Password: "hash"}
+
+		assert.Empty(t, audit.Diff(accountInfo, accountInfo))
+	})
+}
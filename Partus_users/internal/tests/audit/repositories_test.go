@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/audit"
+	"github.com/jonh-dev/partus_users/internal/model"
+	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAccountInfoRepository_ChangePassword(t *testing.T) {
+	userId := "507f1f77bcf86cd799439011"
+	updatedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	ctx := audit.WithMetadata(context.Background(), audit.Metadata{Actor: userId, Method: "/api.AccountInfoService/ChangePassword", RequestId: "req-1"})
+
+	t.Run("Senha alterada é registrada sem o hash", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockAuditRepo := new(mocks.MockAuditRepository)
+
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(&api.AccountInfo{UserId: userId, Password: "hash-antigo"}, nil).Once()
+		mockAccountInfoRepo.On("ChangePassword", mock.Anything, userId, "hash-antigo", "hash-novo", 5, updatedAt).Return(true, nil)
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(&api.AccountInfo{UserId: userId, Password: "hash-novo"}, nil).Once()
+		mockAuditRepo.On("GetLastAuditEvent", mock.Anything).Return(nil, nil)
+		mockAuditRepo.On("InsertAuditEvent", mock.Anything, mock.MatchedBy(func(event *model.AuditEvent) bool {
+			return event.Collection == "account_info" && event.DocumentId == userId && event.Operation == model.AuditOperationUpdate &&
+				event.Actor == userId && event.RequestId == "req-1" &&
+				assert.ObjectsAreEqual([]model.AuditChange{{Field: "password", Redacted: true}}, event.Changes)
+		})).Return(nil)
+
+		repo := audit.NewAccountInfoRepository(mockAccountInfoRepo, audit.NewRecorder(mockAuditRepo, newTransactionRunner()))
+		changed, err := repo.ChangePassword(ctx, userId, "hash-antigo", "hash-novo", 5, updatedAt)
+
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Senha atual divergente não gera evento", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockAuditRepo := new(mocks.MockAuditRepository)
+
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(&api.AccountInfo{UserId: userId, Password: "hash-outro"}, nil)
+		mockAccountInfoRepo.On("ChangePassword", mock.Anything, userId, "hash-antigo", "hash-novo", 5, updatedAt).Return(false, nil)
+
+		repo := audit.NewAccountInfoRepository(mockAccountInfoRepo, audit.NewRecorder(mockAuditRepo, newTransactionRunner()))
+		changed, err := repo.ChangePassword(ctx, userId, "hash-antigo", "hash-novo", 5, updatedAt)
+
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		mockAccountInfoRepo.AssertNumberOfCalls(t, "GetAccountInfo", 1)
+		mockAuditRepo.AssertNotCalled(t, "InsertAuditEvent", mock.Anything, mock.Anything)
+	})
+}
+
+func TestPersonalInfoRepository_DeletePersonalInfo(t *testing.T) {
+	userId := "507f1f77bcf86cd799439011"
+	mockPersonalInfoRepo := new(mocks.MockPersonalInfoRepository)
+	mockAuditRepo := new(mocks.MockAuditRepository)
+
+	mockPersonalInfoRepo.On("GetPersonalInfo", mock.Anything, userId).Return(&api.PersonalInfo{UserId: userId, Email: "joao@example.com"}, nil).Once()
+	mockPersonalInfoRepo.On("DeletePersonalInfo", mock.Anything, userId).Return(nil)
+	mockPersonalInfoRepo.On("GetPersonalInfo", mock.Anything, userId).Return(nil, status.Error(codes.NotFound, "PersonalInfo não encontrado")).Once()
+	mockAuditRepo.On("GetLastAuditEvent", mock.Anything).Return(nil, nil)
+	mockAuditRepo.On("InsertAuditEvent", mock.Anything, mock.MatchedBy(func(event *model.AuditEvent) bool {
+		return event.Collection == "personal_info" && event.Operation == model.AuditOperationDelete && event.Actor == audit.SystemActor &&
+			assert.ObjectsAreEqual([]model.AuditChange{{Field: "userId", Before: userId}, {Field: "email", Before: "joao@example.com"}}, event.Changes)
+	})).Return(nil)
+
+	err := audit.NewPersonalInfoRepository(mockPersonalInfoRepo, audit.NewRecorder(mockAuditRepo, newTransactionRunner())).DeletePersonalInfo(context.Background(), userId)
+
+	assert.NoError(t, err)
+	mockAuditRepo.AssertExpectations(t)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	issuer, err := tokens.NewJWTIssuer(key, "partus_users", 15*time.Minute)
+	assert.NoError(t, err)
+
+	userId := "507f1f77bcf86cd799439011"
+	accessToken, _, err := issuer.IssueAccessToken(userId, "sessao-1", nil, time.Now())
+	assert.NoError(t, err)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.AccountInfoService/UpdateAccountInfo"}
+	run := func(md metadata.MD) audit.Metadata {
+		var captured audit.Metadata
+		_, err := audit.UnaryServerInterceptor(issuer)(metadata.NewIncomingContext(context.Background(), md), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			captured = audit.MetadataFromContext(ctx)
+			return nil, nil
+		})
+		assert.NoError(t, err)
+		return captured
+	}
+
+	t.Run("Autor lido do access token", func(t *testing.T) {
+		captured := run(metadata.Pairs("authorization", "Bearer "+accessToken, "x-request-id", "req-1"))
+
+		assert.Equal(t, audit.Metadata{Actor: userId, Method: info.FullMethod, RequestId: "req-1"}, captured)
+	})
+
+	t.Run("Papéis lidos do access token", func(t *testing.T) {
+		adminToken, _, err := issuer.IssueAccessToken(userId, "sessao-2", []string{model.RoleAdmin}, time.Now())
+		assert.NoError(t, err)
+
+		captured := run(metadata.Pairs("authorization", "Bearer "+adminToken))
+
+		assert.True(t, captured.HasRole(model.RoleAdmin))
+		assert.False(t, captured.HasRole(model.RoleSupport))
+	})
+
+	t.Run("Sem token válido a chamada é anônima", func(t *testing.T) {
+		captured := run(metadata.Pairs("authorization", "Bearer invalido"))
+
+		assert.Equal(t, audit.AnonymousActor, captured.Actor)
+		assert.Len(t, captured.RequestId, 32)
+	})
+}
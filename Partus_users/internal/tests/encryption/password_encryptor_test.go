@@ -0,0 +1,108 @@
+package encryption
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/encryption"
+	"github.com/stretchr/testify/assert"
+)
+
+// fastHashingPolicy usa parâmetros baixos só para manter os testes rápidos.
+func fastHashingPolicy(algorithm string) *config.PasswordHashingPolicy {
+	return &config.PasswordHashingPolicy{
+		Algorithm:         algorithm,
+		BcryptCost:        4,
+		Argon2Memory:      64,
+		Argon2Iterations:  1,
+		Argon2Parallelism: 1,
+		ScryptLogN:        10,
+		ScryptR:           8,
+		ScryptP:           1,
+	}
+}
+
+func TestPasswordEncryptor_HashAndVerify(t *testing.T) {
+	testCases := []struct {
+		algorithm string
+		prefix    string
+	}{
+		{algorithm: "argon2id", prefix: "$argon2id$v=19$m=64,t=1,p=1$"},
+		{algorithm: "scrypt", prefix: "$scrypt$ln=10,r=8,p=1$"},
+		{algorithm: "bcrypt", prefix: "$2a$04$"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.algorithm, func(t *testing.T) {
+			encryptor, err := encryption.NewPasswordEncryptor(fastHashingPolicy(tc.algorithm))
+			assert.NoError(t, err)
+
+			hashed, err := encryptor.EncryptPassword("ValidPassword123!")
+			assert.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hashed, tc.prefix), hashed)
+
+			valid, err := encryptor.VerifyPassword(hashed, "ValidPassword123!")
+			assert.NoError(t, err)
+			assert.True(t, valid)
+
+			valid, err = encryptor.VerifyPassword(hashed, "OutraSenha123!")
+			assert.NoError(t, err)
+			assert.False(t, valid)
+
+			assert.False(t, encryptor.NeedsRehash(hashed))
+		})
+	}
+}
+
+func TestPasswordEncryptor_NeedsRehash(t *testing.T) {
+	bcryptEncryptor, err := encryption.NewPasswordEncryptor(fastHashingPolicy("bcrypt"))
+	assert.NoError(t, err)
+	argon2Encryptor, err := encryption.NewPasswordEncryptor(fastHashingPolicy("argon2id"))
+	assert.NoError(t, err)
+
+	legacyHash, err := bcryptEncryptor.EncryptPassword("ValidPassword123!")
+	assert.NoError(t, err)
+
+	t.Run("hash de outro algoritmo continua válido", func(t *testing.T) {
+		valid, err := argon2Encryptor.VerifyPassword(legacyHash, "ValidPassword123!")
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		assert.True(t, argon2Encryptor.NeedsRehash(legacyHash))
+	})
+
+	t.Run("parâmetros diferentes dos configurados", func(t *testing.T) {
+		stronger := fastHashingPolicy("argon2id")
+		stronger.Argon2Iterations = 2
+		strongerEncryptor, err := encryption.NewPasswordEncryptor(stronger)
+		assert.NoError(t, err)
+
+		hashed, err := argon2Encryptor.EncryptPassword("ValidPassword123!")
+		assert.NoError(t, err)
+
+		valid, err := strongerEncryptor.VerifyPassword(hashed, "ValidPassword123!")
+		assert.NoError(t, err)
+		assert.True(t, valid)
+		assert.True(t, strongerEncryptor.NeedsRehash(hashed))
+	})
+
+	t.Run("formato desconhecido", func(t *testing.T) {
+		_, err := argon2Encryptor.VerifyPassword("$md5$abc", "ValidPassword123!")
+		assert.ErrorIs(t, err, encryption.ErrInvalidPasswordHash)
+		assert.True(t, argon2Encryptor.NeedsRehash("$md5$abc"))
+	})
+}
+
+// TestArgon2idHasher_PHCFormat confere o formato usado pelas outras implementações do argon2id,
+// com os parâmetros na ordem m, t, p.
+func TestArgon2idHasher_PHCFormat(t *testing.T) {
+	hasher := &encryption.Argon2idHasher{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 8, KeyLength: 16}
+	hashed, err := hasher.Hash("password")
+	assert.NoError(t, err)
+
+	parts := strings.Split(hashed, "$")
+	assert.Len(t, parts, 6)
+	assert.Equal(t, "argon2id", parts[1])
+	assert.Equal(t, "v=19", parts[2])
+	assert.Equal(t, "m=64,t=1,p=1", parts[3])
+}
@@ -0,0 +1,176 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/events"
+	"github.com/jonh-dev/partus_users/internal/model"
+	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const userId = "507f1f77bcf86cd799439011"
+
+func TestDomainEvents(t *testing.T) {
+	tests := []struct {
+		name       string
+		collection string
+		operation  string
+		changes    []model.AuditChange
+		expected   []events.DomainEvent
+	}{
+		{
+			name:       "Criação de usuário",
+			collection: "users",
+			operation:  model.AuditOperationCreate,
+			changes: []model.AuditChange{
+				{Field: "personalInfo.email", After: "joao@example.com"},
+				{Field: "accountInfo.username", After: "joao"},
+				{Field: "accountInfo.password", Redacted: true},
+				{Field: "accountInfo.accountStatus", After: "PENDING"},
+			},
+			expected: []events.DomainEvent{{Type: events.TypeUserCreated, Data: events.UserCreatedData{UserId: userId, Email: "joao@example.com", Username: "joao", AccountStatus: "PENDING"}}},
+		},
+		{
+			name:       "Remoção de usuário",
+			collection: "users",
+			operation:  model.AuditOperationUpdate,
+			changes:    []model.AuditChange{{Field: "deletedAt", After: "2024-05-01T12:00:00Z"}},
+			expected:   []events.DomainEvent{{Type: events.TypeUserDeleted, Data: events.UserDeletedData{UserId: userId, DeletedAt: "2024-05-01T12:00:00Z"}}},
+		},
+		{
+			name:       "Restauração de usuário",
+			collection: "users",
+			operation:  model.AuditOperationUpdate,
+			changes:    []model.AuditChange{{Field: "deletedAt", Before: "2024-05-01T12:00:00Z"}},
+			expected:   []events.DomainEvent{{Type: events.TypeUserUpdated, Data: events.UserUpdatedData{UserId: userId, Fields: []string{"deletedAt"}}}},
+		},
+		{
+			name:       "Alteração de dados pessoais",
+			collection: "personal_info",
+			operation:  model.AuditOperationUpdate,
+			changes: []model.AuditChange{
+				{Field: "address.city", Before: "Recife", After: "Olinda"},
+				{Field: "address.street", Before: "Rua A", After: "Rua B"},
+				{Field: "phone", Before: "81999990000", After: "81999991111"},
+			},
+			expected: []events.DomainEvent{{Type: events.TypeUserUpdated, Data: events.UserUpdatedData{UserId: userId, Fields: []string{"address", "phone"}}}},
+		},
+		{
+			name:       "Mudança de status",
+			collection: "account_info",
+			operation:  model.AuditOperationUpdate,
+			changes: []model.AuditChange{
+				{Field: "accountStatus", Before: "ACTIVE", After: "SUSPENDED"},
+				{Field: "statusReason", After: "fraude"},
+				{Field: "statusHistory", After: "[]"},
+				{Field: "updatedAt", Before: "2024-05-01T12:00:00Z", After: "2024-05-02T12:00:00Z"},
+			},
+			expected: []events.DomainEvent{{Type: events.TypeStatusChanged, Data: events.StatusChangedData{UserId: userId, From: "ACTIVE", To: "SUSPENDED", Reason: "fraude"}}},
+		},
+		{
+			name:       "Bloqueio por tentativas de login",
+			collection: "account_info",
+			operation:  model.AuditOperationUpdate,
+			changes: []model.AuditChange{
+				{Field: "failedLoginAttempts", Before: "4", After: "5"},
+				{Field: "accountLockedUntil", After: "2024-05-01T12:05:00Z"},
+				{Field: "accountLockedReason", After: "tentativas de login excedidas"},
+			},
+			expected: []events.DomainEvent{{Type: events.TypeAccountLocked, Data: events.AccountLockedData{UserId: userId, LockedUntil: "2024-05-01T12:05:00Z", Reason: "tentativas de login excedidas"}}},
+		},
+		{
+			name:       "Login bem-sucedido não gera evento",
+			collection: "account_info",
+			operation:  model.AuditOperationUpdate,
+			changes: []model.AuditChange{
+				{Field: "lastLogin", After: "2024-05-01T12:00:00Z"},
+				{Field: "failedLoginAttempts", Before: "2"},
+				{Field: "accountLockedUntil", Before: "2024-05-01T11:00:00Z"},
+			},
+			expected: nil,
+		},
+		{
+			name:       "Alteração de nome de usuário",
+			collection: "account_info",
+			operation:  model.AuditOperationUpdate,
+			changes:    []model.AuditChange{{Field: "username", Before: "joao", After: "joao.silva"}, {Field: "updatedAt", After: "2024-05-01T12:00:00Z"}},
+			expected:   []events.DomainEvent{{Type: events.TypeUserUpdated, Data: events.UserUpdatedData{UserId: userId, Fields: []string{"username"}}}},
+		},
+		{
+			name:       "Remoção definitiva não gera evento",
+			collection: "users",
+			operation:  model.AuditOperationDelete,
+			changes:    []model.AuditChange{{Field: "deletedAt", Before: "2024-05-01T12:00:00Z"}},
+			expected:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auditEvent := &model.AuditEvent{Collection: tt.collection, DocumentId: userId, Operation: tt.operation, Changes: tt.changes}
+
+			assert.Equal(t, tt.expected, events.DomainEvents(auditEvent))
+		})
+	}
+}
+
+func TestOutboxWriter_OnAuditEvent(t *testing.T) {
+	occurredAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	auditEvent := &model.AuditEvent{
+		Collection: "account_info",
+		DocumentId: userId,
+		Operation:  model.AuditOperationUpdate,
+		Changes: []model.AuditChange{
+			{Field: "accountStatus", Before: "ACTIVE", After: "SUSPENDED"},
+			{Field: "username", Before: "joao", After: "joao.silva"},
+		},
+		OccurredAt: occurredAt,
+	}
+	ctx := context.Background()
+
+	mockRepo := new(mocks.MockOutboxRepository)
+	mockRepo.On("InsertOutboxEvent", ctx, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+		return event.Type == events.TypeStatusChanged && event.Subject == userId &&
+			event.Data == `{"userId":"507f1f77bcf86cd799439011","from":"ACTIVE","to":"SUSPENDED"}` &&
+			event.CreatedAt.Equal(occurredAt) && event.NextAttemptAt.Equal(occurredAt)
+	})).Return(nil).Once()
+	mockRepo.On("InsertOutboxEvent", ctx, mock.MatchedBy(func(event *model.OutboxEvent) bool {
+		return event.Type == events.TypeUserUpdated && event.Data == `{"userId":"507f1f77bcf86cd799439011","fields":["username"]}`
+	})).Return(nil).Once()
+
+	err := events.NewOutboxWriter(mockRepo).OnAuditEvent(ctx, auditEvent)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestNewCloudEvent(t *testing.T) {
+	id, _ := primitive.ObjectIDFromHex("665f1f77bcf86cd799439013")
+	event := &model.OutboxEvent{
+		Id:        id,
+		Type:      events.TypeUserDeleted,
+		Subject:   userId,
+		Data:      `{"userId":"507f1f77bcf86cd799439011","deletedAt":"2024-05-01T12:00:00Z"}`,
+		CreatedAt: time.Date(2024, 5, 1, 9, 0, 0, 0, time.FixedZone("BRT", -3*60*60)),
+	}
+
+	data, err := json.Marshal(events.NewCloudEvent(event, "/partus_users"))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"specversion": "1.0",
+		"id": "665f1f77bcf86cd799439013",
+		"source": "/partus_users",
+		"type": "partus.users.UserDeleted",
+		"subject": "507f1f77bcf86cd799439011",
+		"time": "2024-05-01T12:00:00Z",
+		"datacontenttype": "application/json",
+		"data": {"userId": "507f1f77bcf86cd799439011", "deletedAt": "2024-05-01T12:00:00Z"}
+	}`, string(data))
+}
@@ -0,0 +1,100 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/events"
+	"github.com/jonh-dev/partus_users/internal/model"
+	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newOutboxEvent(eventType string, attempts int32) *model.OutboxEvent {
+	return &model.OutboxEvent{
+		Id:        primitive.NewObjectID(),
+		Type:      eventType,
+		Subject:   userId,
+		Data:      `{"userId":"507f1f77bcf86cd799439011"}`,
+		CreatedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Attempts:  attempts,
+	}
+}
+
+func TestRelay_RelayOnce(t *testing.T) {
+	policy := config.DefaultOutboxPolicy()
+
+	t.Run("Publica os eventos pendentes", func(t *testing.T) {
+		first := newOutboxEvent(events.TypeUserCreated, 0)
+		second := newOutboxEvent(events.TypeUserUpdated, 2)
+		mockRepo := new(mocks.MockOutboxRepository)
+		mockRepo.On("ClaimNextOutboxEvent", mock.Anything, mock.Anything, mock.MatchedBy(func(leaseUntil time.Time) bool {
+			return time.Until(leaseUntil) > policy.LeaseTimeout-time.Minute
+		})).Return(first, nil).Once()
+		mockRepo.On("ClaimNextOutboxEvent", mock.Anything, mock.Anything, mock.Anything).Return(second, nil).Once()
+		mockRepo.On("ClaimNextOutboxEvent", mock.Anything, mock.Anything, mock.Anything).Return(nil, nil).Once()
+		mockRepo.On("MarkOutboxEventPublished", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(expiresAt time.Time) bool {
+			return time.Until(expiresAt) > policy.Retention-time.Minute
+		})).Return(nil)
+		publisher := events.NewMemoryPublisher()
+
+		published := events.NewRelay(mockRepo, publisher, policy).RelayOnce(context.Background())
+
+		assert.Equal(t, 2, published)
+		if assert.Len(t, publisher.Events(), 2) {
+			assert.Equal(t, first.Id.Hex(), publisher.Events()[0].Id)
+			assert.Equal(t, events.TypeUserCreated, publisher.Events()[0].Type)
+			assert.Equal(t, policy.Source, publisher.Events()[0].Source)
+			assert.Equal(t, second.Id.Hex(), publisher.Events()[1].Id)
+		}
+		mockRepo.AssertCalled(t, "MarkOutboxEventPublished", mock.Anything, first.Id, mock.Anything, mock.Anything)
+		mockRepo.AssertCalled(t, "MarkOutboxEventPublished", mock.Anything, second.Id, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Falha na publicação adia a nova tentativa", func(t *testing.T) {
+		event := newOutboxEvent(events.TypeUserCreated, 2)
+		mockRepo := new(mocks.MockOutboxRepository)
+		mockRepo.On("ClaimNextOutboxEvent", mock.Anything, mock.Anything, mock.Anything).Return(event, nil)
+		mockRepo.On("MarkOutboxEventFailed", mock.Anything, event.Id, int32(3), mock.MatchedBy(func(nextAttemptAt time.Time) bool {
+			delay := time.Until(nextAttemptAt)
+			return delay > 3*time.Second && delay <= 4*time.Second
+		}), "broker indisponível").Return(nil)
+		publisher := events.NewMemoryPublisher()
+		publisher.FailWith(errors.New("broker indisponível"))
+
+		published := events.NewRelay(mockRepo, publisher, policy).RelayOnce(context.Background())
+
+		assert.Equal(t, 0, published)
+		mockRepo.AssertNumberOfCalls(t, "ClaimNextOutboxEvent", 1)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "MarkOutboxEventPublished", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Lote limitado por BatchSize", func(t *testing.T) {
+		batchPolicy := config.DefaultOutboxPolicy()
+		batchPolicy.BatchSize = 2
+		mockRepo := new(mocks.MockOutboxRepository)
+		mockRepo.On("ClaimNextOutboxEvent", mock.Anything, mock.Anything, mock.Anything).Return(newOutboxEvent(events.TypeUserUpdated, 0), nil)
+		mockRepo.On("MarkOutboxEventPublished", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		published := events.NewRelay(mockRepo, events.NewMemoryPublisher(), batchPolicy).RelayOnce(context.Background())
+
+		assert.Equal(t, 2, published)
+		mockRepo.AssertNumberOfCalls(t, "ClaimNextOutboxEvent", 2)
+	})
+}
+
+func TestOutboxPolicy_RetryDelay(t *testing.T) {
+	policy := &config.OutboxPolicy{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second}
+
+	assert.Equal(t, time.Second, policy.RetryDelay(1))
+	assert.Equal(t, 2*time.Second, policy.RetryDelay(2))
+	assert.Equal(t, 8*time.Second, policy.RetryDelay(4))
+	assert.Equal(t, 10*time.Second, policy.RetryDelay(5))
+	assert.Equal(t, 10*time.Second, policy.RetryDelay(40))
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/handlers"
+	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/services"
+	"github.com/jonh-dev/partus_users/internal/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAccountInfoHandler(t *testing.T) {
+	validAccountInfo := utils.CreateValidAccountInfo()
+
+	t.Run("update", func(t *testing.T) {
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("UpdateUserCredentials", mock.Anything, validAccountInfo).Return(validAccountInfo, nil)
+
+		h := handlers.NewAccountInfoHandler(mockAccountInfoService)
+		response, err := h.UpdateAccountInfo(context.Background(), &api.UpdateAccountInfoRequest{AccountInfo: validAccountInfo})
+
+		assert.NoError(t, err)
+		assert.Equal(t, validAccountInfo.UserId, response.AccountInfo.UserId)
+		assert.Equal(t, validAccountInfo.Username, response.AccountInfo.Username)
+		assert.Empty(t, response.AccountInfo.Password)
+		assert.NotEmpty(t, validAccountInfo.Password)
+		mockAccountInfoService.AssertExpectations(t)
+	})
+
+	t.Run("get without userId", func(t *testing.T) {
+		h := handlers.NewAccountInfoHandler(new(mocks.MockAccountInfoService))
+		_, err := h.GetAccountInfo(context.Background(), &api.GetAccountInfoRequest{})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("delete not found", func(t *testing.T) {
+		req := &api.DeleteAccountInfoRequest{UserId: validAccountInfo.UserId}
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("DeleteAccountInfo", mock.Anything, req).Return(status.Errorf(codes.NotFound, "AccountInfo não encontrado"))
+
+		h := handlers.NewAccountInfoHandler(mockAccountInfoService)
+		_, err := h.DeleteAccountInfo(context.Background(), req)
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		mockAccountInfoService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/handlers"
+	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/services"
+	"github.com/jonh-dev/partus_users/internal/tests/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPersonalInfoHandler(t *testing.T) {
+	validPersonalInfo := utils.CreateValidPersonalInfo()
+
+	t.Run("create", func(t *testing.T) {
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockPersonalInfoService.On("CreatePersonalInfo", mock.Anything, validPersonalInfo).Return(validPersonalInfo, nil)
+
+		h := handlers.NewPersonalInfoHandler(mockPersonalInfoService)
+		response, err := h.CreatePersonalInfo(context.Background(), &api.CreatePersonalInfoRequest{PersonalInfo: validPersonalInfo})
+
+		assert.NoError(t, err)
+		assert.Equal(t, validPersonalInfo, response.PersonalInfo)
+		mockPersonalInfoService.AssertExpectations(t)
+	})
+
+	t.Run("create without personalInfo", func(t *testing.T) {
+		h := handlers.NewPersonalInfoHandler(new(mocks.MockPersonalInfoService))
+		_, err := h.CreatePersonalInfo(context.Background(), &api.CreatePersonalInfoRequest{})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("get not found", func(t *testing.T) {
+		req := &api.GetPersonalInfoRequest{UserId: validPersonalInfo.UserId}
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockPersonalInfoService.On("GetPersonalInfo", mock.Anything, req).Return(nil, status.Errorf(codes.NotFound, "PersonalInfo não encontrado"))
+
+		h := handlers.NewPersonalInfoHandler(mockPersonalInfoService)
+		_, err := h.GetPersonalInfo(context.Background(), req)
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		mockPersonalInfoService.AssertExpectations(t)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		req := &api.DeletePersonalInfoRequest{UserId: validPersonalInfo.UserId}
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockPersonalInfoService.On("DeletePersonalInfo", mock.Anything, req).Return(nil)
+
+		h := handlers.NewPersonalInfoHandler(mockPersonalInfoService)
+		response, err := h.DeletePersonalInfo(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, response.Message)
+		mockPersonalInfoService.AssertExpectations(t)
+	})
+}
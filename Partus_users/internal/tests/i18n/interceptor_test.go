@@ -0,0 +1,148 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jonh-dev/partus_users/internal/i18n"
+	"github.com/jonh-dev/partus_users/internal/validation"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	testCases := []struct {
+		header   string
+		expected string
+	}{
+		{header: "", expected: i18n.Portuguese},
+		{header: "en-US", expected: i18n.English},
+		{header: "en-GB,en;q=0.9", expected: i18n.English},
+		{header: "es-MX", expected: i18n.Spanish},
+		{header: "fr-FR,es;q=0.8,en;q=0.5", expected: i18n.Spanish},
+		{header: "en;q=0.2,es;q=0.7", expected: i18n.Spanish},
+		{header: "en;q=0,pt-PT", expected: i18n.Portuguese},
+		{header: "de-DE,*;q=0.5", expected: i18n.Portuguese},
+		{header: "en;q=abc,es", expected: i18n.Spanish},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.header, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, i18n.ParseAcceptLanguage(testCase.header))
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	validationErr := validation.ToStatus("Dados inválidos",
+		validation.NewFieldError("email", validation.ReasonInvalidEmail, validation.ErrInvalidUserEmail))
+
+	testCases := []struct {
+		name                string
+		acceptLanguage      string
+		err                 error
+		expectedLocale      string
+		expectedMessage     string
+		expectedDescription string
+	}{
+		{
+			name:                "English",
+			acceptLanguage:      "en-US",
+			err:                 validationErr,
+			expectedLocale:      i18n.English,
+			expectedMessage:     "The request contains invalid data: email: The email must be a valid email address",
+			expectedDescription: "The email must be a valid email address",
+		},
+		{
+			name:                "Spanish",
+			acceptLanguage:      "es-AR,en;q=0.5",
+			err:                 validationErr,
+			expectedLocale:      i18n.Spanish,
+			expectedMessage:     "La solicitud contiene datos no válidos: email: El correo electrónico debe ser una dirección válida",
+			expectedDescription: "El correo electrónico debe ser una dirección válida",
+		},
+		{
+			name:                "DefaultLocaleKeepsOriginalMessage",
+			err:                 validationErr,
+			expectedLocale:      i18n.Portuguese,
+			expectedMessage:     status.Convert(validationErr).Message(),
+			expectedDescription: validation.ErrInvalidUserEmail.Error(),
+		},
+		{
+			name:            "ErrorWithoutCatalogCode",
+			acceptLanguage:  "en",
+			err:             status.Error(codes.NotFound, "Usuário não encontrado"),
+			expectedLocale:  i18n.English,
+			expectedMessage: "The requested resource was not found",
+		},
+		{
+			name:            "PlainError",
+			acceptLanguage:  "es",
+			err:             errors.New("falha inesperada"),
+			expectedLocale:  i18n.Spanish,
+			expectedMessage: "Se produjo un error interno",
+		},
+	}
+
+	interceptor := i18n.UnaryServerInterceptor()
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := context.Background()
+			if testCase.acceptLanguage != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("accept-language", testCase.acceptLanguage))
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, testCase.err
+			}
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+
+			st := status.Convert(err)
+			assert.Equal(t, status.Convert(testCase.err).Code(), st.Code())
+			assert.Equal(t, status.Convert(testCase.err).Message(), st.Message())
+
+			var localizedMessages []*errdetails.LocalizedMessage
+			var badRequest *errdetails.BadRequest
+			for _, detail := range st.Details() {
+				switch detail := detail.(type) {
+				case *errdetails.LocalizedMessage:
+					localizedMessages = append(localizedMessages, detail)
+				case *errdetails.BadRequest:
+					badRequest = detail
+				}
+			}
+
+			if assert.Len(t, localizedMessages, 1) {
+				assert.Equal(t, testCase.expectedLocale, localizedMessages[0].Locale)
+				assert.Equal(t, testCase.expectedMessage, localizedMessages[0].Message)
+			}
+			if testCase.expectedDescription != "" && assert.NotNil(t, badRequest) {
+				assert.Equal(t, "email", badRequest.FieldViolations[0].Field)
+				assert.Equal(t, testCase.expectedDescription, badRequest.FieldViolations[0].Description)
+			}
+		})
+	}
+}
+
+func TestLocalizeKeepsExistingLocalizedMessage(t *testing.T) {
+	err := i18n.Localize(status.Error(codes.NotFound, "Usuário não encontrado"), i18n.English)
+
+	localized := i18n.Localize(err, i18n.Spanish)
+
+	assert.Len(t, status.Convert(localized).Details(), 1)
+	assert.Equal(t, err, localized)
+}
+
+func TestMessage(t *testing.T) {
+	message, exists := i18n.Message(i18n.English, "WEAK_PASSWORD")
+	assert.True(t, exists)
+	assert.NotEmpty(t, message)
+
+	_, exists = i18n.Message(i18n.English, "UNKNOWN_CODE")
+	assert.False(t, exists)
+}
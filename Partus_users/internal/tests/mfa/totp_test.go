@@ -0,0 +1,134 @@
+package mfa
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/encryption"
+	"github.com/jonh-dev/partus_users/internal/mfa"
+	"github.com/stretchr/testify/assert"
+)
+
+// Vetores de teste do Apêndice B da RFC 6238, com códigos de 8 dígitos.
+func TestTOTP_RFC6238Vectors(t *testing.T) {
+	seeds := map[mfa.Algorithm][]byte{
+		mfa.AlgorithmSHA1:   []byte("12345678901234567890"),
+		mfa.AlgorithmSHA256: []byte("12345678901234567890123456789012"),
+		mfa.AlgorithmSHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+
+	testCases := []struct {
+		unixTime  int64
+		algorithm mfa.Algorithm
+		code      string
+	}{
+		{59, mfa.AlgorithmSHA1, "94287082"},
+		{59, mfa.AlgorithmSHA256, "46119246"},
+		{59, mfa.AlgorithmSHA512, "90693936"},
+		{1111111109, mfa.AlgorithmSHA1, "07081804"},
+		{1111111109, mfa.AlgorithmSHA256, "68084774"},
+		{1111111109, mfa.AlgorithmSHA512, "25091201"},
+		{1111111111, mfa.AlgorithmSHA1, "14050471"},
+		{1111111111, mfa.AlgorithmSHA256, "67062674"},
+		{1111111111, mfa.AlgorithmSHA512, "99943326"},
+		{1234567890, mfa.AlgorithmSHA1, "89005924"},
+		{1234567890, mfa.AlgorithmSHA256, "91819424"},
+		{1234567890, mfa.AlgorithmSHA512, "93441116"},
+		{2000000000, mfa.AlgorithmSHA1, "69279037"},
+		{2000000000, mfa.AlgorithmSHA256, "90698825"},
+		{2000000000, mfa.AlgorithmSHA512, "38618901"},
+		{20000000000, mfa.AlgorithmSHA1, "65353130"},
+		{20000000000, mfa.AlgorithmSHA256, "77737706"},
+		{20000000000, mfa.AlgorithmSHA512, "47863826"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.algorithm)+"/"+time.Unix(tc.unixTime, 0).UTC().Format(time.RFC3339), func(t *testing.T) {
+			totp := &mfa.TOTP{Digits: 8, Period: 30 * time.Second, Algorithm: tc.algorithm}
+			now := time.Unix(tc.unixTime, 0)
+
+			assert.Equal(t, tc.code, totp.GenerateCode(seeds[tc.algorithm], now))
+
+			step, ok := totp.Validate(seeds[tc.algorithm], tc.code, now)
+			assert.True(t, ok)
+			assert.Equal(t, totp.Step(now), step)
+		})
+	}
+}
+
+func TestTOTP_Validate(t *testing.T) {
+	secret, err := mfa.GenerateSecret()
+	assert.NoError(t, err)
+	totp := mfa.DefaultTOTP()
+	now := time.Now()
+
+	t.Run("accepts the previous period", func(t *testing.T) {
+		code := totp.GenerateCode(secret, now.Add(-30*time.Second))
+		step, ok := totp.Validate(secret, code, now)
+		assert.True(t, ok)
+		assert.Equal(t, totp.Step(now)-1, step)
+	})
+
+	t.Run("rejects codes outside the skew", func(t *testing.T) {
+		code := totp.GenerateCode(secret, now.Add(-2*time.Minute))
+		_, ok := totp.Validate(secret, code, now)
+		assert.False(t, ok)
+	})
+
+	t.Run("rejects codes with the wrong length", func(t *testing.T) {
+		_, ok := totp.Validate(secret, "12345", now)
+		assert.False(t, ok)
+	})
+}
+
+func TestTOTP_KeyURI(t *testing.T) {
+	secret := []byte("12345678901234567890")
+	uri, err := url.Parse(mfa.DefaultTOTP().KeyURI(secret, "Partus", "john.doe"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Partus:john.doe", uri.Path)
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", uri.Query().Get("secret"))
+	assert.Equal(t, "Partus", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := mfa.GenerateRecoveryCodes(mfa.RecoveryCodeCount)
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-z2-9]{5}-[a-z2-9]{5}$`, code)
+		assert.False(t, seen[code])
+		seen[code] = true
+	}
+
+	assert.Equal(t, mfa.HashRecoveryCode("abcde-fghjk"), mfa.HashRecoveryCode(" ABCDE FGHJK"))
+	assert.NotEqual(t, mfa.HashRecoveryCode("abcde-fghjk"), mfa.HashRecoveryCode("abcde-fghjm"))
+}
+
+func TestAESGCMCipher(t *testing.T) {
+	key := make([]byte, 32)
+	secretCipher, err := encryption.NewAESGCMCipher(key)
+	assert.NoError(t, err)
+
+	encrypted, err := secretCipher.Encrypt([]byte("segredo"))
+	assert.NoError(t, err)
+	assert.NotContains(t, encrypted, "segredo")
+
+	decrypted, err := secretCipher.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "segredo", string(decrypted))
+
+	otherCipher, err := encryption.NewAESGCMCipher(append(make([]byte, 31), 1))
+	assert.NoError(t, err)
+	_, err = otherCipher.Decrypt(encrypted)
+	assert.Error(t, err)
+
+	_, err = encryption.NewAESGCMCipher([]byte("curta"))
+	assert.Error(t, err)
+}
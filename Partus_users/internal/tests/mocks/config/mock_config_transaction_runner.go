@@ -0,0 +1,19 @@
+package config
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTransactionRunner struct {
+	mock.Mock
+}
+
+func (m *MockTransactionRunner) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	args := m.Called(ctx)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
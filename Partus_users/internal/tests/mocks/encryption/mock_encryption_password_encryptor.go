@@ -12,3 +12,13 @@ func (m *MockPasswordEncryptor) EncryptPassword(password string) (string, error)
 	args := m.Called(password)
 	return args.String(0), args.Error(1)
 }
+
+func (m *MockPasswordEncryptor) VerifyPassword(hashedPassword string, password string) (bool, error) {
+	args := m.Called(hashedPassword, password)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPasswordEncryptor) NeedsRehash(hashedPassword string) bool {
+	args := m.Called(hashedPassword)
+	return args.Bool(0)
+}
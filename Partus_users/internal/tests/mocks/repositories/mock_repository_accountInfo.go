@@ -2,8 +2,10 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/model"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -35,4 +37,63 @@ func (m *MockAccountInfoRepository) UpdateUserCredentials(ctx context.Context, a
 	return args.Get(0).(*api.AccountInfo), args.Error(1)
 }
 
+func (m *MockAccountInfoRepository) GetAccountInfoByUsername(ctx context.Context, username string) (*api.AccountInfo, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.AccountInfo), args.Error(1)
+}
+
+func (m *MockAccountInfoRepository) RegisterSuccessfulLogin(ctx context.Context, id string, loginAt time.Time) error {
+	args := m.Called(ctx, id, loginAt)
+	return args.Error(0)
+}
+
+func (m *MockAccountInfoRepository) UpdateFailedLoginState(ctx context.Context, previous *api.AccountInfo, updated *api.AccountInfo) (bool, error) {
+	args := m.Called(ctx, previous, updated)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccountInfoRepository) DeleteAccountInfo(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // Implemente os outros métodos conforme necessário...
+
+func (m *MockAccountInfoRepository) ResetPassword(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string, historySize int, updatedAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, currentHashedPassword, newHashedPassword, historySize, updatedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccountInfoRepository) GetPasswordHistory(ctx context.Context, id string) ([]string, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAccountInfoRepository) ChangePassword(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string, historySize int, updatedAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, currentHashedPassword, newHashedPassword, historySize, updatedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccountInfoRepository) ChangeAccountStatus(ctx context.Context, id string, transition *model.StatusTransition) (bool, error) {
+	args := m.Called(ctx, id, transition)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAccountInfoRepository) FindExpiredSuspensions(ctx context.Context, now time.Time) ([]string, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockAccountInfoRepository) UpdatePasswordHash(ctx context.Context, id string, currentHashedPassword string, newHashedPassword string) (bool, error) {
+	args := m.Called(ctx, id, currentHashedPassword, newHashedPassword)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockMFARepository struct {
+	mock.Mock
+}
+
+func (m *MockMFARepository) GetMFA(ctx context.Context, userId string) (*model.MFA, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MFA), args.Error(1)
+}
+
+func (m *MockMFARepository) SavePendingMFA(ctx context.Context, mfa *model.MFA) error {
+	args := m.Called(ctx, mfa)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) EnableMFA(ctx context.Context, userId string, recoveryCodeHashes []string, step int64, confirmedAt time.Time) (bool, error) {
+	args := m.Called(ctx, userId, recoveryCodeHashes, step, confirmedAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) UseTOTPStep(ctx context.Context, userId string, step int64) (bool, error) {
+	args := m.Called(ctx, userId, step)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) UseRecoveryCode(ctx context.Context, userId string, codeHash string) (bool, error) {
+	args := m.Called(ctx, userId, codeHash)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFARepository) DeleteMFA(ctx context.Context, userId string) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) CreateChallenge(ctx context.Context, challenge *model.MFAChallenge) error {
+	args := m.Called(ctx, challenge)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) GetChallengeByTokenHash(ctx context.Context, tokenHash string) (*model.MFAChallenge, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.MFAChallenge), args.Error(1)
+}
+
+func (m *MockMFARepository) IncrementChallengeAttempts(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockMFARepository) DeleteChallenge(ctx context.Context, id primitive.ObjectID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
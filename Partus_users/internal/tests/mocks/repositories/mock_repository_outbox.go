@@ -0,0 +1,37 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) InsertOutboxEvent(ctx context.Context, event *model.OutboxEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ClaimNextOutboxEvent(ctx context.Context, now time.Time, leaseUntil time.Time) (*model.OutboxEvent, error) {
+	args := m.Called(ctx, now, leaseUntil)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.OutboxEvent), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkOutboxEventPublished(ctx context.Context, id primitive.ObjectID, publishedAt time.Time, expiresAt time.Time) error {
+	args := m.Called(ctx, id, publishedAt, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkOutboxEventFailed(ctx context.Context, id primitive.ObjectID, attempts int32, nextAttemptAt time.Time, lastError string) error {
+	args := m.Called(ctx, id, attempts, nextAttemptAt, lastError)
+	return args.Error(0)
+}
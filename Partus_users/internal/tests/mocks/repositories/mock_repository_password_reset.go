@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) CreatePasswordReset(ctx context.Context, reset *model.PasswordReset) error {
+	args := m.Called(ctx, reset)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) ConsumePasswordReset(ctx context.Context, tokenHash string, now time.Time) (*model.PasswordReset, error) {
+	args := m.Called(ctx, tokenHash, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.PasswordReset), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) InvalidateUserPasswordResets(ctx context.Context, userId string, now time.Time) error {
+	args := m.Called(ctx, userId, now)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) DeleteUserPasswordResets(ctx context.Context, userId string) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
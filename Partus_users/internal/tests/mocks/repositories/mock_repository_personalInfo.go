@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/jonh-dev/partus_users/api"
 	"github.com/stretchr/testify/mock"
@@ -27,8 +28,16 @@ func (m *MockPersonalInfoRepository) GetPersonalInfo(ctx context.Context, id str
 	return args.Get(0).(*api.PersonalInfo), args.Error(1)
 }
 
-func (m *MockPersonalInfoRepository) UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error) {
-	args := m.Called(ctx, personalInfo)
+func (m *MockPersonalInfoRepository) GetPersonalInfoByEmail(ctx context.Context, email string) (*api.PersonalInfo, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.PersonalInfo), args.Error(1)
+}
+
+func (m *MockPersonalInfoRepository) UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo, fields []string) (*api.PersonalInfo, error) {
+	args := m.Called(ctx, personalInfo, fields)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -42,3 +51,13 @@ func (m *MockPersonalInfoRepository) DoesEmailExist(ctx context.Context, email s
 	}
 	return args.Get(0).(bool), args.Error(1)
 }
+
+func (m *MockPersonalInfoRepository) DeletePersonalInfo(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockPersonalInfoRepository) MarkEmailVerified(ctx context.Context, id string, email string, verifiedAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, email, verifiedAt)
+	return args.Bool(0), args.Error(1)
+}
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) CreateSession(ctx context.Context, session *model.Session) (*model.Session, error) {
+	args := m.Called(ctx, session)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) GetSession(ctx context.Context, id string) (*model.Session, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) GetSessionByTokenHash(ctx context.Context, tokenHash string) (*model.Session, error) {
+	args := m.Called(ctx, tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) ListActiveSessions(ctx context.Context, userId string, now time.Time) ([]*model.Session, error) {
+	args := m.Called(ctx, userId, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) RotateSessionToken(ctx context.Context, id string, currentHash string, newHash string, client model.SessionClient, seenAt time.Time) (bool, error) {
+	args := m.Called(ctx, id, currentHash, newHash, client, seenAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeSession(ctx context.Context, id string, reason string, revokedAt time.Time) error {
+	args := m.Called(ctx, id, reason, revokedAt)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeUserSessions(ctx context.Context, userId string, exceptSessionId string, reason string, revokedAt time.Time) (int64, error) {
+	args := m.Called(ctx, userId, exceptSessionId, reason, revokedAt)
+	return args.Get(0).(int64), args.Error(1)
+}
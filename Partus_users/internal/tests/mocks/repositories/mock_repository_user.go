@@ -2,8 +2,10 @@ package mocks
 
 import (
 	"context"
+	"time"
 
 	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -27,6 +29,14 @@ func (m *MockUserRepository) GetUser(ctx context.Context, id string) (*model.Use
 	return args.Get(0).(*model.User), args.Error(1)
 }
 
+func (m *MockUserRepository) ListUsers(ctx context.Context, query repositories.ListUsersQuery) ([]*model.User, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*model.User), args.Error(1)
+}
+
 func (m *MockUserRepository) UpdateUser(ctx context.Context, user *model.User) (*model.User, error) {
 	args := m.Called(ctx, user)
 	if args.Get(0) == nil {
@@ -34,3 +44,26 @@ func (m *MockUserRepository) UpdateUser(ctx context.Context, user *model.User) (
 	}
 	return args.Get(0).(*model.User), args.Error(1)
 }
+
+func (m *MockUserRepository) SoftDeleteUser(ctx context.Context, id string, deletedAt time.Time) error {
+	args := m.Called(ctx, id, deletedAt)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) RestoreUser(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindDeletedUserIds(ctx context.Context, deletedBefore time.Time) ([]string, error) {
+	args := m.Called(ctx, deletedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockUserRepository) PurgeUser(ctx context.Context, id string, deletedBefore time.Time) error {
+	args := m.Called(ctx, id, deletedBefore)
+	return args.Error(0)
+}
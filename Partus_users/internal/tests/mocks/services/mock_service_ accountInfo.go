@@ -27,6 +27,14 @@ func (m *MockAccountInfoService) GetAccountInfo(ctx context.Context, req *api.Ge
 	return args.Get(0).(*api.AccountInfo), args.Error(1)
 }
 
+func (m *MockAccountInfoService) GetAccountInfoByUsername(ctx context.Context, username string) (*api.AccountInfo, error) {
+	args := m.Called(ctx, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.AccountInfo), args.Error(1)
+}
+
 func (m *MockAccountInfoService) UpdateUserCredentials(ctx context.Context, accountInfo *api.AccountInfo) (*api.AccountInfo, error) {
 	args := m.Called(ctx, accountInfo)
 	if args.Get(0) == nil {
@@ -34,3 +42,52 @@ func (m *MockAccountInfoService) UpdateUserCredentials(ctx context.Context, acco
 	}
 	return args.Get(0).(*api.AccountInfo), args.Error(1)
 }
+
+func (m *MockAccountInfoService) Authenticate(ctx context.Context, username string, password string) (*api.AccountInfo, error) {
+	args := m.Called(ctx, username, password)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.AccountInfo), args.Error(1)
+}
+
+func (m *MockAccountInfoService) RegisterSuccessfulLogin(ctx context.Context, userId string) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
+
+func (m *MockAccountInfoService) RegisterFailedMFA(ctx context.Context, userId string) error {
+	args := m.Called(ctx, userId)
+	return args.Error(0)
+}
+
+func (m *MockAccountInfoService) VerifyPassword(ctx context.Context, userId string, password string) error {
+	args := m.Called(ctx, userId, password)
+	return args.Error(0)
+}
+
+func (m *MockAccountInfoService) ChangePassword(ctx context.Context, userId string, currentPassword string, newPassword string) error {
+	args := m.Called(ctx, userId, currentPassword, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockAccountInfoService) ChangeAccountStatus(ctx context.Context, req *api.ChangeAccountStatusRequest) (*api.AccountInfo, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.AccountInfo), args.Error(1)
+}
+
+func (m *MockAccountInfoService) RegisterFailedLogin(ctx context.Context, username string, reason string) (*api.AccountInfo, error) {
+	args := m.Called(ctx, username, reason)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.AccountInfo), args.Error(1)
+}
+
+func (m *MockAccountInfoService) DeleteAccountInfo(ctx context.Context, req *api.DeleteAccountInfoRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
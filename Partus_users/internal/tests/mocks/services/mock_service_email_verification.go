@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockEmailVerificationService struct {
+	mock.Mock
+}
+
+func (m *MockEmailVerificationService) SendVerificationEmail(ctx context.Context, userId string, email string, firstName string, timeZone string) error {
+	args := m.Called(ctx, userId, email, firstName, timeZone)
+	return args.Error(0)
+}
+
+func (m *MockEmailVerificationService) VerifyEmail(ctx context.Context, req *api.VerifyEmailRequest) (*api.VerifyEmailResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.VerifyEmailResponse), args.Error(1)
+}
+
+func (m *MockEmailVerificationService) ResendVerificationEmail(ctx context.Context, req *api.ResendVerificationEmailRequest) (*api.ResendVerificationEmailResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.ResendVerificationEmailResponse), args.Error(1)
+}
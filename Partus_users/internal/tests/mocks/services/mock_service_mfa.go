@@ -0,0 +1,52 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMFAService struct {
+	mock.Mock
+}
+
+func (m *MockMFAService) EnrollTOTP(ctx context.Context, req *api.EnrollTOTPRequest) (*api.EnrollTOTPResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.EnrollTOTPResponse), args.Error(1)
+}
+
+func (m *MockMFAService) ConfirmTOTP(ctx context.Context, req *api.ConfirmTOTPRequest) (*api.ConfirmTOTPResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.ConfirmTOTPResponse), args.Error(1)
+}
+
+func (m *MockMFAService) DisableMFA(ctx context.Context, req *api.DisableMFARequest) (*api.DisableMFAResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.DisableMFAResponse), args.Error(1)
+}
+
+func (m *MockMFAService) IsMFAEnabled(ctx context.Context, userId string) (bool, error) {
+	args := m.Called(ctx, userId)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockMFAService) CreateChallenge(ctx context.Context, userId string) (string, time.Time, error) {
+	args := m.Called(ctx, userId)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockMFAService) VerifyChallenge(ctx context.Context, challengeToken string, code string) (string, error) {
+	args := m.Called(ctx, challengeToken, code)
+	return args.String(0), args.Error(1)
+}
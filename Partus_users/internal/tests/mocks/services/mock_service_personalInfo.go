@@ -27,6 +27,14 @@ func (m *MockPersonalInfoService) GetPersonalInfo(ctx context.Context, req *api.
 	return args.Get(0).(*api.PersonalInfo), args.Error(1)
 }
 
+func (m *MockPersonalInfoService) GetPersonalInfoByEmail(ctx context.Context, email string) (*api.PersonalInfo, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.PersonalInfo), args.Error(1)
+}
+
 func (m *MockPersonalInfoService) UpdatePersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo) (*api.PersonalInfo, error) {
 	args := m.Called(ctx, personalInfo)
 	if args.Get(0) == nil {
@@ -34,3 +42,16 @@ func (m *MockPersonalInfoService) UpdatePersonalInfo(ctx context.Context, person
 	}
 	return args.Get(0).(*api.PersonalInfo), args.Error(1)
 }
+
+func (m *MockPersonalInfoService) DeletePersonalInfo(ctx context.Context, req *api.DeletePersonalInfoRequest) error {
+	args := m.Called(ctx, req)
+	return args.Error(0)
+}
+
+func (m *MockPersonalInfoService) PatchPersonalInfo(ctx context.Context, personalInfo *api.PersonalInfo, fields []string) (*api.PersonalInfo, error) {
+	args := m.Called(ctx, personalInfo, fields)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.PersonalInfo), args.Error(1)
+}
@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSessionService struct {
+	mock.Mock
+}
+
+func (m *MockSessionService) CreateSession(ctx context.Context, userId string) (*api.TokenResponse, error) {
+	args := m.Called(ctx, userId)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.TokenResponse), args.Error(1)
+}
+
+func (m *MockSessionService) RefreshToken(ctx context.Context, req *api.RefreshTokenRequest) (*api.TokenResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.TokenResponse), args.Error(1)
+}
+
+func (m *MockSessionService) GetPublicKeys(ctx context.Context, req *api.GetPublicKeysRequest) (*api.GetPublicKeysResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.GetPublicKeysResponse), args.Error(1)
+}
+
+func (m *MockSessionService) ListSessions(ctx context.Context, req *api.ListSessionsRequest) (*api.ListSessionsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.ListSessionsResponse), args.Error(1)
+}
+
+func (m *MockSessionService) RevokeSession(ctx context.Context, req *api.RevokeSessionRequest) (*api.RevokeSessionResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.RevokeSessionResponse), args.Error(1)
+}
+
+func (m *MockSessionService) RevokeAllSessions(ctx context.Context, req *api.RevokeAllSessionsRequest) (*api.RevokeAllSessionsResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.RevokeAllSessionsResponse), args.Error(1)
+}
+
+func (m *MockSessionService) RevokeOtherSessions(ctx context.Context, userId string) (int, error) {
+	args := m.Called(ctx, userId)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockSessionService) RevokeAllUserSessions(ctx context.Context, userId string, reason string) (int, error) {
+	args := m.Called(ctx, userId, reason)
+	return args.Int(0), args.Error(1)
+}
@@ -27,6 +27,30 @@ func (m *MockUserService) GetUser(ctx context.Context, req *api.GetUserRequest)
 	return args.Get(0).(*api.UserResponse), args.Error(1)
 }
 
+func (m *MockUserService) GetUserByEmail(ctx context.Context, req *api.GetUserByEmailRequest) (*api.UserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) GetUserByUsername(ctx context.Context, req *api.GetUserByUsernameRequest) (*api.UserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.UserResponse), args.Error(1)
+}
+
+func (m *MockUserService) ListUsers(ctx context.Context, req *api.ListUsersRequest) (*api.ListUsersResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.ListUsersResponse), args.Error(1)
+}
+
 func (m *MockUserService) UpdateUser(ctx context.Context, req *api.UpdateUserRequest) (*api.UserResponse, error) {
 	args := m.Called(ctx, req)
 	if args.Get(0) == nil {
@@ -50,3 +74,27 @@ func (m *MockUserService) HandleFailedLogin(ctx context.Context, req *api.Handle
 	}
 	return args.Get(0).(*api.UserResponse), args.Error(1)
 }
+
+func (m *MockUserService) Login(ctx context.Context, req *api.LoginRequest) (*api.LoginResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.LoginResponse), args.Error(1)
+}
+
+func (m *MockUserService) VerifyLoginMFA(ctx context.Context, req *api.VerifyLoginMFARequest) (*api.LoginResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.LoginResponse), args.Error(1)
+}
+
+func (m *MockUserService) RestoreUser(ctx context.Context, req *api.RestoreUserRequest) (*api.UserResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*api.UserResponse), args.Error(1)
+}
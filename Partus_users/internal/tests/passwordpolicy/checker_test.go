@@ -0,0 +1,127 @@
+package passwordpolicy
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/partus_users/internal/passwordpolicy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateStrength(t *testing.T) {
+	common := passwordpolicy.DefaultCommonPasswords()
+
+	testCases := []struct {
+		password string
+		maxScore int
+		minScore int
+		pattern  passwordpolicy.PatternKind
+	}{
+		{password: "Password1!", maxScore: 1, pattern: passwordpolicy.PatternDictionary},
+		{password: "P@ssw0rd2024", maxScore: 2, pattern: passwordpolicy.PatternDictionary},
+		{password: "zxcvbnmlkjh1", maxScore: 2, pattern: passwordpolicy.PatternKeyboard},
+		{password: "abcdefgh1234", maxScore: 1, pattern: passwordpolicy.PatternSequence},
+		{password: "aaaaaaaaaaa1", maxScore: 1, pattern: passwordpolicy.PatternRepeat},
+		{password: "v9#Lq2!xR7mT", minScore: 4},
+		{password: "cavalo-correto-bateria-grampo", minScore: 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.password, func(t *testing.T) {
+			strength := passwordpolicy.EstimateStrength(tc.password, common)
+
+			assert.GreaterOrEqual(t, strength.Score, tc.minScore)
+			if tc.pattern != "" {
+				assert.LessOrEqual(t, strength.Score, tc.maxScore)
+				assert.Contains(t, strength.Patterns, tc.pattern)
+			}
+		})
+	}
+
+	t.Run("dados do usuário", func(t *testing.T) {
+		strength := passwordpolicy.EstimateStrength("Joaosilva#1990", common, "joaosilva", "joao.silva@example.com")
+
+		assert.Contains(t, strength.Patterns, passwordpolicy.PatternUserInput)
+		assert.LessOrEqual(t, strength.Score, 2)
+	})
+}
+
+func TestChecker(t *testing.T) {
+	dir := t.TempDir()
+	writeBreachedRange(t, dir, "Vazada#2019x", 42)
+
+	corpus, err := passwordpolicy.NewBreachedCorpus(dir)
+	assert.NoError(t, err)
+	checker := passwordpolicy.NewChecker(corpus, passwordpolicy.DefaultCommonPasswords(), 3)
+
+	testCases := []struct {
+		name     string
+		password string
+		reason   string
+	}{
+		{name: "senha comum", password: "Senha123", reason: "mais usadas"},
+		{name: "senha vazada", password: "Vazada#2019x", reason: "vazamentos"},
+		{name: "senha fraca", password: "Password1!", reason: "fácil de adivinhar"},
+		{name: "senha forte", password: "v9#Lq2!xR7mT"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checker.Check(tc.password)
+
+			if tc.reason == "" {
+				assert.NoError(t, err)
+				return
+			}
+			var rejection *passwordpolicy.Rejection
+			assert.True(t, errors.As(err, &rejection))
+			assert.Contains(t, rejection.Reason, tc.reason)
+		})
+	}
+}
+
+func TestBreachedCorpus_Occurrences(t *testing.T) {
+	dir := t.TempDir()
+	writeBreachedRange(t, dir, "Vazada#2019x", 42)
+
+	corpus, err := passwordpolicy.NewBreachedCorpus(dir)
+	assert.NoError(t, err)
+
+	occurrences, err := corpus.Occurrences("Vazada#2019x")
+	assert.NoError(t, err)
+	assert.Equal(t, 42, occurrences)
+
+	occurrences, err = corpus.Occurrences("outra senha qualquer")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, occurrences)
+
+	_, err = passwordpolicy.NewBreachedCorpus(filepath.Join(dir, "inexistente"))
+	assert.Error(t, err)
+}
+
+func TestLoadCommonPasswords_Limit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "common.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("primeira\nSegunda\nterceira\n"), 0600))
+
+	common, err := passwordpolicy.LoadCommonPasswords(path, 2)
+
+	assert.NoError(t, err)
+	assert.True(t, common.Contains("segunda"))
+	assert.False(t, common.Contains("terceira"))
+}
+
+// writeBreachedRange grava a faixa do prefixo da senha como o downloader do Have I Been Pwned,
+// com uma linha de outro sufixo antes da senha.
+func writeBreachedRange(t *testing.T, dir string, password string, occurrences int) {
+	digest := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(digest[:]))
+
+	content := "0000000000000000000000000000000000A:3\r\n" + hash[5:] + ":" + strconv.Itoa(occurrences) + "\r\n"
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(content), 0600))
+}
@@ -0,0 +1,133 @@
+package sanitizer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/sanitizer"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+const passwordHash = "$argon2id$v=19$m=65536,t=3,p=2$c2FsdHNhbHRzYWx0$aGFzaGhhc2hoYXNoaGFzaA"
+
+func TestScrub(t *testing.T) {
+	response := &api.ListUsersResponse{
+		Users: []*api.User{
+			{Id: "1", AccountInfo: &api.AccountInfo{Username: "johndoe", Password: passwordHash}},
+			{Id: "2", AccountInfo: &api.AccountInfo{Username: "marydoe", Password: passwordHash}},
+		},
+	}
+
+	sanitizer.Scrub(response)
+
+	for _, user := range response.Users {
+		assert.Empty(t, user.AccountInfo.Password)
+		assert.NotEmpty(t, user.AccountInfo.Username)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := sanitizer.UnaryServerInterceptor()
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return &api.LoginResponse{User: &api.User{AccountInfo: &api.AccountInfo{Password: passwordHash}}}, nil
+	}
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, handler)
+
+	assert.NoError(t, err)
+	assert.Empty(t, resp.(*api.LoginResponse).User.AccountInfo.Password)
+
+	var nilResponse *api.UserResponse
+	resp, err = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nilResponse, nil
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, resp.(*api.UserResponse))
+}
+
+// TestPasswordFieldsAreSensitive garante que um novo campo de senha não seja criado sem a opção
+// (api.sensitive).
+func TestPasswordFieldsAreSensitive(t *testing.T) {
+	messages := api.File_user_proto.Messages()
+	for i := 0; i < messages.Len(); i++ {
+		fields := messages.Get(i).Fields()
+		for j := 0; j < fields.Len(); j++ {
+			field := fields.Get(j)
+			if strings.Contains(strings.ToLower(string(field.Name())), "password") {
+				assert.True(t, sanitizer.IsSensitive(field), "%s deve ser marcado como sensitive", field.FullName())
+			}
+		}
+	}
+}
+
+// TestNoRPCReturnsSensitiveData preenche a resposta de cada RPC, com o hash em todo campo sensível
+// alcançável, e verifica que nada dele sai pelo interceptor.
+func TestNoRPCReturnsSensitiveData(t *testing.T) {
+	interceptor := sanitizer.UnaryServerInterceptor()
+	services := api.File_user_proto.Services()
+
+	for i := 0; i < services.Len(); i++ {
+		methods := services.Get(i).Methods()
+		for j := 0; j < methods.Len(); j++ {
+			method := methods.Get(j)
+			t.Run(string(method.FullName()), func(t *testing.T) {
+				messageType, err := protoregistry.GlobalTypes.FindMessageByName(method.Output().FullName())
+				assert.NoError(t, err)
+
+				response := messageType.New()
+				fill(response, 0)
+
+				resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: string(method.FullName())},
+					func(ctx context.Context, req interface{}) (interface{}, error) {
+						return response.Interface(), nil
+					})
+				assert.NoError(t, err)
+
+				text, err := prototext.Marshal(resp.(proto.Message))
+				assert.NoError(t, err)
+				assert.NotContains(t, string(text), passwordHash)
+			})
+		}
+	}
+}
+
+// fill atribui um valor a todo campo de texto e de mensagem, até a profundidade máxima.
+func fill(message protoreflect.Message, depth int) {
+	if depth > 4 {
+		return
+	}
+
+	fields := message.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if field.IsMap() {
+			continue
+		}
+
+		value := protoreflect.ValueOfString("valor")
+		if sanitizer.IsSensitive(field) {
+			value = protoreflect.ValueOfString(passwordHash)
+		}
+
+		switch {
+		case field.Kind() == protoreflect.StringKind && field.IsList():
+			message.Mutable(field).List().Append(value)
+		case field.Kind() == protoreflect.StringKind:
+			message.Set(field, value)
+		case field.Message() != nil && field.IsList():
+			list := message.Mutable(field).List()
+			element := list.NewElement()
+			fill(element.Message(), depth+1)
+			list.Append(element)
+		case field.Message() != nil:
+			fill(message.Mutable(field).Message(), depth+1)
+		}
+	}
+}
@@ -3,15 +3,26 @@ package services
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/audit"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/passwordpolicy"
 	"github.com/jonh-dev/partus_users/internal/services"
+	configMocks "github.com/jonh-dev/partus_users/internal/tests/mocks/config"
 	"github.com/jonh-dev/partus_users/internal/tests/mocks/encryption"
 	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	serviceMocks "github.com/jonh-dev/partus_users/internal/tests/mocks/services"
 	"github.com/jonh-dev/partus_users/internal/tests/utils"
 	"github.com/jonh-dev/partus_users/internal/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestAccountInfoService_CreateAccountInfo(t *testing.T) {
@@ -90,19 +101,9 @@ func TestAccountInfoService_CreateAccountInfo(t *testing.T) {
 			accountInfo:   utils.CreateLongPasswordAccountInfo(),
 			expectedError: validation.ErrInvalidPassword,
 		},
-		{
-			name:          "status da conta inválido",
-			accountInfo:   utils.CreateInvalidAccountStatusInfo(),
-			expectedError: validation.ErrInvalidAccountStatus,
-		},
-		{
-			name:          "motivo do status inválido",
-			accountInfo:   utils.CreateInvalidStatusReasonInfo(),
-			expectedError: validation.ErrInvalidStatusReason,
-		},
 	}
 
-	s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor)
+	s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -116,9 +117,631 @@ func TestAccountInfoService_CreateAccountInfo(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.NotNil(t, accountInfo)
+				assert.Equal(t, api.AccountStatus_PENDING, accountInfo.AccountStatus)
 			}
 
 			mockAccountInfoRepo.AssertExpectations(t)
 		})
 	}
+
+	t.Run("campos do servidor enviados pelo cliente são ignorados", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+		accountInfo := utils.CreateValidAccountInfo()
+		accountInfo.AccountStatus = api.AccountStatus_ACTIVE
+		accountInfo.StatusReason = "Conta ativa"
+		accountInfo.FailedLoginAttempts = 3
+		accountInfo.AccountLockedUntil = timestamppb.New(time.Now().Add(time.Hour))
+		accountInfo.AccountLockedReason = "bloqueada"
+		mockAccountInfoRepo.On("CreateAccountInfo", mock.Anything, mock.MatchedBy(func(created *api.AccountInfo) bool {
+			return created.AccountStatus == api.AccountStatus_PENDING && created.StatusReason != "Conta ativa" &&
+				created.FailedLoginAttempts == 0 && created.AccountLockedUntil == nil && created.AccountLockedReason == ""
+		})).Return(accountInfo, nil)
+
+		_, err := s.CreateAccountInfo(context.Background(), accountInfo)
+
+		assert.NoError(t, err)
+		mockAccountInfoRepo.AssertExpectations(t)
+	})
+}
+
+func TestAccountInfoService_CreateAccountInfo_DuplicateUsername(t *testing.T) {
+	mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+	mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+	accountInfo := utils.CreateValidAccountInfo()
+
+	mockPasswordEncryptor.On("EncryptPassword", mock.AnythingOfType("string")).Return("encryptedPassword", nil)
+	mockAccountInfoRepo.On("CreateAccountInfo", mock.Anything, accountInfo).Return(nil, status.Errorf(codes.AlreadyExists, "O nome de usuário já existe"))
+
+	s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+	_, err := s.CreateAccountInfo(context.Background(), accountInfo)
+
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	mockAccountInfoRepo.AssertExpectations(t)
+}
+
+func TestAccountInfoService_Authenticate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		accountInfo   *api.AccountInfo
+		validPassword bool
+		reactivated   bool
+		expectedCode  codes.Code
+	}{
+		{
+			name:          "login válido",
+			accountInfo:   utils.CreateStoredAccountInfo(),
+			validPassword: true,
+			expectedCode:  codes.OK,
+		},
+		{
+			name:          "senha inválida",
+			accountInfo:   utils.CreateStoredAccountInfo(),
+			validPassword: false,
+			expectedCode:  codes.Unauthenticated,
+		},
+		{
+			name:          "conta pendente",
+			accountInfo:   utils.CreateStoredAccountInfoWithStatus(api.AccountStatus_PENDING),
+			validPassword: true,
+			expectedCode:  codes.FailedPrecondition,
+		},
+		{
+			name:          "conta suspensa",
+			accountInfo:   utils.CreateStoredAccountInfoWithStatus(api.AccountStatus_SUSPENDED),
+			validPassword: true,
+			expectedCode:  codes.PermissionDenied,
+		},
+		{
+			name:          "conta inativa",
+			accountInfo:   utils.CreateStoredAccountInfoWithStatus(api.AccountStatus_INACTIVE),
+			validPassword: true,
+			expectedCode:  codes.NotFound,
+		},
+		{
+			name:          "suspensão temporária em vigor",
+			accountInfo:   utils.CreateSuspendedAccountInfo(time.Now().Add(time.Hour)),
+			validPassword: true,
+			expectedCode:  codes.PermissionDenied,
+		},
+		{
+			name:          "suspensão temporária vencida",
+			accountInfo:   utils.CreateSuspendedAccountInfo(time.Now().Add(-time.Minute)),
+			validPassword: true,
+			reactivated:   true,
+			expectedCode:  codes.OK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+			mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+			s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+			mockAccountInfoRepo.On("GetAccountInfoByUsername", mock.Anything, tc.accountInfo.Username).Return(tc.accountInfo, nil)
+			mockPasswordEncryptor.On("VerifyPassword", tc.accountInfo.Password, "ValidPassword123!").Return(tc.validPassword, nil)
+
+			if !tc.validPassword {
+				mockAccountInfoRepo.On("UpdateFailedLoginState", mock.Anything, tc.accountInfo, mock.AnythingOfType("*api.AccountInfo")).Return(true, nil)
+			}
+			if tc.reactivated {
+				mockAccountInfoRepo.On("ChangeAccountStatus", mock.Anything, tc.accountInfo.UserId, mock.MatchedBy(func(transition *model.StatusTransition) bool {
+					return transition.From == model.AccountStatus_SUSPENDED && transition.To == model.AccountStatus_ACTIVE && transition.Actor != ""
+				})).Return(true, nil)
+			}
+			if tc.expectedCode == codes.OK {
+				mockPasswordEncryptor.On("NeedsRehash", tc.accountInfo.Password).Return(false)
+			}
+
+			accountInfo, err := s.Authenticate(context.Background(), tc.accountInfo.Username, "ValidPassword123!")
+
+			if tc.expectedCode != codes.OK {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedCode, status.Code(err))
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, accountInfo)
+			}
+
+			mockAccountInfoRepo.AssertExpectations(t)
+			mockPasswordEncryptor.AssertExpectations(t)
+		})
+	}
+
+	t.Run("hash antigo é regravado no login", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+		s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+		stored := utils.CreateStoredAccountInfo()
+		oldHash := stored.Password
+		mockAccountInfoRepo.On("GetAccountInfoByUsername", mock.Anything, stored.Username).Return(stored, nil)
+		mockPasswordEncryptor.On("VerifyPassword", oldHash, "ValidPassword123!").Return(true, nil)
+		mockPasswordEncryptor.On("NeedsRehash", oldHash).Return(true)
+		mockPasswordEncryptor.On("EncryptPassword", "ValidPassword123!").Return("$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA", nil)
+		mockAccountInfoRepo.On("UpdatePasswordHash", mock.Anything, stored.UserId, oldHash, "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA").Return(true, nil)
+
+		accountInfo, err := s.Authenticate(context.Background(), stored.Username, "ValidPassword123!")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "$argon2id$v=19$m=65536,t=3,p=4$c2FsdA$aGFzaA", accountInfo.Password)
+		mockAccountInfoRepo.AssertExpectations(t)
+	})
+
+	t.Run("usuário desconhecido", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+		s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+		mockAccountInfoRepo.On("GetAccountInfoByUsername", mock.Anything, "unknown_user").Return(nil, status.Errorf(codes.NotFound, "AccountInfo não encontrado"))
+
+		_, err := s.Authenticate(context.Background(), "unknown_user", "ValidPassword123!")
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockPasswordEncryptor.AssertNotCalled(t, "VerifyPassword", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccountInfoService_RegisterFailedLogin(t *testing.T) {
+	policy := &config.LockoutPolicy{
+		MaxFailedAttempts: 3,
+		Window:            15 * time.Minute,
+		BaseLockDuration:  5 * time.Minute,
+		MaxLockDuration:   time.Hour,
+	}
+
+	testCases := []struct {
+		name             string
+		accountInfo      *api.AccountInfo
+		expectedAttempts int32
+		expectedLock     time.Duration
+	}{
+		{
+			name:             "primeira falha",
+			accountInfo:      utils.CreateStoredAccountInfo(),
+			expectedAttempts: 1,
+		},
+		{
+			name:             "falha que atinge o limite bloqueia a conta",
+			accountInfo:      utils.CreateFailedLoginAccountInfo(2, time.Now().Add(-time.Minute), time.Time{}),
+			expectedAttempts: 3,
+			expectedLock:     5 * time.Minute,
+		},
+		{
+			name:             "falha após bloqueio expirado dobra a duração",
+			accountInfo:      utils.CreateFailedLoginAccountInfo(3, time.Now().Add(-10*time.Minute), time.Now().Add(-time.Minute)),
+			expectedAttempts: 4,
+			expectedLock:     10 * time.Minute,
+		},
+		{
+			name:             "falha fora da janela reinicia a contagem",
+			accountInfo:      utils.CreateFailedLoginAccountInfo(2, time.Now().Add(-time.Hour), time.Time{}),
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+			s := services.NewAccountInfoService(mockAccountInfoRepo, new(encryption.MockPasswordEncryptor), utils.CreatePasswordChecker(), policy, config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+			mockAccountInfoRepo.On("GetAccountInfoByUsername", mock.Anything, tc.accountInfo.Username).Return(tc.accountInfo, nil)
+			mockAccountInfoRepo.On("UpdateFailedLoginState", mock.Anything, tc.accountInfo, mock.AnythingOfType("*api.AccountInfo")).Return(true, nil)
+
+			accountInfo, err := s.RegisterFailedLogin(context.Background(), tc.accountInfo.Username, "senha inválida")
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedAttempts, accountInfo.FailedLoginAttempts)
+			if tc.expectedLock > 0 {
+				assert.WithinDuration(t, time.Now().Add(tc.expectedLock), accountInfo.AccountLockedUntil.AsTime(), 5*time.Second)
+				assert.NotEmpty(t, accountInfo.AccountLockedReason)
+			} else {
+				assert.False(t, accountInfo.AccountLockedUntil.AsTime().After(time.Now()))
+			}
+
+			mockAccountInfoRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("atualização concorrente é repetida", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		s := services.NewAccountInfoService(mockAccountInfoRepo, new(encryption.MockPasswordEncryptor), utils.CreatePasswordChecker(), policy, config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+		stale := utils.CreateFailedLoginAccountInfo(1, time.Now().Add(-time.Minute), time.Time{})
+		fresh := utils.CreateFailedLoginAccountInfo(2, time.Now(), time.Time{})
+
+		mockAccountInfoRepo.On("GetAccountInfoByUsername", mock.Anything, stale.Username).Return(stale, nil)
+		mockAccountInfoRepo.On("UpdateFailedLoginState", mock.Anything, stale, mock.AnythingOfType("*api.AccountInfo")).Return(false, nil)
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, stale.UserId).Return(fresh, nil)
+		mockAccountInfoRepo.On("UpdateFailedLoginState", mock.Anything, fresh, mock.AnythingOfType("*api.AccountInfo")).Return(true, nil)
+
+		accountInfo, err := s.RegisterFailedLogin(context.Background(), stale.Username, "senha inválida")
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), accountInfo.FailedLoginAttempts)
+		mockAccountInfoRepo.AssertExpectations(t)
+	})
+
+	t.Run("código MFA inválido conta como falha", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		s := services.NewAccountInfoService(mockAccountInfoRepo, new(encryption.MockPasswordEncryptor), utils.CreatePasswordChecker(), policy, config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+		stored := utils.CreateFailedLoginAccountInfo(2, time.Now().Add(-time.Minute), time.Time{})
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, stored.UserId).Return(stored, nil)
+		mockAccountInfoRepo.On("UpdateFailedLoginState", mock.Anything, stored, mock.MatchedBy(func(updated *api.AccountInfo) bool {
+			return updated.FailedLoginAttempts == 3 && updated.LastFailedLoginReason == "código MFA inválido"
+		})).Return(true, nil)
+
+		err := s.RegisterFailedMFA(context.Background(), stored.UserId)
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		mockAccountInfoRepo.AssertExpectations(t)
+	})
+
+	t.Run("senha inválida na reautenticação conta como falha", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+		s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), policy, config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+		stored := utils.CreateFailedLoginAccountInfo(1, time.Now().Add(-time.Minute), time.Time{})
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, stored.UserId).Return(stored, nil)
+		mockPasswordEncryptor.On("VerifyPassword", stored.Password, "SenhaErrada1!").Return(false, nil)
+		mockAccountInfoRepo.On("UpdateFailedLoginState", mock.Anything, stored, mock.MatchedBy(func(updated *api.AccountInfo) bool {
+			return updated.FailedLoginAttempts == 2
+		})).Return(true, nil)
+
+		err := s.VerifyPassword(context.Background(), stored.UserId, "SenhaErrada1!")
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockAccountInfoRepo.AssertExpectations(t)
+	})
+
+	t.Run("conta bloqueada recusa a reautenticação", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+		s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), policy, config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+		locked := utils.CreateFailedLoginAccountInfo(3, time.Now(), time.Now().Add(5*time.Minute))
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, locked.UserId).Return(locked, nil)
+
+		err := s.VerifyPassword(context.Background(), locked.UserId, "ValidPassword123!")
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		mockPasswordEncryptor.AssertNotCalled(t, "VerifyPassword", mock.Anything, mock.Anything)
+	})
+
+	t.Run("conta bloqueada recusa o login", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+		s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), policy, config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+		locked := utils.CreateFailedLoginAccountInfo(3, time.Now(), time.Now().Add(5*time.Minute))
+		mockAccountInfoRepo.On("GetAccountInfoByUsername", mock.Anything, locked.Username).Return(locked, nil)
+
+		_, err := s.Authenticate(context.Background(), locked.Username, "ValidPassword123!")
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		mockPasswordEncryptor.AssertNotCalled(t, "VerifyPassword", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccountInfoService_UpdateUserCredentials(t *testing.T) {
+	t.Run("Username alterado", func(t *testing.T) {
+		original := utils.CreateValidAccountInfo()
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, original.UserId).Return(original, nil)
+		mockAccountInfoRepo.On("UpdateUserCredentials", mock.Anything, mock.MatchedBy(func(accountInfo *api.AccountInfo) bool {
+			return accountInfo.Username == "novo_username" && accountInfo.Password == "" && accountInfo.UpdatedAt != nil
+		})).Return(&api.AccountInfo{UserId: original.UserId, Username: "novo_username"}, nil)
+
+		s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+		accountInfo, err := s.UpdateUserCredentials(context.Background(), &api.AccountInfo{UserId: original.UserId, Username: "novo_username"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "novo_username", accountInfo.Username)
+		mockAccountInfoRepo.AssertExpectations(t)
+		mockPasswordEncryptor.AssertNotCalled(t, "EncryptPassword", mock.Anything)
+	})
+
+	t.Run("Senha é recusada", func(t *testing.T) {
+		accountInfo := utils.CreateValidAccountInfo()
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+
+		s := services.NewAccountInfoService(mockAccountInfoRepo, new(encryption.MockPasswordEncryptor), utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+		_, err := s.UpdateUserCredentials(context.Background(), accountInfo)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockAccountInfoRepo.AssertNotCalled(t, "UpdateUserCredentials", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccountInfoService_ChangePassword(t *testing.T) {
+	userId := "507f1f77bcf86cd799439011"
+	newPassword := "NovaSenha@123"
+	current := &api.AccountInfo{UserId: userId, Password: "currentHash"}
+	history := []string{"oldHash1", "oldHash2"}
+
+	newService := func(mockAccountInfoRepo *mocks.MockAccountInfoRepository, mockPasswordEncryptor *encryption.MockPasswordEncryptor, mockSessionService *serviceMocks.MockSessionService) *services.AccountInfoService {
+		return services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), &config.PasswordPolicy{HistorySize: 2}, mockSessionService, new(configMocks.MockTransactionRunner))
+	}
+
+	t.Run("Senha alterada e histórico atualizado", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+		mockSessionService := new(serviceMocks.MockSessionService)
+
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(current, nil)
+		mockAccountInfoRepo.On("GetPasswordHistory", mock.Anything, userId).Return(history, nil)
+		mockPasswordEncryptor.On("VerifyPassword", "currentHash", "ValidPassword123!").Return(true, nil)
+		mockPasswordEncryptor.On("VerifyPassword", mock.Anything, newPassword).Return(false, nil)
+		mockPasswordEncryptor.On("EncryptPassword", newPassword).Return("newHash", nil)
+		mockAccountInfoRepo.On("ChangePassword", mock.Anything, userId, "currentHash", "newHash", 2, mock.AnythingOfType("time.Time")).Return(true, nil)
+		mockSessionService.On("RevokeOtherSessions", mock.Anything, userId).Return(1, nil)
+
+		err := newService(mockAccountInfoRepo, mockPasswordEncryptor, mockSessionService).ChangePassword(context.Background(), userId, "ValidPassword123!", newPassword)
+
+		assert.NoError(t, err)
+		mockPasswordEncryptor.AssertNumberOfCalls(t, "VerifyPassword", 4)
+		mockAccountInfoRepo.AssertExpectations(t)
+		mockSessionService.AssertExpectations(t)
+	})
+
+	t.Run("Senha atual incorreta", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(current, nil)
+		mockPasswordEncryptor.On("VerifyPassword", "currentHash", "SenhaErrada1!").Return(false, nil)
+
+		err := newService(mockAccountInfoRepo, mockPasswordEncryptor, new(serviceMocks.MockSessionService)).ChangePassword(context.Background(), userId, "SenhaErrada1!", newPassword)
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockAccountInfoRepo.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Senha do histórico é recusada", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(current, nil)
+		mockAccountInfoRepo.On("GetPasswordHistory", mock.Anything, userId).Return(history, nil)
+		mockPasswordEncryptor.On("VerifyPassword", "currentHash", "ValidPassword123!").Return(true, nil)
+		mockPasswordEncryptor.On("VerifyPassword", "currentHash", newPassword).Return(false, nil)
+		mockPasswordEncryptor.On("VerifyPassword", "oldHash1", newPassword).Return(true, nil)
+
+		err := newService(mockAccountInfoRepo, mockPasswordEncryptor, new(serviceMocks.MockSessionService)).ChangePassword(context.Background(), userId, "ValidPassword123!", newPassword)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockPasswordEncryptor.AssertNotCalled(t, "EncryptPassword", mock.Anything)
+		mockAccountInfoRepo.AssertNotCalled(t, "ChangePassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Senha fácil de adivinhar é recusada", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockPasswordEncryptor := new(encryption.MockPasswordEncryptor)
+		checker := passwordpolicy.NewChecker(nil, passwordpolicy.DefaultCommonPasswords(), 3)
+		s := services.NewAccountInfoService(mockAccountInfoRepo, mockPasswordEncryptor, checker, config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(current, nil)
+		mockPasswordEncryptor.On("VerifyPassword", "currentHash", "ValidPassword123!").Return(true, nil)
+
+		err := s.ChangePassword(context.Background(), userId, "ValidPassword123!", "Password1!")
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), "fácil de adivinhar")
+		mockAccountInfoRepo.AssertNotCalled(t, "GetPasswordHistory", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Senha fraca é recusada", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+
+		err := newService(mockAccountInfoRepo, new(encryption.MockPasswordEncryptor), new(serviceMocks.MockSessionService)).ChangePassword(context.Background(), userId, "ValidPassword123!", "fraca")
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockAccountInfoRepo.AssertNotCalled(t, "GetAccountInfo", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccountStatusTransitions(t *testing.T) {
+	statuses := []model.AccountStatus{model.AccountStatus_ACTIVE, model.AccountStatus_INACTIVE, model.AccountStatus_PENDING, model.AccountStatus_SUSPENDED}
+	allowed := map[[2]model.AccountStatus]bool{
+		{model.AccountStatus_PENDING, model.AccountStatus_ACTIVE}:     true,
+		{model.AccountStatus_PENDING, model.AccountStatus_INACTIVE}:   true,
+		{model.AccountStatus_ACTIVE, model.AccountStatus_SUSPENDED}:   true,
+		{model.AccountStatus_ACTIVE, model.AccountStatus_INACTIVE}:    true,
+		{model.AccountStatus_SUSPENDED, model.AccountStatus_ACTIVE}:   true,
+		{model.AccountStatus_SUSPENDED, model.AccountStatus_INACTIVE}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			assert.Equal(t, allowed[[2]model.AccountStatus{from, to}], model.CanTransition(from, to), "%s -> %s", from, to)
+		}
+	}
+}
+
+func TestAccountInfoService_ChangeAccountStatus(t *testing.T) {
+	userId := "507f1f77bcf86cd799439011"
+	active := &api.AccountInfo{UserId: userId, AccountStatus: api.AccountStatus_ACTIVE}
+
+	newService := func(mockAccountInfoRepo *mocks.MockAccountInfoRepository, mockSessionService *serviceMocks.MockSessionService) *services.AccountInfoService {
+		mockTxRunner := new(configMocks.MockTransactionRunner)
+		mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+		return services.NewAccountInfoService(mockAccountInfoRepo, new(encryption.MockPasswordEncryptor), utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), mockSessionService, mockTxRunner)
+	}
+
+	t.Run("Suspensão temporária registrada e sessões encerradas", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockSessionService := new(serviceMocks.MockSessionService)
+		suspendedUntil := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(active, nil)
+		mockAccountInfoRepo.On("ChangeAccountStatus", mock.Anything, userId, mock.MatchedBy(func(transition *model.StatusTransition) bool {
+			return transition.From == model.AccountStatus_ACTIVE && transition.To == model.AccountStatus_SUSPENDED &&
+				transition.Actor == "admin-1" && transition.Reason == "Spam" && transition.SuspendedUntil.Equal(suspendedUntil)
+		})).Return(true, nil)
+		mockSessionService.On("RevokeAllUserSessions", mock.Anything, userId, mock.AnythingOfType("string")).Return(2, nil)
+
+		_, err := newService(mockAccountInfoRepo, mockSessionService).ChangeAccountStatus(context.Background(), &api.ChangeAccountStatusRequest{
+			UserId:         userId,
+			AccountStatus:  api.AccountStatus_SUSPENDED,
+			Reason:         " Spam ",
+			Actor:          "admin-1",
+			SuspendedUntil: timestamppb.New(suspendedUntil),
+		})
+
+		assert.NoError(t, err)
+		mockAccountInfoRepo.AssertExpectations(t)
+		mockSessionService.AssertExpectations(t)
+	})
+
+	t.Run("Falha ao encerrar as sessões desfaz a mudança de status", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockSessionService := new(serviceMocks.MockSessionService)
+		mockTxRunner := new(configMocks.MockTransactionRunner)
+
+		mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(active, nil)
+		mockAccountInfoRepo.On("ChangeAccountStatus", mock.Anything, userId, mock.AnythingOfType("*model.StatusTransition")).Return(true, nil)
+		mockSessionService.On("RevokeAllUserSessions", mock.Anything, userId, mock.AnythingOfType("string")).Return(0, assert.AnError)
+
+		s := services.NewAccountInfoService(mockAccountInfoRepo, new(encryption.MockPasswordEncryptor), utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), mockSessionService, mockTxRunner)
+		_, err := s.ChangeAccountStatus(context.Background(), &api.ChangeAccountStatusRequest{
+			UserId: userId, AccountStatus: api.AccountStatus_INACTIVE, Reason: "Pedido do usuário", Actor: "admin-1",
+		})
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		mockTxRunner.AssertNumberOfCalls(t, "WithTransaction", 1)
+		mockAccountInfoRepo.AssertNumberOfCalls(t, "GetAccountInfo", 1)
+	})
+
+	t.Run("Ativação de conta pendente fica com a verificação do e-mail", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(&api.AccountInfo{UserId: userId, AccountStatus: api.AccountStatus_PENDING}, nil)
+
+		_, err := newService(mockAccountInfoRepo, new(serviceMocks.MockSessionService)).ChangeAccountStatus(context.Background(), &api.ChangeAccountStatusRequest{
+			UserId: userId, AccountStatus: api.AccountStatus_ACTIVE, Reason: "Documentos conferidos", Actor: "admin-1",
+		})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		mockAccountInfoRepo.AssertNotCalled(t, "ChangeAccountStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Responsável vem do access token de administrador", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockSessionService := new(serviceMocks.MockSessionService)
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(&api.AccountInfo{UserId: userId, AccountStatus: api.AccountStatus_SUSPENDED}, nil)
+		mockAccountInfoRepo.On("ChangeAccountStatus", mock.Anything, userId, mock.MatchedBy(func(transition *model.StatusTransition) bool {
+			return transition.Actor == "admin-2"
+		})).Return(true, nil)
+
+		ctx := audit.WithMetadata(context.Background(), audit.Metadata{Actor: "admin-2", Roles: []string{model.RoleAdmin}})
+		_, err := newService(mockAccountInfoRepo, mockSessionService).ChangeAccountStatus(ctx, &api.ChangeAccountStatusRequest{
+			UserId: userId, AccountStatus: api.AccountStatus_ACTIVE, Reason: "Suspensão revista",
+		})
+
+		assert.NoError(t, err)
+		mockAccountInfoRepo.AssertExpectations(t)
+		mockSessionService.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Chamada pelo gRPC sem administrador é recusada", func(t *testing.T) {
+		testCases := []struct {
+			name     string
+			metadata audit.Metadata
+			actor    string
+			code     codes.Code
+		}{
+			{"sem access token", audit.Metadata{Actor: audit.AnonymousActor}, "", codes.Unauthenticated},
+			{"sem access token com actor", audit.Metadata{Actor: audit.AnonymousActor}, "admin-1", codes.Unauthenticated},
+			{"usuário comum", audit.Metadata{Actor: userId}, "", codes.PermissionDenied},
+			{"suporte", audit.Metadata{Actor: "support-1", Roles: []string{model.RoleSupport}}, "", codes.PermissionDenied},
+			{"administrador com actor", audit.Metadata{Actor: "admin-2", Roles: []string{model.RoleAdmin}}, "admin-1", codes.InvalidArgument},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+
+				ctx := audit.WithMetadata(context.Background(), tc.metadata)
+				_, err := newService(mockAccountInfoRepo, new(serviceMocks.MockSessionService)).ChangeAccountStatus(ctx, &api.ChangeAccountStatusRequest{
+					UserId: userId, AccountStatus: api.AccountStatus_SUSPENDED, Reason: "Spam", Actor: tc.actor,
+				})
+
+				assert.Equal(t, tc.code, status.Code(err))
+				mockAccountInfoRepo.AssertNotCalled(t, "ChangeAccountStatus", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Transição não permitida", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(&api.AccountInfo{UserId: userId, AccountStatus: api.AccountStatus_INACTIVE}, nil)
+
+		_, err := newService(mockAccountInfoRepo, new(serviceMocks.MockSessionService)).ChangeAccountStatus(context.Background(), &api.ChangeAccountStatusRequest{
+			UserId: userId, AccountStatus: api.AccountStatus_ACTIVE, Reason: "Reativação", Actor: "admin-1",
+		})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		mockAccountInfoRepo.AssertNotCalled(t, "ChangeAccountStatus", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Status alterado por outra requisição", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+		mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, userId).Return(active, nil)
+		mockAccountInfoRepo.On("ChangeAccountStatus", mock.Anything, userId, mock.AnythingOfType("*model.StatusTransition")).Return(false, nil)
+
+		_, err := newService(mockAccountInfoRepo, new(serviceMocks.MockSessionService)).ChangeAccountStatus(context.Background(), &api.ChangeAccountStatusRequest{
+			UserId: userId, AccountStatus: api.AccountStatus_INACTIVE, Reason: "Pedido do usuário", Actor: userId,
+		})
+
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("Todas as violações são retornadas", func(t *testing.T) {
+		mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+
+		_, err := newService(mockAccountInfoRepo, new(serviceMocks.MockSessionService)).ChangeAccountStatus(context.Background(), &api.ChangeAccountStatusRequest{
+			UserId:         userId,
+			AccountStatus:  api.AccountStatus_ACTIVE,
+			SuspendedUntil: timestamppb.New(time.Now().Add(time.Hour)),
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		var reasons []string
+		for _, detail := range status.Convert(err).Details() {
+			if errorInfo, ok := detail.(*errdetails.ErrorInfo); ok {
+				reasons = append(reasons, errorInfo.Reason)
+			}
+		}
+		assert.Equal(t, []string{validation.ReasonMissingTransitionReason, validation.ReasonMissingActor, validation.ReasonInvalidSuspensionEnd}, reasons)
+		mockAccountInfoRepo.AssertNotCalled(t, "GetAccountInfo", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAccountInfoService_ReactivateExpiredSuspensions(t *testing.T) {
+	now := time.Now()
+	expired := utils.CreateSuspendedAccountInfo(now.Add(-time.Minute))
+	expired.UserId = "507f1f77bcf86cd799439011"
+	// Suspensa de novo entre a busca e a leitura.
+	extended := utils.CreateSuspendedAccountInfo(now.Add(time.Hour))
+	extended.UserId = "507f1f77bcf86cd799439012"
+
+	mockAccountInfoRepo := new(mocks.MockAccountInfoRepository)
+	mockAccountInfoRepo.On("FindExpiredSuspensions", mock.Anything, now).Return([]string{expired.UserId, extended.UserId}, nil)
+	mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, expired.UserId).Return(expired, nil)
+	mockAccountInfoRepo.On("GetAccountInfo", mock.Anything, extended.UserId).Return(extended, nil)
+	mockAccountInfoRepo.On("ChangeAccountStatus", mock.Anything, expired.UserId, mock.AnythingOfType("*model.StatusTransition")).Return(true, nil)
+
+	s := services.NewAccountInfoService(mockAccountInfoRepo, new(encryption.MockPasswordEncryptor), utils.CreatePasswordChecker(), config.DefaultLockoutPolicy(), config.DefaultPasswordPolicy(), new(serviceMocks.MockSessionService), new(configMocks.MockTransactionRunner))
+	reactivated, err := s.ReactivateExpiredSuspensions(context.Background(), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, reactivated)
+	mockAccountInfoRepo.AssertExpectations(t)
+	mockAccountInfoRepo.AssertNumberOfCalls(t, "ChangeAccountStatus", 1)
 }
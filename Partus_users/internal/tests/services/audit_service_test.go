@@ -0,0 +1,90 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
+	"github.com/jonh-dev/partus_users/internal/services"
+	repository "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestAuditService_ListAuditEvents(t *testing.T) {
+	userId := "507f1f77bcf86cd799439011"
+	occurredAfter := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	events := []*model.AuditEvent{
+		{Sequence: 9, DocumentId: userId, Operation: model.AuditOperationUpdate},
+		{Sequence: 7, DocumentId: userId, Operation: model.AuditOperationUpdate},
+		{Sequence: 4, DocumentId: userId, Operation: model.AuditOperationCreate},
+	}
+
+	t.Run("Primeira página com filtros", func(t *testing.T) {
+		mockAuditRepo := new(repository.MockAuditRepository)
+		mockAuditRepo.On("ListAuditEvents", mock.Anything, repositories.ListAuditEventsQuery{
+			DocumentId:    userId,
+			OccurredAfter: occurredAfter,
+			Limit:         3,
+		}).Return(events, nil)
+
+		response, err := services.NewAuditService(mockAuditRepo).ListAuditEvents(context.Background(), &api.ListAuditEventsRequest{
+			UserId:        userId,
+			OccurredAfter: timestamppb.New(occurredAfter),
+			PageSize:      2,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Events, 2)
+		assert.Equal(t, int64(9), response.Events[0].Sequence)
+		assert.NotEmpty(t, response.NextPageToken)
+
+		mockAuditRepo.On("ListAuditEvents", mock.Anything, repositories.ListAuditEventsQuery{
+			DocumentId:     userId,
+			OccurredAfter:  occurredAfter,
+			BeforeSequence: 7,
+			Limit:          3,
+		}).Return(events[2:], nil)
+
+		response, err = services.NewAuditService(mockAuditRepo).ListAuditEvents(context.Background(), &api.ListAuditEventsRequest{
+			UserId:        userId,
+			OccurredAfter: timestamppb.New(occurredAfter),
+			PageSize:      2,
+			PageToken:     response.NextPageToken,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Events, 1)
+		assert.Empty(t, response.NextPageToken)
+	})
+
+	invalidRequests := []struct {
+		name    string
+		request *api.ListAuditEventsRequest
+	}{
+		{name: "ID de usuário inválido", request: &api.ListAuditEventsRequest{UserId: "123"}},
+		{name: "page_size acima do limite", request: &api.ListAuditEventsRequest{PageSize: 1000}},
+		{name: "pageToken inválido", request: &api.ListAuditEventsRequest{PageToken: "%%%"}},
+		{name: "Intervalo invertido", request: &api.ListAuditEventsRequest{
+			OccurredAfter:  timestamppb.New(occurredAfter),
+			OccurredBefore: timestamppb.New(occurredAfter.Add(-time.Hour)),
+		}},
+	}
+
+	for _, tc := range invalidRequests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockAuditRepo := new(repository.MockAuditRepository)
+
+			_, err := services.NewAuditService(mockAuditRepo).ListAuditEvents(context.Background(), tc.request)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			mockAuditRepo.AssertNotCalled(t, "ListAuditEvents", mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/mailer"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/services"
+	configMocks "github.com/jonh-dev/partus_users/internal/tests/mocks/config"
+	repository "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestEmailVerificationPolicy() *config.EmailVerificationPolicy {
+	return &config.EmailVerificationPolicy{
+		SigningKey:      []byte("chave-de-teste-com-pelo-menos-32-caracteres"),
+		TokenTTL:        time.Hour,
+		VerificationURL: "https://partus.example.com/verificar-email",
+	}
+}
+
+func tokenFromMessage(t *testing.T, message mailer.Message) string {
+	link := regexp.MustCompile(`https://\S+`).FindString(message.Body)
+	parsed, err := url.Parse(link)
+	assert.NoError(t, err)
+	return parsed.Query().Get("token")
+}
+
+func TestEmailVerificationService_SendAndVerify(t *testing.T) {
+	userId := primitive.NewObjectID().Hex()
+	email := "john.doe@example.com"
+
+	memoryMailer := mailer.NewMemoryMailer()
+	mockPersonalInfoRepo := new(repository.MockPersonalInfoRepository)
+	mockAccountInfoRepo := new(repository.MockAccountInfoRepository)
+	mockTxRunner := new(configMocks.MockTransactionRunner)
+	mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+	mockPersonalInfoRepo.On("MarkEmailVerified", mock.Anything, userId, email, mock.AnythingOfType("time.Time")).Return(true, nil)
+	mockAccountInfoRepo.On("ChangeAccountStatus", mock.Anything, userId, mock.MatchedBy(func(transition *model.StatusTransition) bool {
+		return transition.From == model.AccountStatus_PENDING && transition.To == model.AccountStatus_ACTIVE && transition.Actor == "partus_users" && transition.Reason != ""
+	})).Return(true, nil)
+
+	s := services.NewEmailVerificationService(mockPersonalInfoRepo, mockAccountInfoRepo, mockTxRunner, memoryMailer, newTestEmailVerificationPolicy())
+
+	err := s.SendVerificationEmail(context.Background(), userId, email, "John", "Europe/Lisbon")
+	assert.NoError(t, err)
+
+	messages := memoryMailer.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, email, messages[0].To)
+	assert.Contains(t, messages[0].Body, "John")
+	assert.Regexp(t, `até \d{2}/\d{2}/\d{4} \d{2}:\d{2} WES?T:`, messages[0].Body)
+
+	response, err := s.VerifyEmail(context.Background(), &api.VerifyEmailRequest{Token: tokenFromMessage(t, messages[0])})
+
+	assert.NoError(t, err)
+	assert.Equal(t, userId, response.UserId)
+	mockTxRunner.AssertExpectations(t)
+	mockPersonalInfoRepo.AssertExpectations(t)
+	mockAccountInfoRepo.AssertExpectations(t)
+}
+
+func TestEmailVerificationService_VerifyEmail_RejectedTokens(t *testing.T) {
+	userId := primitive.NewObjectID().Hex()
+	policy := newTestEmailVerificationPolicy()
+
+	expired, _, err := tokens.NewEmailVerificationSigner(policy.SigningKey, time.Hour).Sign(userId, "john.doe@example.com", time.Now().Add(-2*time.Hour))
+	assert.NoError(t, err)
+	forged, _, err := tokens.NewEmailVerificationSigner([]byte("outra-chave-com-pelo-menos-32-caracteres"), time.Hour).Sign(userId, "john.doe@example.com", time.Now())
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name  string
+		token string
+	}{
+		{"expired", expired},
+		{"signed with another key", forged},
+		{"malformed", "not-a-token"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockTxRunner := new(configMocks.MockTransactionRunner)
+			s := services.NewEmailVerificationService(new(repository.MockPersonalInfoRepository), new(repository.MockAccountInfoRepository), mockTxRunner, mailer.NewMemoryMailer(), policy)
+
+			_, err := s.VerifyEmail(context.Background(), &api.VerifyEmailRequest{Token: tc.token})
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+			mockTxRunner.AssertNotCalled(t, "WithTransaction", mock.Anything)
+		})
+	}
+}
+
+func TestEmailVerificationService_VerifyEmail_EmailChanged(t *testing.T) {
+	userId := primitive.NewObjectID().Hex()
+	policy := newTestEmailVerificationPolicy()
+	token, _, err := tokens.NewEmailVerificationSigner(policy.SigningKey, policy.TokenTTL).Sign(userId, "old@example.com", time.Now())
+	assert.NoError(t, err)
+
+	mockPersonalInfoRepo := new(repository.MockPersonalInfoRepository)
+	mockAccountInfoRepo := new(repository.MockAccountInfoRepository)
+	mockTxRunner := new(configMocks.MockTransactionRunner)
+	mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+	mockPersonalInfoRepo.On("MarkEmailVerified", mock.Anything, userId, "old@example.com", mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	s := services.NewEmailVerificationService(mockPersonalInfoRepo, mockAccountInfoRepo, mockTxRunner, mailer.NewMemoryMailer(), policy)
+	_, err = s.VerifyEmail(context.Background(), &api.VerifyEmailRequest{Token: token})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	mockAccountInfoRepo.AssertNotCalled(t, "ChangeAccountStatus", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEmailVerificationService_SendVerificationEmail_MailerFailure(t *testing.T) {
+	memoryMailer := mailer.NewMemoryMailer()
+	memoryMailer.FailWith(errors.New("conexão recusada"))
+
+	s := services.NewEmailVerificationService(new(repository.MockPersonalInfoRepository), new(repository.MockAccountInfoRepository), new(configMocks.MockTransactionRunner), memoryMailer, newTestEmailVerificationPolicy())
+	err := s.SendVerificationEmail(context.Background(), primitive.NewObjectID().Hex(), "john.doe@example.com", "John", "")
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Empty(t, memoryMailer.Messages())
+}
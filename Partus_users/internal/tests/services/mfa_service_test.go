@@ -0,0 +1,333 @@
+package services_test
+
+import (
+	"context"
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/encryption"
+	"github.com/jonh-dev/partus_users/internal/mfa"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/services"
+	repository "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/services"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func newTestMFAPolicy() *config.MFAPolicy {
+	return &config.MFAPolicy{EncryptionKey: make([]byte, 32), Issuer: "Partus", ChallengeTTL: 5 * time.Minute, MaxChallengeAttempts: 5}
+}
+
+func newTestMFA(t *testing.T, secretCipher encryption.SecretCipher, userId primitive.ObjectID) (*model.MFA, []byte) {
+	secret, err := mfa.GenerateSecret()
+	assert.NoError(t, err)
+	encryptedSecret, err := secretCipher.Encrypt(secret)
+	assert.NoError(t, err)
+	return &model.MFA{Id: primitive.NewObjectID(), UserId: userId, EncryptedSecret: encryptedSecret, Enabled: true}, secret
+}
+
+func TestMFAService_EnrollTOTP(t *testing.T) {
+	userId := primitive.NewObjectID().Hex()
+	issuer := newTestTokenIssuer(t)
+	ctx := withAccessToken(t, issuer, userId, primitive.NewObjectID().Hex())
+	secretCipher, err := encryption.NewAESGCMCipher(make([]byte, 32))
+	assert.NoError(t, err)
+
+	t.Run("generates a pending secret", func(t *testing.T) {
+		mockMFARepo := new(repository.MockMFARepository)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("VerifyPassword", mock.Anything, userId, "ValidPassword123!").Return(nil)
+		mockAccountInfoService.On("GetAccountInfo", mock.Anything, &api.GetAccountInfoRequest{UserId: userId}).Return(&api.AccountInfo{UserId: userId, Username: "johndoe"}, nil)
+
+		var saved *model.MFA
+		mockMFARepo.On("SavePendingMFA", mock.Anything, mock.AnythingOfType("*model.MFA")).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*model.MFA)
+		}).Return(nil)
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		response, err := s.EnrollTOTP(ctx, &api.EnrollTOTPRequest{UserId: userId, Password: "ValidPassword123!"})
+
+		assert.NoError(t, err)
+		assert.Contains(t, response.OtpauthUri, "otpauth://totp/Partus:johndoe?")
+		assert.Contains(t, response.OtpauthUri, "secret="+response.Secret)
+		assert.False(t, saved.Enabled)
+		assert.NotContains(t, saved.EncryptedSecret, response.Secret)
+
+		decrypted, err := secretCipher.Decrypt(saved.EncryptedSecret)
+		assert.NoError(t, err)
+		secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(response.Secret)
+		assert.NoError(t, err)
+		assert.Equal(t, secret, decrypted)
+	})
+
+	t.Run("requires the password", func(t *testing.T) {
+		mockMFARepo := new(repository.MockMFARepository)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("VerifyPassword", mock.Anything, userId, "wrong").Return(status.Errorf(codes.Unauthenticated, "Senha inválida"))
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.EnrollTOTP(ctx, &api.EnrollTOTPRequest{UserId: userId, Password: "wrong"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockMFARepo.AssertNotCalled(t, "SavePendingMFA", mock.Anything, mock.Anything)
+	})
+
+	t.Run("token of another user", func(t *testing.T) {
+		mockMFARepo := new(repository.MockMFARepository)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.EnrollTOTP(withAccessToken(t, issuer, primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()), &api.EnrollTOTPRequest{UserId: userId, Password: "ValidPassword123!"})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockAccountInfoService.AssertNotCalled(t, "VerifyPassword", mock.Anything, mock.Anything, mock.Anything)
+		mockMFARepo.AssertNotCalled(t, "SavePendingMFA", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMFAService_ConfirmTOTP(t *testing.T) {
+	userId := primitive.NewObjectID()
+	issuer := newTestTokenIssuer(t)
+	ctx := withAccessToken(t, issuer, userId.Hex(), primitive.NewObjectID().Hex())
+	secretCipher, err := encryption.NewAESGCMCipher(make([]byte, 32))
+	assert.NoError(t, err)
+
+	t.Run("valid code enables mfa", func(t *testing.T) {
+		pending, secret := newTestMFA(t, secretCipher, userId)
+		pending.Enabled = false
+		mockMFARepo := new(repository.MockMFARepository)
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(pending, nil)
+		mockMFARepo.On("EnableMFA", mock.Anything, userId.Hex(), mock.AnythingOfType("[]string"), mock.AnythingOfType("int64"), mock.AnythingOfType("time.Time")).Return(true, nil)
+
+		s := services.NewMFAService(mockMFARepo, new(mocks.MockAccountInfoService), secretCipher, newTestMFAPolicy(), issuer)
+		response, err := s.ConfirmTOTP(ctx, &api.ConfirmTOTPRequest{UserId: userId.Hex(), Code: mfa.DefaultTOTP().GenerateCode(secret, time.Now())})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.RecoveryCodes, mfa.RecoveryCodeCount)
+
+		hashes := mockMFARepo.Calls[1].Arguments.Get(2).([]string)
+		assert.Len(t, hashes, mfa.RecoveryCodeCount)
+		assert.Equal(t, mfa.HashRecoveryCode(response.RecoveryCodes[0]), hashes[0])
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		pending, _ := newTestMFA(t, secretCipher, userId)
+		pending.Enabled = false
+		mockMFARepo := new(repository.MockMFARepository)
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(pending, nil)
+
+		s := services.NewMFAService(mockMFARepo, new(mocks.MockAccountInfoService), secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.ConfirmTOTP(ctx, &api.ConfirmTOTPRequest{UserId: userId.Hex(), Code: "abcdef"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockMFARepo.AssertNotCalled(t, "EnableMFA", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("without access token", func(t *testing.T) {
+		mockMFARepo := new(repository.MockMFARepository)
+
+		s := services.NewMFAService(mockMFARepo, new(mocks.MockAccountInfoService), secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.ConfirmTOTP(context.Background(), &api.ConfirmTOTPRequest{UserId: userId.Hex(), Code: "123456"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockMFARepo.AssertNotCalled(t, "GetMFA", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMFAService_VerifyChallenge(t *testing.T) {
+	userId := primitive.NewObjectID()
+	issuer := newTestTokenIssuer(t)
+	challengeToken := "challenge-token"
+	tokenHash := tokens.HashMFAChallengeToken(challengeToken)
+	secretCipher, err := encryption.NewAESGCMCipher(make([]byte, 32))
+	assert.NoError(t, err)
+
+	newChallenge := func(attempts int32) *model.MFAChallenge {
+		return &model.MFAChallenge{Id: primitive.NewObjectID(), UserId: userId, TokenHash: tokenHash, Attempts: attempts, ExpiresAt: time.Now().Add(time.Minute)}
+	}
+	newAccountInfoService := func() *mocks.MockAccountInfoService {
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("GetAccountInfo", mock.Anything, &api.GetAccountInfoRequest{UserId: userId.Hex()}).Return(&api.AccountInfo{UserId: userId.Hex()}, nil)
+		return mockAccountInfoService
+	}
+
+	t.Run("valid totp code", func(t *testing.T) {
+		userMFA, secret := newTestMFA(t, secretCipher, userId)
+		challenge := newChallenge(0)
+		mockMFARepo := new(repository.MockMFARepository)
+		mockMFARepo.On("GetChallengeByTokenHash", mock.Anything, tokenHash).Return(challenge, nil)
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(userMFA, nil)
+		mockMFARepo.On("UseTOTPStep", mock.Anything, userId.Hex(), mock.AnythingOfType("int64")).Return(true, nil)
+		mockMFARepo.On("DeleteChallenge", mock.Anything, challenge.Id).Return(true, nil)
+
+		s := services.NewMFAService(mockMFARepo, newAccountInfoService(), secretCipher, newTestMFAPolicy(), issuer)
+		verifiedUserId, err := s.VerifyChallenge(context.Background(), challengeToken, mfa.DefaultTOTP().GenerateCode(secret, time.Now()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId.Hex(), verifiedUserId)
+		mockMFARepo.AssertExpectations(t)
+	})
+
+	t.Run("reused totp code", func(t *testing.T) {
+		userMFA, secret := newTestMFA(t, secretCipher, userId)
+		userMFA.LastUsedStep = mfa.DefaultTOTP().Step(time.Now()) + 1
+		challenge := newChallenge(0)
+		mockMFARepo := new(repository.MockMFARepository)
+		mockMFARepo.On("GetChallengeByTokenHash", mock.Anything, tokenHash).Return(challenge, nil)
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(userMFA, nil)
+		mockMFARepo.On("IncrementChallengeAttempts", mock.Anything, challenge.Id).Return(nil)
+		mockAccountInfoService := newAccountInfoService()
+		mockAccountInfoService.On("RegisterFailedMFA", mock.Anything, userId.Hex()).Return(nil)
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.VerifyChallenge(context.Background(), challengeToken, mfa.DefaultTOTP().GenerateCode(secret, time.Now()))
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockMFARepo.AssertNotCalled(t, "UseTOTPStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("recovery code", func(t *testing.T) {
+		userMFA, _ := newTestMFA(t, secretCipher, userId)
+		challenge := newChallenge(0)
+		mockMFARepo := new(repository.MockMFARepository)
+		mockMFARepo.On("GetChallengeByTokenHash", mock.Anything, tokenHash).Return(challenge, nil)
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(userMFA, nil)
+		mockMFARepo.On("UseRecoveryCode", mock.Anything, userId.Hex(), mfa.HashRecoveryCode("abcde-fghjk")).Return(true, nil)
+		mockMFARepo.On("DeleteChallenge", mock.Anything, challenge.Id).Return(true, nil)
+
+		s := services.NewMFAService(mockMFARepo, newAccountInfoService(), secretCipher, newTestMFAPolicy(), issuer)
+		verifiedUserId, err := s.VerifyChallenge(context.Background(), challengeToken, "ABCDE-FGHJK")
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId.Hex(), verifiedUserId)
+	})
+
+	t.Run("invalid code counts an attempt", func(t *testing.T) {
+		userMFA, _ := newTestMFA(t, secretCipher, userId)
+		challenge := newChallenge(0)
+		mockMFARepo := new(repository.MockMFARepository)
+		mockMFARepo.On("GetChallengeByTokenHash", mock.Anything, tokenHash).Return(challenge, nil)
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(userMFA, nil)
+		mockMFARepo.On("UseRecoveryCode", mock.Anything, userId.Hex(), mock.AnythingOfType("string")).Return(false, nil)
+		mockMFARepo.On("IncrementChallengeAttempts", mock.Anything, challenge.Id).Return(nil)
+		mockAccountInfoService := newAccountInfoService()
+		mockAccountInfoService.On("RegisterFailedMFA", mock.Anything, userId.Hex()).Return(nil)
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.VerifyChallenge(context.Background(), challengeToken, "wrong-code")
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockMFARepo.AssertExpectations(t)
+		mockAccountInfoService.AssertExpectations(t)
+	})
+
+	t.Run("invalid code that locks the account", func(t *testing.T) {
+		userMFA, _ := newTestMFA(t, secretCipher, userId)
+		challenge := newChallenge(0)
+		mockMFARepo := new(repository.MockMFARepository)
+		mockMFARepo.On("GetChallengeByTokenHash", mock.Anything, tokenHash).Return(challenge, nil)
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(userMFA, nil)
+		mockMFARepo.On("UseRecoveryCode", mock.Anything, userId.Hex(), mock.AnythingOfType("string")).Return(false, nil)
+		mockMFARepo.On("IncrementChallengeAttempts", mock.Anything, challenge.Id).Return(nil)
+		mockMFARepo.On("DeleteChallenge", mock.Anything, challenge.Id).Return(true, nil)
+		mockAccountInfoService := newAccountInfoService()
+		mockAccountInfoService.On("RegisterFailedMFA", mock.Anything, userId.Hex()).Return(status.Errorf(codes.ResourceExhausted, "A conta está bloqueada"))
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.VerifyChallenge(context.Background(), challengeToken, "wrong-code")
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		mockMFARepo.AssertExpectations(t)
+	})
+
+	t.Run("locked account", func(t *testing.T) {
+		userMFA, _ := newTestMFA(t, secretCipher, userId)
+		challenge := newChallenge(0)
+		mockMFARepo := new(repository.MockMFARepository)
+		mockMFARepo.On("GetChallengeByTokenHash", mock.Anything, tokenHash).Return(challenge, nil)
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(userMFA, nil)
+		mockMFARepo.On("DeleteChallenge", mock.Anything, challenge.Id).Return(true, nil)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("GetAccountInfo", mock.Anything, &api.GetAccountInfoRequest{UserId: userId.Hex()}).Return(&api.AccountInfo{UserId: userId.Hex(), AccountLockedUntil: timestamppb.New(time.Now().Add(time.Minute))}, nil)
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.VerifyChallenge(context.Background(), challengeToken, "123456")
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		mockMFARepo.AssertNotCalled(t, "UseTOTPStep", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("too many attempts", func(t *testing.T) {
+		challenge := newChallenge(5)
+		mockMFARepo := new(repository.MockMFARepository)
+		mockMFARepo.On("GetChallengeByTokenHash", mock.Anything, tokenHash).Return(challenge, nil)
+		mockMFARepo.On("DeleteChallenge", mock.Anything, challenge.Id).Return(true, nil)
+
+		s := services.NewMFAService(mockMFARepo, new(mocks.MockAccountInfoService), secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.VerifyChallenge(context.Background(), challengeToken, "123456")
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockMFARepo.AssertNotCalled(t, "GetMFA", mock.Anything, mock.Anything)
+	})
+}
+
+func TestMFAService_DisableMFA(t *testing.T) {
+	userId := primitive.NewObjectID()
+	issuer := newTestTokenIssuer(t)
+	ctx := withAccessToken(t, issuer, userId.Hex(), primitive.NewObjectID().Hex())
+	secretCipher, err := encryption.NewAESGCMCipher(make([]byte, 32))
+	assert.NoError(t, err)
+
+	t.Run("requires the password", func(t *testing.T) {
+		mockMFARepo := new(repository.MockMFARepository)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("VerifyPassword", mock.Anything, userId.Hex(), "wrong").Return(status.Errorf(codes.Unauthenticated, "Senha inválida"))
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.DisableMFA(ctx, &api.DisableMFARequest{UserId: userId.Hex(), Password: "wrong", Code: "123456"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockMFARepo.AssertNotCalled(t, "DeleteMFA", mock.Anything, mock.Anything)
+	})
+
+	t.Run("password and code disable mfa", func(t *testing.T) {
+		userMFA, secret := newTestMFA(t, secretCipher, userId)
+		mockMFARepo := new(repository.MockMFARepository)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("VerifyPassword", mock.Anything, userId.Hex(), "ValidPassword123!").Return(nil)
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(userMFA, nil)
+		mockMFARepo.On("UseTOTPStep", mock.Anything, userId.Hex(), mock.AnythingOfType("int64")).Return(true, nil)
+		mockMFARepo.On("DeleteMFA", mock.Anything, userId.Hex()).Return(nil)
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.DisableMFA(ctx, &api.DisableMFARequest{UserId: userId.Hex(), Password: "ValidPassword123!", Code: mfa.DefaultTOTP().GenerateCode(secret, time.Now())})
+
+		assert.NoError(t, err)
+		mockMFARepo.AssertExpectations(t)
+	})
+
+	t.Run("wrong code counts toward the lockout", func(t *testing.T) {
+		userMFA, secret := newTestMFA(t, secretCipher, userId)
+		mockMFARepo := new(repository.MockMFARepository)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("VerifyPassword", mock.Anything, userId.Hex(), "ValidPassword123!").Return(nil)
+		mockAccountInfoService.On("RegisterFailedMFA", mock.Anything, userId.Hex()).Return(status.Errorf(codes.ResourceExhausted, "A conta está bloqueada"))
+		mockMFARepo.On("GetMFA", mock.Anything, userId.Hex()).Return(userMFA, nil)
+
+		s := services.NewMFAService(mockMFARepo, mockAccountInfoService, secretCipher, newTestMFAPolicy(), issuer)
+		_, err := s.DisableMFA(ctx, &api.DisableMFARequest{UserId: userId.Hex(), Password: "ValidPassword123!", Code: mfa.DefaultTOTP().GenerateCode(secret, time.Now().Add(-time.Hour))})
+
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		mockAccountInfoService.AssertExpectations(t)
+		mockMFARepo.AssertNotCalled(t, "DeleteMFA", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,160 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/mailer"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/services"
+	configMocks "github.com/jonh-dev/partus_users/internal/tests/mocks/config"
+	"github.com/jonh-dev/partus_users/internal/tests/mocks/encryption"
+	repository "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	serviceMocks "github.com/jonh-dev/partus_users/internal/tests/mocks/services"
+	"github.com/jonh-dev/partus_users/internal/tests/utils"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type passwordResetTestDeps struct {
+	personalInfoRepo  *repository.MockPersonalInfoRepository
+	accountInfoRepo   *repository.MockAccountInfoRepository
+	passwordResetRepo *repository.MockPasswordResetRepository
+	passwordEncryptor *encryption.MockPasswordEncryptor
+	sessionRevoker    *serviceMocks.MockSessionService
+	txRunner          *configMocks.MockTransactionRunner
+	mailer            *mailer.MemoryMailer
+}
+
+func newTestPasswordResetService() (*services.PasswordResetService, *passwordResetTestDeps) {
+	deps := &passwordResetTestDeps{
+		personalInfoRepo:  new(repository.MockPersonalInfoRepository),
+		accountInfoRepo:   new(repository.MockAccountInfoRepository),
+		passwordResetRepo: new(repository.MockPasswordResetRepository),
+		passwordEncryptor: new(encryption.MockPasswordEncryptor),
+		sessionRevoker:    new(serviceMocks.MockSessionService),
+		txRunner:          new(configMocks.MockTransactionRunner),
+		mailer:            mailer.NewMemoryMailer(),
+	}
+	deps.txRunner.On("WithTransaction", mock.Anything).Return(nil)
+
+	policy := &config.PasswordResetPolicy{TokenTTL: time.Hour, ResetURL: "https://partus.example.com/redefinir-senha"}
+	s := services.NewPasswordResetService(deps.personalInfoRepo, deps.accountInfoRepo, deps.passwordResetRepo, deps.passwordEncryptor, utils.CreatePasswordChecker(), config.DefaultPasswordPolicy(), deps.sessionRevoker, deps.txRunner, deps.mailer, policy)
+	return s, deps
+}
+
+func TestPasswordResetService_RequestPasswordReset(t *testing.T) {
+	t.Run("unknown email", func(t *testing.T) {
+		s, deps := newTestPasswordResetService()
+		deps.personalInfoRepo.On("GetPersonalInfoByEmail", mock.Anything, "nobody@example.com").Return(nil, status.Errorf(codes.NotFound, "Informações pessoais não encontradas"))
+
+		response, err := s.RequestPasswordReset(context.Background(), &api.RequestPasswordResetRequest{Email: "nobody@example.com"})
+		s.Wait()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Se o e-mail estiver cadastrado, você receberá as instruções para redefinir a senha", response.Message)
+		assert.Empty(t, deps.mailer.Messages())
+		deps.passwordResetRepo.AssertNotCalled(t, "CreatePasswordReset", mock.Anything, mock.Anything)
+	})
+
+	t.Run("known email", func(t *testing.T) {
+		userId := primitive.NewObjectID()
+		s, deps := newTestPasswordResetService()
+		deps.personalInfoRepo.On("GetPersonalInfoByEmail", mock.Anything, "john.doe@example.com").Return(&api.PersonalInfo{UserId: userId.Hex(), FirstName: "John", Email: "john.doe@example.com"}, nil)
+		deps.passwordResetRepo.On("InvalidateUserPasswordResets", mock.Anything, userId.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+
+		var stored *model.PasswordReset
+		deps.passwordResetRepo.On("CreatePasswordReset", mock.Anything, mock.AnythingOfType("*model.PasswordReset")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*model.PasswordReset)
+		}).Return(nil)
+
+		response, err := s.RequestPasswordReset(context.Background(), &api.RequestPasswordResetRequest{Email: "john.doe@example.com"})
+		s.Wait()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Se o e-mail estiver cadastrado, você receberá as instruções para redefinir a senha", response.Message)
+
+		messages := deps.mailer.Messages()
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "john.doe@example.com", messages[0].To)
+
+		token := tokenFromMessage(t, messages[0])
+		assert.NotEmpty(t, token)
+		assert.Equal(t, userId, stored.UserId)
+		assert.Equal(t, tokens.HashPasswordResetToken(token), stored.TokenHash)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), stored.ExpiresAt, time.Second)
+		deps.passwordResetRepo.AssertExpectations(t)
+	})
+}
+
+func TestPasswordResetService_ResetPassword(t *testing.T) {
+	userId := primitive.NewObjectID()
+	token := "token-de-redefinicao"
+	newPassword := "NovaSenha@123"
+
+	t.Run("success", func(t *testing.T) {
+		s, deps := newTestPasswordResetService()
+		deps.passwordEncryptor.On("EncryptPassword", newPassword).Return("hashed-password", nil)
+		deps.passwordResetRepo.On("ConsumePasswordReset", mock.Anything, tokens.HashPasswordResetToken(token), mock.AnythingOfType("time.Time")).Return(&model.PasswordReset{UserId: userId}, nil)
+		deps.accountInfoRepo.On("GetAccountInfo", mock.Anything, userId.Hex()).Return(&api.AccountInfo{UserId: userId.Hex(), Password: "current-hash"}, nil)
+		deps.accountInfoRepo.On("GetPasswordHistory", mock.Anything, userId.Hex()).Return([]string{"old-hash"}, nil)
+		deps.passwordEncryptor.On("VerifyPassword", mock.Anything, newPassword).Return(false, nil)
+		deps.accountInfoRepo.On("ResetPassword", mock.Anything, userId.Hex(), "current-hash", "hashed-password", config.DefaultPasswordPolicy().HistorySize, mock.AnythingOfType("time.Time")).Return(true, nil)
+		deps.passwordResetRepo.On("InvalidateUserPasswordResets", mock.Anything, userId.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+		deps.sessionRevoker.On("RevokeAllUserSessions", mock.Anything, userId.Hex(), "senha redefinida").Return(2, nil)
+
+		response, err := s.ResetPassword(context.Background(), &api.ResetPasswordRequest{Token: token, NewPassword: newPassword})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Senha redefinida com sucesso", response.Message)
+		deps.txRunner.AssertExpectations(t)
+		deps.passwordResetRepo.AssertExpectations(t)
+		deps.accountInfoRepo.AssertExpectations(t)
+		deps.sessionRevoker.AssertExpectations(t)
+	})
+
+	t.Run("password from the history is refused", func(t *testing.T) {
+		s, deps := newTestPasswordResetService()
+		deps.passwordEncryptor.On("EncryptPassword", newPassword).Return("hashed-password", nil)
+		deps.passwordResetRepo.On("ConsumePasswordReset", mock.Anything, tokens.HashPasswordResetToken(token), mock.AnythingOfType("time.Time")).Return(&model.PasswordReset{UserId: userId}, nil)
+		deps.accountInfoRepo.On("GetAccountInfo", mock.Anything, userId.Hex()).Return(&api.AccountInfo{UserId: userId.Hex(), Password: "current-hash"}, nil)
+		deps.accountInfoRepo.On("GetPasswordHistory", mock.Anything, userId.Hex()).Return([]string{"old-hash"}, nil)
+		deps.passwordEncryptor.On("VerifyPassword", "current-hash", newPassword).Return(false, nil)
+		deps.passwordEncryptor.On("VerifyPassword", "old-hash", newPassword).Return(true, nil)
+
+		_, err := s.ResetPassword(context.Background(), &api.ResetPasswordRequest{Token: token, NewPassword: newPassword})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		deps.accountInfoRepo.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		deps.sessionRevoker.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("weak password keeps the token", func(t *testing.T) {
+		s, deps := newTestPasswordResetService()
+
+		_, err := s.ResetPassword(context.Background(), &api.ResetPasswordRequest{Token: token, NewPassword: "fraca"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		deps.passwordResetRepo.AssertNotCalled(t, "ConsumePasswordReset", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid or used token", func(t *testing.T) {
+		s, deps := newTestPasswordResetService()
+		deps.passwordEncryptor.On("EncryptPassword", newPassword).Return("hashed-password", nil)
+		deps.passwordResetRepo.On("ConsumePasswordReset", mock.Anything, tokens.HashPasswordResetToken(token), mock.AnythingOfType("time.Time")).Return(nil, status.Errorf(codes.NotFound, "Pedido de redefinição não encontrado"))
+
+		_, err := s.ResetPassword(context.Background(), &api.ResetPasswordRequest{Token: token, NewPassword: newPassword})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), "Token de redefinição inválido ou expirado")
+		deps.accountInfoRepo.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		deps.sessionRevoker.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -7,10 +7,12 @@ import (
 	"github.com/jonh-dev/partus_users/api"
 	"github.com/jonh-dev/partus_users/internal/services"
 	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	serviceMocks "github.com/jonh-dev/partus_users/internal/tests/mocks/services"
 	"github.com/jonh-dev/partus_users/internal/tests/utils"
 	"github.com/jonh-dev/partus_users/internal/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
@@ -142,7 +144,7 @@ func TestPersonalInfoService_CreatePersonalInfo(t *testing.T) {
 		// Adicione mais cenários de teste conforme necessário...
 	}
 
-	s := services.NewPersonalInfoService(mockPersonalInfoRepo)
+	s := services.NewPersonalInfoService(mockPersonalInfoRepo, new(serviceMocks.MockEmailVerificationService))
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
@@ -171,3 +173,154 @@ func TestPersonalInfoService_CreatePersonalInfo(t *testing.T) {
 	}
 
 }
+
+func TestPersonalInfoService_CreatePersonalInfo_AllViolations(t *testing.T) {
+	mockPersonalInfoRepo := new(mocks.MockPersonalInfoRepository)
+	personalInfo := utils.CreateValidPersonalInfo()
+	personalInfo.FirstName = "john"
+	personalInfo.Email = "john.doe"
+	personalInfo.Phone = "11987a54321"
+	personalInfo.TimeZone = "America/Atlantida"
+
+	s := services.NewPersonalInfoService(mockPersonalInfoRepo, new(serviceMocks.MockEmailVerificationService))
+	_, err := s.CreatePersonalInfo(context.Background(), personalInfo)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), validation.ErrInvalidPhone.Error())
+
+	var fields []string
+	reasons := map[string]string{}
+	for _, detail := range status.Convert(err).Details() {
+		switch detail := detail.(type) {
+		case *errdetails.BadRequest:
+			for _, violation := range detail.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+		case *errdetails.ErrorInfo:
+			reasons[detail.Metadata["field"]] = detail.Reason
+		}
+	}
+	assert.Equal(t, []string{"firstName", "email", "phone", "timeZone"}, fields)
+	assert.Equal(t, map[string]string{
+		"firstName": validation.ReasonInvalidFirstName,
+		"email":     validation.ReasonInvalidEmail,
+		"phone":     validation.ReasonInvalidPhone,
+		"timeZone":  validation.ReasonInvalidTimeZone,
+	}, reasons)
+	mockPersonalInfoRepo.AssertNotCalled(t, "DoesEmailExist", mock.Anything, mock.Anything)
+}
+
+func TestPersonalInfoService_CreatePersonalInfo_DuplicateKey(t *testing.T) {
+	mockPersonalInfoRepo := new(mocks.MockPersonalInfoRepository)
+	personalInfo := utils.CreateValidPersonalInfo()
+
+	// Outra requisição gravou o mesmo e-mail entre a verificação e a inserção.
+	mockPersonalInfoRepo.On("DoesEmailExist", mock.Anything, personalInfo.Email).Return(false, nil)
+	mockPersonalInfoRepo.On("CreatePersonalInfo", mock.Anything, personalInfo).Return(nil, status.Errorf(codes.AlreadyExists, "O e-mail já existe"))
+
+	s := services.NewPersonalInfoService(mockPersonalInfoRepo, new(serviceMocks.MockEmailVerificationService))
+	_, err := s.CreatePersonalInfo(context.Background(), personalInfo)
+
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	mockPersonalInfoRepo.AssertExpectations(t)
+}
+
+func TestPersonalInfoService_PatchPersonalInfo(t *testing.T) {
+	t.Run("only masked fields are validated", func(t *testing.T) {
+		mockPersonalInfoRepo := new(mocks.MockPersonalInfoRepository)
+		s := services.NewPersonalInfoService(mockPersonalInfoRepo, new(serviceMocks.MockEmailVerificationService))
+
+		personalInfo := utils.CreateFirstNameWithLowerCasePersonalInfo()
+		personalInfo.Phone = "11987654321"
+		fields := []string{"phone"}
+
+		mockPersonalInfoRepo.On("UpdatePersonalInfo", mock.Anything, personalInfo, fields).Return(personalInfo, nil)
+
+		_, err := s.PatchPersonalInfo(context.Background(), personalInfo, fields)
+
+		assert.NoError(t, err)
+		mockPersonalInfoRepo.AssertExpectations(t)
+	})
+
+	t.Run("time zone", func(t *testing.T) {
+		mockPersonalInfoRepo := new(mocks.MockPersonalInfoRepository)
+		s := services.NewPersonalInfoService(mockPersonalInfoRepo, new(serviceMocks.MockEmailVerificationService))
+
+		personalInfo := utils.CreateValidPersonalInfo()
+		personalInfo.TimeZone = "Europe/Lisbon"
+		fields := []string{"timeZone"}
+		mockPersonalInfoRepo.On("UpdatePersonalInfo", mock.Anything, personalInfo, fields).Return(personalInfo, nil)
+
+		_, err := s.PatchPersonalInfo(context.Background(), personalInfo, fields)
+		assert.NoError(t, err)
+
+		for _, timeZone := range []string{"Local", "GMT-3", "Europe/Atlantida"} {
+			personalInfo.TimeZone = timeZone
+			_, err = s.PatchPersonalInfo(context.Background(), personalInfo, fields)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err), timeZone)
+		}
+		mockPersonalInfoRepo.AssertNumberOfCalls(t, "UpdatePersonalInfo", 1)
+	})
+
+	t.Run("invalid masked field", func(t *testing.T) {
+		mockPersonalInfoRepo := new(mocks.MockPersonalInfoRepository)
+		s := services.NewPersonalInfoService(mockPersonalInfoRepo, new(serviceMocks.MockEmailVerificationService))
+
+		_, err := s.PatchPersonalInfo(context.Background(), utils.CreatePhoneWithInvalidCharactersPersonalInfo(), []string{"phone"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), validation.ErrInvalidPhone.Error())
+		mockPersonalInfoRepo.AssertNotCalled(t, "UpdatePersonalInfo", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("email already taken", func(t *testing.T) {
+		mockPersonalInfoRepo := new(mocks.MockPersonalInfoRepository)
+		s := services.NewPersonalInfoService(mockPersonalInfoRepo, new(serviceMocks.MockEmailVerificationService))
+
+		personalInfo := utils.CreateExistingEmailPersonalInfo()
+		mockPersonalInfoRepo.On("GetPersonalInfo", mock.Anything, personalInfo.UserId).Return(utils.CreateValidPersonalInfo(), nil)
+		mockPersonalInfoRepo.On("DoesEmailExist", mock.Anything, personalInfo.Email).Return(true, nil)
+
+		_, err := s.PatchPersonalInfo(context.Background(), personalInfo, []string{"email"})
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		mockPersonalInfoRepo.AssertExpectations(t)
+	})
+
+	t.Run("new email receives the verification email", func(t *testing.T) {
+		mockPersonalInfoRepo := new(mocks.MockPersonalInfoRepository)
+		mockEmailVerification := new(serviceMocks.MockEmailVerificationService)
+		s := services.NewPersonalInfoService(mockPersonalInfoRepo, mockEmailVerification)
+
+		personalInfo := utils.CreateValidPersonalInfo()
+		personalInfo.Email = "new.email@example.com"
+		fields := []string{"email"}
+		mockPersonalInfoRepo.On("GetPersonalInfo", mock.Anything, personalInfo.UserId).Return(utils.CreateValidPersonalInfo(), nil)
+		mockPersonalInfoRepo.On("DoesEmailExist", mock.Anything, personalInfo.Email).Return(false, nil)
+		mockPersonalInfoRepo.On("UpdatePersonalInfo", mock.Anything, personalInfo, fields).Return(personalInfo, nil)
+		mockEmailVerification.On("SendVerificationEmail", mock.Anything, personalInfo.UserId, personalInfo.Email, personalInfo.FirstName, personalInfo.TimeZone).Return(nil)
+
+		_, err := s.PatchPersonalInfo(context.Background(), personalInfo, fields)
+
+		assert.NoError(t, err)
+		mockPersonalInfoRepo.AssertExpectations(t)
+		mockEmailVerification.AssertExpectations(t)
+	})
+
+	t.Run("unchanged email keeps the verification", func(t *testing.T) {
+		mockPersonalInfoRepo := new(mocks.MockPersonalInfoRepository)
+		mockEmailVerification := new(serviceMocks.MockEmailVerificationService)
+		s := services.NewPersonalInfoService(mockPersonalInfoRepo, mockEmailVerification)
+
+		personalInfo := utils.CreateValidPersonalInfo()
+		personalInfo.Phone = "11987654321"
+		mockPersonalInfoRepo.On("GetPersonalInfo", mock.Anything, personalInfo.UserId).Return(utils.CreateValidPersonalInfo(), nil)
+		mockPersonalInfoRepo.On("UpdatePersonalInfo", mock.Anything, personalInfo, []string{"phone"}).Return(personalInfo, nil)
+
+		_, err := s.PatchPersonalInfo(context.Background(), personalInfo, []string{"email", "phone"})
+
+		assert.NoError(t, err)
+		mockPersonalInfoRepo.AssertExpectations(t)
+		mockEmailVerification.AssertNotCalled(t, "SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,320 @@
+package services_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/services"
+	repository "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
+	"github.com/jonh-dev/partus_users/internal/tests/utils"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func newTestTokenIssuer(t *testing.T) *tokens.JWTIssuer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	issuer, err := tokens.NewJWTIssuer(key, "partus_users", 15*time.Minute)
+	assert.NoError(t, err)
+	return issuer
+}
+
+func newTestTokenPolicy() *config.TokenPolicy {
+	return &config.TokenPolicy{Issuer: "partus_users", AccessTokenTTL: 15 * time.Minute, RefreshTokenTTL: 24 * time.Hour}
+}
+
+func TestSessionService_CreateSession(t *testing.T) {
+	userId := primitive.NewObjectID().Hex()
+	issuer := newTestTokenIssuer(t)
+
+	mockSessionRepo := new(repository.MockSessionRepository)
+	stored := &model.Session{Id: primitive.NewObjectID(), ExpiresAt: time.Now().Add(24 * time.Hour)}
+	mockSessionRepo.On("CreateSession", mock.Anything, mock.AnythingOfType("*model.Session")).Run(func(args mock.Arguments) {
+		stored.TokenHash = args.Get(1).(*model.Session).TokenHash
+	}).Return(stored, nil)
+
+	mockUserRepo := new(repository.MockUserRepository)
+	mockUserRepo.On("GetUser", mock.Anything, userId).Return(&model.User{Roles: []string{model.RoleSupport}}, nil)
+
+	s := services.NewSessionService(mockSessionRepo, mockUserRepo, issuer, newTestTokenPolicy())
+	response, err := s.CreateSession(context.Background(), userId)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Bearer", response.TokenType)
+	assert.Equal(t, tokens.HashRefreshToken(response.RefreshToken), stored.TokenHash)
+	assert.NotEqual(t, response.RefreshToken, stored.TokenHash)
+
+	claims, err := issuer.VerifyAccessToken(response.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, userId, claims.Subject)
+	assert.Equal(t, stored.Id.Hex(), claims.SessionId)
+	assert.Equal(t, []string{model.RoleSupport}, claims.Roles)
+}
+
+func TestSessionService_RefreshToken(t *testing.T) {
+	validUser := utils.CreateValidUser()
+	refreshToken := "refresh-token"
+	tokenHash := tokens.HashRefreshToken(refreshToken)
+
+	newSession := func() *model.Session {
+		return &model.Session{
+			Id:        primitive.NewObjectID(),
+			UserId:    validUser.Id,
+			TokenHash: tokenHash,
+			CreatedAt: time.Now().Add(-time.Hour),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("rotates the refresh token", func(t *testing.T) {
+		session := newSession()
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockUserRepo := new(repository.MockUserRepository)
+		mockSessionRepo.On("GetSessionByTokenHash", mock.Anything, tokenHash).Return(session, nil)
+		mockUserRepo.On("GetUser", mock.Anything, validUser.Id.Hex()).Return(validUser, nil)
+		mockSessionRepo.On("RotateSessionToken", mock.Anything, session.Id.Hex(), tokenHash, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("time.Time")).Return(true, nil)
+
+		s := services.NewSessionService(mockSessionRepo, mockUserRepo, newTestTokenIssuer(t), newTestTokenPolicy())
+		response, err := s.RefreshToken(context.Background(), &api.RefreshTokenRequest{RefreshToken: refreshToken})
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, refreshToken, response.RefreshToken)
+		mockSessionRepo.AssertCalled(t, "RotateSessionToken", mock.Anything, session.Id.Hex(), tokenHash, tokens.HashRefreshToken(response.RefreshToken), mock.Anything, mock.AnythingOfType("time.Time"))
+	})
+
+	t.Run("reused token revokes the session", func(t *testing.T) {
+		session := newSession()
+		session.TokenHash = tokens.HashRefreshToken("rotated-token")
+		session.UsedTokenHashes = []string{tokenHash}
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("GetSessionByTokenHash", mock.Anything, tokenHash).Return(session, nil)
+		mockSessionRepo.On("RevokeSession", mock.Anything, session.Id.Hex(), "refresh token reutilizado", mock.AnythingOfType("time.Time")).Return(nil)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), newTestTokenIssuer(t), newTestTokenPolicy())
+		_, err := s.RefreshToken(context.Background(), &api.RefreshTokenRequest{RefreshToken: refreshToken})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockSessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("concurrent rotation revokes the session", func(t *testing.T) {
+		session := newSession()
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockUserRepo := new(repository.MockUserRepository)
+		mockSessionRepo.On("GetSessionByTokenHash", mock.Anything, tokenHash).Return(session, nil)
+		mockUserRepo.On("GetUser", mock.Anything, validUser.Id.Hex()).Return(validUser, nil)
+		mockSessionRepo.On("RotateSessionToken", mock.Anything, session.Id.Hex(), tokenHash, mock.AnythingOfType("string"), mock.Anything, mock.AnythingOfType("time.Time")).Return(false, nil)
+		mockSessionRepo.On("RevokeSession", mock.Anything, session.Id.Hex(), "refresh token reutilizado", mock.AnythingOfType("time.Time")).Return(nil)
+
+		s := services.NewSessionService(mockSessionRepo, mockUserRepo, newTestTokenIssuer(t), newTestTokenPolicy())
+		_, err := s.RefreshToken(context.Background(), &api.RefreshTokenRequest{RefreshToken: refreshToken})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockSessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("expired or revoked session", func(t *testing.T) {
+		expired := newSession()
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		revoked := newSession()
+		revoked.RevokedAt = time.Now().Add(-time.Minute)
+
+		for _, session := range []*model.Session{expired, revoked} {
+			mockSessionRepo := new(repository.MockSessionRepository)
+			mockSessionRepo.On("GetSessionByTokenHash", mock.Anything, tokenHash).Return(session, nil)
+
+			s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), newTestTokenIssuer(t), newTestTokenPolicy())
+			_, err := s.RefreshToken(context.Background(), &api.RefreshTokenRequest{RefreshToken: refreshToken})
+
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+			mockSessionRepo.AssertNotCalled(t, "RotateSessionToken", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		}
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("GetSessionByTokenHash", mock.Anything, tokenHash).Return(nil, status.Errorf(codes.NotFound, "Sessão não encontrada"))
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), newTestTokenIssuer(t), newTestTokenPolicy())
+		_, err := s.RefreshToken(context.Background(), &api.RefreshTokenRequest{RefreshToken: refreshToken})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func withAccessToken(t *testing.T, issuer *tokens.JWTIssuer, userId string, sessionId string, roles ...string) context.Context {
+	accessToken, _, err := issuer.IssueAccessToken(userId, sessionId, roles, time.Now())
+	assert.NoError(t, err)
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer "+accessToken))
+}
+
+func TestSessionService_ListSessions(t *testing.T) {
+	userId := primitive.NewObjectID()
+	issuer := newTestTokenIssuer(t)
+	current := &model.Session{Id: primitive.NewObjectID(), UserId: userId, Client: model.SessionClient{Device: "iPhone", IpAddress: "10.0.0.1"}}
+	other := &model.Session{Id: primitive.NewObjectID(), UserId: userId, Client: model.SessionClient{Device: "Chrome"}}
+
+	t.Run("lists the sessions of the caller", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("ListActiveSessions", mock.Anything, userId.Hex(), mock.AnythingOfType("time.Time")).Return([]*model.Session{current, other}, nil)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		response, err := s.ListSessions(withAccessToken(t, issuer, userId.Hex(), current.Id.Hex()), &api.ListSessionsRequest{UserId: userId.Hex()})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Sessions, 2)
+		assert.True(t, response.Sessions[0].Current)
+		assert.Equal(t, "iPhone", response.Sessions[0].Device)
+		assert.Equal(t, "10.0.0.1", response.Sessions[0].IpAddress)
+		assert.False(t, response.Sessions[1].Current)
+	})
+
+	t.Run("without access token", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		_, err := s.ListSessions(context.Background(), &api.ListSessionsRequest{UserId: userId.Hex()})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockSessionRepo.AssertNotCalled(t, "ListActiveSessions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("sessions of another user", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		_, err := s.ListSessions(withAccessToken(t, issuer, primitive.NewObjectID().Hex(), current.Id.Hex()), &api.ListSessionsRequest{UserId: userId.Hex()})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockSessionRepo.AssertNotCalled(t, "ListActiveSessions", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("support staff lists the sessions of another user", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("ListActiveSessions", mock.Anything, userId.Hex(), mock.AnythingOfType("time.Time")).Return([]*model.Session{current, other}, nil)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		response, err := s.ListSessions(withAccessToken(t, issuer, primitive.NewObjectID().Hex(), current.Id.Hex(), model.RoleSupport), &api.ListSessionsRequest{UserId: userId.Hex()})
+
+		assert.NoError(t, err)
+		assert.Len(t, response.Sessions, 2)
+		assert.False(t, response.Sessions[0].Current)
+	})
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	userId := primitive.NewObjectID()
+	issuer := newTestTokenIssuer(t)
+	session := &model.Session{Id: primitive.NewObjectID(), UserId: userId}
+	ctx := withAccessToken(t, issuer, userId.Hex(), primitive.NewObjectID().Hex())
+
+	t.Run("revokes a session of the user", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("GetSession", mock.Anything, session.Id.Hex()).Return(session, nil)
+		mockSessionRepo.On("RevokeSession", mock.Anything, session.Id.Hex(), "revogada pelo usuário", mock.AnythingOfType("time.Time")).Return(nil)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		response, err := s.RevokeSession(ctx, &api.RevokeSessionRequest{UserId: userId.Hex(), SessionId: session.Id.Hex()})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Sessão revogada com sucesso", response.Message)
+		mockSessionRepo.AssertExpectations(t)
+	})
+
+	t.Run("session of another user is not found", func(t *testing.T) {
+		otherUserId := primitive.NewObjectID().Hex()
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("GetSession", mock.Anything, session.Id.Hex()).Return(session, nil)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		_, err := s.RevokeSession(withAccessToken(t, issuer, otherUserId, primitive.NewObjectID().Hex()), &api.RevokeSessionRequest{UserId: otherUserId, SessionId: session.Id.Hex()})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		mockSessionRepo.AssertNotCalled(t, "RevokeSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("token of another user", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		_, err := s.RevokeSession(withAccessToken(t, issuer, primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()), &api.RevokeSessionRequest{UserId: userId.Hex(), SessionId: session.Id.Hex()})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockSessionRepo.AssertNotCalled(t, "GetSession", mock.Anything, mock.Anything)
+	})
+
+	t.Run("admin revokes a session of another user", func(t *testing.T) {
+		adminId := primitive.NewObjectID().Hex()
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("GetSession", mock.Anything, session.Id.Hex()).Return(session, nil)
+		mockSessionRepo.On("RevokeSession", mock.Anything, session.Id.Hex(), "revogada pela equipe ("+adminId+")", mock.AnythingOfType("time.Time")).Return(nil)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		_, err := s.RevokeSession(withAccessToken(t, issuer, adminId, primitive.NewObjectID().Hex(), model.RoleAdmin), &api.RevokeSessionRequest{UserId: userId.Hex(), SessionId: session.Id.Hex()})
+
+		assert.NoError(t, err)
+		mockSessionRepo.AssertExpectations(t)
+	})
+}
+
+func TestSessionService_RevokeAllSessions(t *testing.T) {
+	userId := primitive.NewObjectID().Hex()
+	currentSessionId := primitive.NewObjectID().Hex()
+	issuer := newTestTokenIssuer(t)
+
+	ctx := withAccessToken(t, issuer, userId, currentSessionId)
+
+	t.Run("keeps the current session", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("RevokeUserSessions", mock.Anything, userId, currentSessionId, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(int64(2), nil)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		response, err := s.RevokeAllSessions(ctx, &api.RevokeAllSessionsRequest{UserId: userId, KeepCurrent: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), response.RevokedCount)
+	})
+
+	t.Run("revokes every session", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("RevokeUserSessions", mock.Anything, userId, "", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		response, err := s.RevokeAllSessions(ctx, &api.RevokeAllSessionsRequest{UserId: userId})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), response.RevokedCount)
+	})
+
+	t.Run("token of another user", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		_, err := s.RevokeAllSessions(withAccessToken(t, issuer, primitive.NewObjectID().Hex(), currentSessionId), &api.RevokeAllSessionsRequest{UserId: userId})
+
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		mockSessionRepo.AssertNotCalled(t, "RevokeUserSessions", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("support staff revokes every session of another user", func(t *testing.T) {
+		mockSessionRepo := new(repository.MockSessionRepository)
+		mockSessionRepo.On("RevokeUserSessions", mock.Anything, userId, "", mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+
+		s := services.NewSessionService(mockSessionRepo, new(repository.MockUserRepository), issuer, newTestTokenPolicy())
+		response, err := s.RevokeAllSessions(withAccessToken(t, issuer, primitive.NewObjectID().Hex(), currentSessionId, model.RoleSupport), &api.RevokeAllSessionsRequest{UserId: userId, KeepCurrent: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int32(3), response.RevokedCount)
+	})
+}
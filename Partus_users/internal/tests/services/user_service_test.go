@@ -2,15 +2,27 @@ package services_test
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/jonh-dev/partus_users/api"
+	"github.com/jonh-dev/partus_users/internal/model"
+	"github.com/jonh-dev/partus_users/internal/repositories"
 	"github.com/jonh-dev/partus_users/internal/services"
+	configMocks "github.com/jonh-dev/partus_users/internal/tests/mocks/config"
 	repository "github.com/jonh-dev/partus_users/internal/tests/mocks/repositories"
 	mocks "github.com/jonh-dev/partus_users/internal/tests/mocks/services"
 	"github.com/jonh-dev/partus_users/internal/tests/utils"
+	"github.com/jonh-dev/partus_users/internal/validation"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestUserService_CreateUser(t *testing.T) {
@@ -27,16 +39,473 @@ func TestUserService_CreateUser(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		mockUserRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*model.User")).Return(validUser, nil)
 		mockPersonalInfoService.On("CreatePersonalInfo", mock.Anything, mock.AnythingOfType("*api.PersonalInfo")).Return(validUser.PersonalInfo.ToProto(), nil)
-		mockAccountInfoService.On("CreateAccountInfo", mock.Anything, mock.AnythingOfType("*api.AccountInfo")).Return(validUser.AccountInfo.ToProto(), nil)
+		var createdAccountInfo *api.AccountInfo
+		mockAccountInfoService.On("CreateAccountInfo", mock.Anything, mock.AnythingOfType("*api.AccountInfo")).Run(func(args mock.Arguments) {
+			createdAccountInfo = args.Get(1).(*api.AccountInfo)
+		}).Return(validUser.AccountInfo.ToProto(), nil)
+
+		mockTxRunner := new(configMocks.MockTransactionRunner)
+		mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+		mockEmailVerification := new(mocks.MockEmailVerificationService)
+		mockEmailVerification.On("SendVerificationEmail", mock.Anything, validUser.Id.Hex(), validUser.PersonalInfo.Email, validUser.PersonalInfo.FirstName, validUser.PersonalInfo.TimeZone).Return(nil)
 
-		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService)
+		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, mockTxRunner, new(mocks.MockSessionService), new(mocks.MockMFAService), mockEmailVerification, new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
 		user, err := u.CreateUser(context.Background(), validCreateUserRequest)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, user)
+		assert.NotEmpty(t, createdAccountInfo.Password)
+		assert.Empty(t, user.User.AccountInfo.Password)
+		assert.Equal(t, api.AccountStatus_PENDING, createdAccountInfo.AccountStatus)
+		assert.NotEmpty(t, createdAccountInfo.StatusReason)
 
+		mockTxRunner.AssertExpectations(t)
 		mockUserRepo.AssertExpectations(t)
 		mockPersonalInfoService.AssertExpectations(t)
 		mockAccountInfoService.AssertExpectations(t)
+		mockEmailVerification.AssertExpectations(t)
+	})
+
+	t.Run("mail failure does not undo the account", func(t *testing.T) {
+		mockTxRunner := new(configMocks.MockTransactionRunner)
+		mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+		mockEmailVerification := new(mocks.MockEmailVerificationService)
+		mockEmailVerification.On("SendVerificationEmail", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(status.Errorf(codes.Unavailable, "SMTP indisponível"))
+
+		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, mockTxRunner, new(mocks.MockSessionService), new(mocks.MockMFAService), mockEmailVerification, new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		user, err := u.CreateUser(context.Background(), validCreateUserRequest)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, user)
+	})
+
+	t.Run("duplicated email aborts the transaction", func(t *testing.T) {
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockUserRepo := new(repository.MockUserRepository)
+		mockTxRunner := new(configMocks.MockTransactionRunner)
+		mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+		mockPersonalInfoService.On("CreatePersonalInfo", mock.Anything, mock.AnythingOfType("*api.PersonalInfo")).Return(nil, status.Errorf(codes.AlreadyExists, "Email já cadastrado"))
+
+		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, mockTxRunner, new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		_, err := u.CreateUser(context.Background(), validCreateUserRequest)
+
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+		mockAccountInfoService.AssertNotCalled(t, "CreateAccountInfo", mock.Anything, mock.Anything)
+		mockUserRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("validation violations point to the request fields", func(t *testing.T) {
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockTxRunner := new(configMocks.MockTransactionRunner)
+		mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+		mockPersonalInfoService.On("CreatePersonalInfo", mock.Anything, mock.AnythingOfType("*api.PersonalInfo")).Return(validUser.PersonalInfo.ToProto(), nil)
+		violation := validation.NewFieldError("username", validation.ReasonInvalidUsername, validation.ErrInvalidUsername)
+		mockAccountInfoService.On("CreateAccountInfo", mock.Anything, mock.AnythingOfType("*api.AccountInfo")).Return(nil, validation.ToStatus("Erro ao validar AccountInfo", violation))
+
+		u := services.NewUserService(new(repository.MockUserRepository), mockPersonalInfoService, mockAccountInfoService, mockTxRunner, new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		_, err := u.CreateUser(context.Background(), validCreateUserRequest)
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), validation.ErrInvalidUsername.Error())
+		details := status.Convert(err).Details()
+		assert.Len(t, details, 2)
+		badRequest, ok := details[0].(*errdetails.BadRequest)
+		assert.True(t, ok)
+		assert.Equal(t, "user.account_info.username", badRequest.FieldViolations[0].Field)
+		errorInfo, ok := details[1].(*errdetails.ErrorInfo)
+		assert.True(t, ok)
+		assert.Equal(t, validation.ReasonInvalidUsername, errorInfo.Reason)
+		assert.Equal(t, "user.account_info.username", errorInfo.Metadata["field"])
+	})
+
+	t.Run("failure on users insert is returned to the transaction", func(t *testing.T) {
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockUserRepo := new(repository.MockUserRepository)
+		mockTxRunner := new(configMocks.MockTransactionRunner)
+		mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+		mockPersonalInfoService.On("CreatePersonalInfo", mock.Anything, mock.AnythingOfType("*api.PersonalInfo")).Return(validUser.PersonalInfo.ToProto(), nil)
+		mockAccountInfoService.On("CreateAccountInfo", mock.Anything, mock.AnythingOfType("*api.AccountInfo")).Return(validUser.AccountInfo.ToProto(), nil)
+		mockUserRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*model.User")).Return(nil, fmt.Errorf("falha ao inserir usuário no banco de dados"))
+
+		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, mockTxRunner, new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		_, err := u.CreateUser(context.Background(), validCreateUserRequest)
+
+		assert.Equal(t, codes.Internal, status.Code(err))
+		mockTxRunner.AssertExpectations(t)
+	})
+}
+
+func TestUserService_Login(t *testing.T) {
+	mockUserRepo := new(repository.MockUserRepository)
+	mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+	mockAccountInfoService := new(mocks.MockAccountInfoService)
+
+	validUser := utils.CreateValidUser()
+	userId := validUser.Id.Hex()
+	accountInfo := validUser.AccountInfo.ToProto()
+	accountInfo.UserId = userId
+
+	t.Run("success", func(t *testing.T) {
+		mockAccountInfoService.On("Authenticate", mock.Anything, "johndoe", "ValidPassword123!").Return(accountInfo, nil)
+		mockUserRepo.On("GetUser", mock.Anything, userId).Return(validUser, nil)
+		mockPersonalInfoService.On("GetPersonalInfo", mock.Anything, &api.GetPersonalInfoRequest{UserId: userId}).Return(validUser.PersonalInfo.ToProto(), nil)
+		mockAccountInfoService.On("GetAccountInfo", mock.Anything, &api.GetAccountInfoRequest{UserId: userId}).Return(accountInfo, nil)
+		mockAccountInfoService.On("RegisterSuccessfulLogin", mock.Anything, userId).Return(nil)
+		mockSessionService := new(mocks.MockSessionService)
+		mockSessionService.On("CreateSession", mock.Anything, userId).Return(&api.TokenResponse{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer"}, nil)
+		mockMFAService := new(mocks.MockMFAService)
+		mockMFAService.On("IsMFAEnabled", mock.Anything, userId).Return(false, nil)
+
+		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, new(configMocks.MockTransactionRunner), mockSessionService, mockMFAService, new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		response, err := u.Login(context.Background(), &api.LoginRequest{Username: "johndoe", Password: "ValidPassword123!"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId, response.User.Id)
+		assert.Equal(t, "refresh", response.Tokens.RefreshToken)
+		assert.False(t, response.MfaRequired)
+		mockSessionService.AssertExpectations(t)
+
+		mockUserRepo.AssertExpectations(t)
+		mockPersonalInfoService.AssertExpectations(t)
+		mockAccountInfoService.AssertExpectations(t)
+	})
+
+	t.Run("mfa required", func(t *testing.T) {
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockAccountInfoService.On("Authenticate", mock.Anything, "johndoe", "ValidPassword123!").Return(accountInfo, nil)
+		mockSessionService := new(mocks.MockSessionService)
+		mockMFAService := new(mocks.MockMFAService)
+		mockMFAService.On("IsMFAEnabled", mock.Anything, userId).Return(true, nil)
+		mockMFAService.On("CreateChallenge", mock.Anything, userId).Return("challenge", time.Now().Add(5*time.Minute), nil)
+
+		u := services.NewUserService(new(repository.MockUserRepository), new(mocks.MockPersonalInfoService), mockAccountInfoService, new(configMocks.MockTransactionRunner), mockSessionService, mockMFAService, new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		response, err := u.Login(context.Background(), &api.LoginRequest{Username: "johndoe", Password: "ValidPassword123!"})
+
+		assert.NoError(t, err)
+		assert.True(t, response.MfaRequired)
+		assert.Equal(t, "challenge", response.MfaChallengeToken)
+		assert.Nil(t, response.Tokens)
+		assert.Nil(t, response.User)
+		mockSessionService.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+		mockAccountInfoService.AssertNotCalled(t, "RegisterSuccessfulLogin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("missing credentials", func(t *testing.T) {
+		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		_, err := u.Login(context.Background(), &api.LoginRequest{Username: "johndoe"})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestUserService_VerifyLoginMFA(t *testing.T) {
+	validUser := utils.CreateValidUser()
+	userId := validUser.Id.Hex()
+	accountInfo := validUser.AccountInfo.ToProto()
+	accountInfo.UserId = userId
+
+	t.Run("valid code issues tokens", func(t *testing.T) {
+		mockUserRepo := new(repository.MockUserRepository)
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockSessionService := new(mocks.MockSessionService)
+		mockMFAService := new(mocks.MockMFAService)
+		mockMFAService.On("VerifyChallenge", mock.Anything, "challenge", "123456").Return(userId, nil)
+		mockUserRepo.On("GetUser", mock.Anything, userId).Return(validUser, nil)
+		mockPersonalInfoService.On("GetPersonalInfo", mock.Anything, &api.GetPersonalInfoRequest{UserId: userId}).Return(validUser.PersonalInfo.ToProto(), nil)
+		mockAccountInfoService.On("GetAccountInfo", mock.Anything, &api.GetAccountInfoRequest{UserId: userId}).Return(accountInfo, nil)
+		mockAccountInfoService.On("RegisterSuccessfulLogin", mock.Anything, userId).Return(nil)
+		mockSessionService.On("CreateSession", mock.Anything, userId).Return(&api.TokenResponse{AccessToken: "access", RefreshToken: "refresh", TokenType: "Bearer"}, nil)
+
+		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, new(configMocks.MockTransactionRunner), mockSessionService, mockMFAService, new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		response, err := u.VerifyLoginMFA(context.Background(), &api.VerifyLoginMFARequest{MfaChallengeToken: "challenge", Code: "123456"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId, response.User.Id)
+		assert.Equal(t, "access", response.Tokens.AccessToken)
+		mockAccountInfoService.AssertExpectations(t)
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		mockSessionService := new(mocks.MockSessionService)
+		mockMFAService := new(mocks.MockMFAService)
+		mockMFAService.On("VerifyChallenge", mock.Anything, "challenge", "000000").Return("", status.Errorf(codes.Unauthenticated, "Código inválido"))
+
+		u := services.NewUserService(new(repository.MockUserRepository), new(mocks.MockPersonalInfoService), new(mocks.MockAccountInfoService), new(configMocks.MockTransactionRunner), mockSessionService, mockMFAService, new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		_, err := u.VerifyLoginMFA(context.Background(), &api.VerifyLoginMFARequest{MfaChallengeToken: "challenge", Code: "000000"})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		mockSessionService.AssertNotCalled(t, "CreateSession", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_GetUserByEmail(t *testing.T) {
+	validUser := utils.CreateValidUser()
+	userId := validUser.Id.Hex()
+	personalInfo := validUser.PersonalInfo.ToProto()
+	personalInfo.UserId = userId
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(repository.MockUserRepository)
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+		mockPersonalInfoService.On("GetPersonalInfoByEmail", mock.Anything, "John.Doe@Example.com").Return(personalInfo, nil)
+		mockUserRepo.On("GetUser", mock.Anything, userId).Return(validUser, nil)
+		mockPersonalInfoService.On("GetPersonalInfo", mock.Anything, &api.GetPersonalInfoRequest{UserId: userId}).Return(personalInfo, nil)
+		mockAccountInfoService.On("GetAccountInfo", mock.Anything, &api.GetAccountInfoRequest{UserId: userId}).Return(validUser.AccountInfo.ToProto(), nil)
+
+		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		response, err := u.GetUserByEmail(context.Background(), &api.GetUserByEmailRequest{Email: "John.Doe@Example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId, response.User.Id)
+		assert.Empty(t, response.User.AccountInfo.Password)
+		mockPersonalInfoService.AssertExpectations(t)
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockPersonalInfoService.On("GetPersonalInfoByEmail", mock.Anything, "nobody@example.com").Return(nil, status.Errorf(codes.NotFound, "PersonalInfo não encontrado"))
+
+		u := services.NewUserService(new(repository.MockUserRepository), mockPersonalInfoService, new(mocks.MockAccountInfoService), new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		_, err := u.GetUserByEmail(context.Background(), &api.GetUserByEmailRequest{Email: "nobody@example.com"})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, "PersonalInfo não encontrado", status.Convert(err).Message())
+	})
+}
+
+func TestUserService_GetUserByUsername(t *testing.T) {
+	validUser := utils.CreateValidUser()
+	userId := validUser.Id.Hex()
+	accountInfo := validUser.AccountInfo.ToProto()
+	accountInfo.UserId = userId
+
+	mockUserRepo := new(repository.MockUserRepository)
+	mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+	mockAccountInfoService := new(mocks.MockAccountInfoService)
+	mockAccountInfoService.On("GetAccountInfoByUsername", mock.Anything, "JohnDoe").Return(accountInfo, nil)
+	mockUserRepo.On("GetUser", mock.Anything, userId).Return(validUser, nil)
+	mockPersonalInfoService.On("GetPersonalInfo", mock.Anything, &api.GetPersonalInfoRequest{UserId: userId}).Return(validUser.PersonalInfo.ToProto(), nil)
+	mockAccountInfoService.On("GetAccountInfo", mock.Anything, &api.GetAccountInfoRequest{UserId: userId}).Return(accountInfo, nil)
+
+	u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+	response, err := u.GetUserByUsername(context.Background(), &api.GetUserByUsernameRequest{Username: "JohnDoe"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, userId, response.User.Id)
+
+	_, err = u.GetUserByUsername(context.Background(), &api.GetUserByUsernameRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestUserService_UpdateUser(t *testing.T) {
+	validUser := utils.CreateValidUser()
+	userId := validUser.Id.Hex()
+
+	t.Run("success", func(t *testing.T) {
+		mockUserRepo := new(repository.MockUserRepository)
+		mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+		mockAccountInfoService := new(mocks.MockAccountInfoService)
+
+		personalInfo := &api.PersonalInfo{Email: "new.email@example.com"}
+		mockUserRepo.On("GetUser", mock.Anything, userId).Return(validUser, nil)
+		mockPersonalInfoService.On("PatchPersonalInfo", mock.Anything, personalInfo, []string{"email"}).Return(personalInfo, nil)
+		mockPersonalInfoService.On("GetPersonalInfo", mock.Anything, &api.GetPersonalInfoRequest{UserId: userId}).Return(validUser.PersonalInfo.ToProto(), nil)
+		mockAccountInfoService.On("GetAccountInfo", mock.Anything, &api.GetAccountInfoRequest{UserId: userId}).Return(validUser.AccountInfo.ToProto(), nil)
+
+		u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		response, err := u.UpdateUser(context.Background(), &api.UpdateUserRequest{
+			User:       &api.User{Id: userId, PersonalInfo: personalInfo},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"personal_info.email"}},
+		})
+
+		assert.NoError(t, err)
+		assert.NotNil(t, response.User)
+		assert.Equal(t, userId, personalInfo.UserId)
+		mockPersonalInfoService.AssertExpectations(t)
+	})
+
+	t.Run("account info paths are rejected", func(t *testing.T) {
+		u := services.NewUserService(new(repository.MockUserRepository), new(mocks.MockPersonalInfoService), new(mocks.MockAccountInfoService), new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		_, err := u.UpdateUser(context.Background(), &api.UpdateUserRequest{
+			User:       &api.User{Id: userId},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"account_info.password"}},
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("missing mask", func(t *testing.T) {
+		u := services.NewUserService(new(repository.MockUserRepository), new(mocks.MockPersonalInfoService), new(mocks.MockAccountInfoService), new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		_, err := u.UpdateUser(context.Background(), &api.UpdateUserRequest{User: &api.User{Id: userId}})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestUserService_DeleteUser(t *testing.T) {
+	userId := utils.CreateValidUser().Id.Hex()
+
+	t.Run("success marks the user as deleted and revokes the sessions", func(t *testing.T) {
+		mockUserRepo := new(repository.MockUserRepository)
+		mockSessionService := new(mocks.MockSessionService)
+		mockTxRunner := new(configMocks.MockTransactionRunner)
+		mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+		mockUserRepo.On("SoftDeleteUser", mock.Anything, userId, mock.AnythingOfType("time.Time")).Return(nil)
+		mockSessionService.On("RevokeAllUserSessions", mock.Anything, userId, "usuário removido").Return(2, nil)
+
+		u := services.NewUserService(mockUserRepo, new(mocks.MockPersonalInfoService), new(mocks.MockAccountInfoService), mockTxRunner, mockSessionService, new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		response, err := u.DeleteUser(context.Background(), &api.DeleteUserRequest{Id: userId})
+
+		assert.NoError(t, err)
+		assert.Equal(t, userId, response.User.Id)
+		mockUserRepo.AssertExpectations(t)
+		mockSessionService.AssertExpectations(t)
+	})
+
+	t.Run("unknown id", func(t *testing.T) {
+		mockUserRepo := new(repository.MockUserRepository)
+		mockSessionService := new(mocks.MockSessionService)
+		mockTxRunner := new(configMocks.MockTransactionRunner)
+		mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+		mockUserRepo.On("SoftDeleteUser", mock.Anything, userId, mock.AnythingOfType("time.Time")).Return(status.Errorf(codes.NotFound, "Usuário não encontrado"))
+
+		u := services.NewUserService(mockUserRepo, new(mocks.MockPersonalInfoService), new(mocks.MockAccountInfoService), mockTxRunner, mockSessionService, new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		_, err := u.DeleteUser(context.Background(), &api.DeleteUserRequest{Id: userId})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		mockSessionService.AssertNotCalled(t, "RevokeAllUserSessions", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserService_RestoreUser(t *testing.T) {
+	validUser := utils.CreateValidUser()
+	userId := validUser.Id.Hex()
+
+	mockUserRepo := new(repository.MockUserRepository)
+	mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+	mockAccountInfoService := new(mocks.MockAccountInfoService)
+
+	mockUserRepo.On("RestoreUser", mock.Anything, userId).Return(nil)
+	mockUserRepo.On("GetUser", mock.Anything, userId).Return(validUser, nil)
+	mockPersonalInfoService.On("GetPersonalInfo", mock.Anything, &api.GetPersonalInfoRequest{UserId: userId}).Return(validUser.PersonalInfo.ToProto(), nil)
+	mockAccountInfoService.On("GetAccountInfo", mock.Anything, &api.GetAccountInfoRequest{UserId: userId}).Return(validUser.AccountInfo.ToProto(), nil)
+
+	u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+	response, err := u.RestoreUser(context.Background(), &api.RestoreUserRequest{Id: userId})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Usuário restaurado com sucesso", response.Message)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestUserService_PurgeDeletedUsers(t *testing.T) {
+	deletedBefore := time.Now().Add(-30 * 24 * time.Hour)
+	purgedId := primitive.NewObjectID().Hex()
+	failingId := primitive.NewObjectID().Hex()
+
+	mockUserRepo := new(repository.MockUserRepository)
+	mockPersonalInfoService := new(mocks.MockPersonalInfoService)
+	mockAccountInfoService := new(mocks.MockAccountInfoService)
+	mockTxRunner := new(configMocks.MockTransactionRunner)
+
+	mockTxRunner.On("WithTransaction", mock.Anything).Return(nil)
+	mockUserRepo.On("FindDeletedUserIds", mock.Anything, deletedBefore).Return([]string{purgedId, failingId}, nil)
+
+	mockMFARepo := new(repository.MockMFARepository)
+	mockPasswordResetRepo := new(repository.MockPasswordResetRepository)
+
+	mockUserRepo.On("PurgeUser", mock.Anything, purgedId, deletedBefore).Return(nil)
+	mockPersonalInfoService.On("DeletePersonalInfo", mock.Anything, &api.DeletePersonalInfoRequest{UserId: purgedId}).Return(nil)
+	mockAccountInfoService.On("DeleteAccountInfo", mock.Anything, &api.DeleteAccountInfoRequest{UserId: purgedId}).Return(status.Errorf(codes.NotFound, "AccountInfo não encontrado"))
+	mockMFARepo.On("DeleteMFA", mock.Anything, purgedId).Return(nil)
+	mockPasswordResetRepo.On("DeleteUserPasswordResets", mock.Anything, purgedId).Return(nil)
+
+	mockUserRepo.On("PurgeUser", mock.Anything, failingId, deletedBefore).Return(nil)
+	mockPersonalInfoService.On("DeletePersonalInfo", mock.Anything, &api.DeletePersonalInfoRequest{UserId: failingId}).Return(status.Errorf(codes.Internal, "falha"))
+
+	u := services.NewUserService(mockUserRepo, mockPersonalInfoService, mockAccountInfoService, mockTxRunner, new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), mockMFARepo, mockPasswordResetRepo)
+	purged, err := u.PurgeDeletedUsers(context.Background(), deletedBefore)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	mockUserRepo.AssertExpectations(t)
+	mockPersonalInfoService.AssertExpectations(t)
+	mockMFARepo.AssertExpectations(t)
+	mockPasswordResetRepo.AssertExpectations(t)
+	mockMFARepo.AssertNotCalled(t, "DeleteMFA", mock.Anything, failingId)
+	mockAccountInfoService.AssertNotCalled(t, "DeleteAccountInfo", mock.Anything, &api.DeleteAccountInfoRequest{UserId: failingId})
+}
+
+func TestUserService_ListUsers(t *testing.T) {
+	first := utils.CreateValidUser()
+	second := utils.CreateValidUser()
+	second.PersonalInfo.Email = "mary.doe@example.com"
+	third := utils.CreateValidUser()
+	third.PersonalInfo.Email = "zoe.doe@example.com"
+
+	t.Run("pages through results with the next page token", func(t *testing.T) {
+		mockUserRepo := new(repository.MockUserRepository)
+		mockUserRepo.On("ListUsers", mock.Anything, mock.MatchedBy(func(query repositories.ListUsersQuery) bool {
+			return query.After == nil && query.Limit == 3 && query.SortField == repositories.UserSortByEmail &&
+				query.EmailDomain == "example.com" && len(query.AccountStatuses) == 1 && query.AccountStatuses[0] == model.AccountStatus_SUSPENDED
+		})).Return([]*model.User{first, second, third}, nil)
+		mockUserRepo.On("ListUsers", mock.Anything, mock.MatchedBy(func(query repositories.ListUsersQuery) bool {
+			return query.After != nil && query.After.LastId == second.Id && query.After.SortValue == second.PersonalInfo.Email
+		})).Return([]*model.User{third}, nil)
+
+		u := services.NewUserService(mockUserRepo, new(mocks.MockPersonalInfoService), new(mocks.MockAccountInfoService), new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		req := &api.ListUsersRequest{
+			PageSize:        2,
+			SortBy:          api.UserSortField_SORT_BY_EMAIL,
+			EmailDomain:     "@example.com",
+			AccountStatuses: []api.AccountStatus{api.AccountStatus_SUSPENDED},
+		}
+
+		page, err := u.ListUsers(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Len(t, page.Users, 2)
+		assert.NotEmpty(t, page.NextPageToken)
+		for _, user := range page.Users {
+			assert.Empty(t, user.AccountInfo.Password)
+		}
+
+		req.PageToken = page.NextPageToken
+		page, err = u.ListUsers(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Len(t, page.Users, 1)
+		assert.Empty(t, page.NextPageToken)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("token from another sort order", func(t *testing.T) {
+		mockUserRepo := new(repository.MockUserRepository)
+		mockUserRepo.On("ListUsers", mock.Anything, mock.Anything).Return([]*model.User{first, second}, nil).Once()
+
+		u := services.NewUserService(mockUserRepo, new(mocks.MockPersonalInfoService), new(mocks.MockAccountInfoService), new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+		page, err := u.ListUsers(context.Background(), &api.ListUsersRequest{PageSize: 1})
+		assert.NoError(t, err)
+
+		_, err = u.ListUsers(context.Background(), &api.ListUsersRequest{PageSize: 1, PageToken: page.NextPageToken, SortBy: api.UserSortField_SORT_BY_LAST_NAME})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		u := services.NewUserService(new(repository.MockUserRepository), new(mocks.MockPersonalInfoService), new(mocks.MockAccountInfoService), new(configMocks.MockTransactionRunner), new(mocks.MockSessionService), new(mocks.MockMFAService), new(mocks.MockEmailVerificationService), new(repository.MockMFARepository), new(repository.MockPasswordResetRepository))
+
+		_, err := u.ListUsers(context.Background(), &api.ListUsersRequest{PageSize: 1000})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = u.ListUsers(context.Background(), &api.ListUsersRequest{PageToken: "não-é-um-token"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		now := time.Now()
+		_, err = u.ListUsers(context.Background(), &api.ListUsersRequest{CreatedAfter: timestamppb.New(now), CreatedBefore: timestamppb.New(now.Add(-time.Hour))})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
 	})
 }
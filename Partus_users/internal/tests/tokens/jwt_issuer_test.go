@@ -0,0 +1,120 @@
+package tokens
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jonh-dev/partus_users/internal/config"
+	"github.com/jonh-dev/partus_users/internal/tokens"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJWTIssuer_IssueAndVerify(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		key  interface{}
+		alg  string
+		kty  string
+	}{
+		{name: "EdDSA", key: edKey, alg: "EdDSA", kty: "OKP"},
+		{name: "RS256", key: rsaKey, alg: "RS256", kty: "RSA"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			issuer := newIssuerFromFile(t, tc.key)
+
+			token, expiresAt, err := issuer.IssueAccessToken("user-id", "session-id", nil, time.Now())
+			assert.NoError(t, err)
+			assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, time.Second)
+
+			claims, err := issuer.VerifyAccessToken(token)
+			assert.NoError(t, err)
+			assert.Equal(t, "user-id", claims.Subject)
+			assert.Equal(t, "session-id", claims.SessionId)
+			assert.Equal(t, "partus_users", claims.Issuer)
+
+			keys := issuer.PublicKeys()
+			assert.Len(t, keys, 1)
+			assert.Equal(t, tc.alg, keys[0].Alg)
+			assert.Equal(t, tc.kty, keys[0].Kty)
+			assert.NotEmpty(t, keys[0].Kid)
+		})
+	}
+}
+
+func TestJWTIssuer_RejectsInvalidTokens(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	issuer, err := tokens.NewJWTIssuer(key, "partus_users", 15*time.Minute)
+	assert.NoError(t, err)
+	otherIssuer, err := tokens.NewJWTIssuer(otherKey, "partus_users", 15*time.Minute)
+	assert.NoError(t, err)
+
+	t.Run("signed with another key", func(t *testing.T) {
+		token, _, err := otherIssuer.IssueAccessToken("user-id", "session-id", nil, time.Now())
+		assert.NoError(t, err)
+
+		_, err = issuer.VerifyAccessToken(token)
+		assert.ErrorIs(t, err, tokens.ErrInvalidAccessToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token, _, err := issuer.IssueAccessToken("user-id", "session-id", nil, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+
+		_, err = issuer.VerifyAccessToken(token)
+		assert.ErrorIs(t, err, tokens.ErrInvalidAccessToken)
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		token, _, err := issuer.IssueAccessToken("user-id", "session-id", nil, time.Now())
+		assert.NoError(t, err)
+
+		_, err = issuer.VerifyAccessToken(token[:len(token)-2] + "AA")
+		assert.ErrorIs(t, err, tokens.ErrInvalidAccessToken)
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	first, err := tokens.NewRefreshToken()
+	assert.NoError(t, err)
+	second, err := tokens.NewRefreshToken()
+	assert.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, tokens.HashRefreshToken(first), tokens.HashRefreshToken(first))
+	assert.NotEqual(t, first, tokens.HashRefreshToken(first))
+}
+
+func newIssuerFromFile(t *testing.T, key interface{}) *tokens.JWTIssuer {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwt_private_key.pem")
+	assert.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600))
+
+	issuer, err := tokens.LoadJWTIssuer(&config.TokenPolicy{
+		PrivateKeyFile:  path,
+		Issuer:          "partus_users",
+		AccessTokenTTL:  15 * time.Minute,
+		RefreshTokenTTL: 24 * time.Hour,
+	})
+	assert.NoError(t, err)
+	return issuer
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"strings"
+	"time"
 
 	"github.com/jonh-dev/partus_users/api"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -115,3 +116,32 @@ func CreateInvalidStatusReasonInfo() *api.AccountInfo {
 	accountInfo.StatusReason = ""
 	return accountInfo
 }
+
+func CreateStoredAccountInfo() *api.AccountInfo {
+	accountInfo := CreateValidAccountInfo()
+	accountInfo.UserId = "64b7f0c2e4b0a1a2b3c4d5e6"
+	accountInfo.Password = "$2a$10$hashedpassword"
+	return accountInfo
+}
+
+func CreateStoredAccountInfoWithStatus(accountStatus api.AccountStatus) *api.AccountInfo {
+	accountInfo := CreateStoredAccountInfo()
+	accountInfo.AccountStatus = accountStatus
+	accountInfo.StatusReason = "motivo do status"
+	return accountInfo
+}
+
+func CreateSuspendedAccountInfo(suspendedUntil time.Time) *api.AccountInfo {
+	accountInfo := CreateStoredAccountInfoWithStatus(api.AccountStatus_SUSPENDED)
+	accountInfo.SuspendedUntil = timestamppb.New(suspendedUntil)
+	return accountInfo
+}
+
+func CreateFailedLoginAccountInfo(failedLoginAttempts int32, lastFailedLogin time.Time, accountLockedUntil time.Time) *api.AccountInfo {
+	accountInfo := CreateStoredAccountInfo()
+	accountInfo.FailedLoginAttempts = failedLoginAttempts
+	accountInfo.LastFailedLogin = timestamppb.New(lastFailedLogin)
+	accountInfo.LastFailedLoginReason = "senha inválida"
+	accountInfo.AccountLockedUntil = timestamppb.New(accountLockedUntil)
+	return accountInfo
+}
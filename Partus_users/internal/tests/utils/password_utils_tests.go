@@ -0,0 +1,9 @@
+package utils
+
+import "github.com/jonh-dev/partus_users/internal/passwordpolicy"
+
+// CreatePasswordChecker recusa apenas as senhas da lista embutida, sem exigir força mínima, para
+// que os testes dos serviços não dependam do estimador.
+func CreatePasswordChecker() *passwordpolicy.Checker {
+	return passwordpolicy.NewChecker(nil, passwordpolicy.DefaultCommonPasswords(), 0)
+}
@@ -0,0 +1,65 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const emailVerificationAudience = "email_verification"
+
+var ErrInvalidVerificationToken = errors.New("token de verificação inválido")
+
+// EmailVerificationClaims inclui o e-mail para que o token deixe de valer se o e-mail mudar antes
+// da verificação.
+type EmailVerificationClaims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// EmailVerificationSigner assina os tokens de verificação com HMAC-SHA256 e uma chave própria, para
+// que eles nunca sejam aceitos como tokens de acesso.
+type EmailVerificationSigner struct {
+	key []byte
+	ttl time.Duration
+}
+
+func NewEmailVerificationSigner(key []byte, ttl time.Duration) *EmailVerificationSigner {
+	return &EmailVerificationSigner{key: key, ttl: ttl}
+}
+
+func (s *EmailVerificationSigner) Sign(userId string, email string, now time.Time) (string, time.Time, error) {
+	expiresAt := now.Add(s.ttl)
+	claims := EmailVerificationClaims{
+		Email: email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userId,
+			Audience:  jwt.ClaimStrings{emailVerificationAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("falha ao assinar o token de verificação: %w", err)
+	}
+	return signed, expiresAt, nil
+}
+
+func (s *EmailVerificationSigner) Verify(token string) (*EmailVerificationClaims, error) {
+	claims := &EmailVerificationClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return s.key, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(emailVerificationAudience), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidVerificationToken, err)
+	}
+
+	if claims.Subject == "" || claims.Email == "" {
+		return nil, ErrInvalidVerificationToken
+	}
+	return claims, nil
+}
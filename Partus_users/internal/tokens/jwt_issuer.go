@@ -0,0 +1,188 @@
+package tokens
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jonh-dev/partus_users/internal/config"
+)
+
+var ErrInvalidAccessToken = errors.New("token de acesso inválido")
+
+type AccessTokenClaims struct {
+	SessionId string `json:"sid"`
+	// Roles são os papéis administrativos do usuário no momento da emissão do token.
+	Roles []string `json:"roles,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// HasAnyRole informa se o token tem algum dos papéis.
+func (c *AccessTokenClaims) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		for _, granted := range c.Roles {
+			if granted == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PublicKey é uma chave pública no formato JWK (RFC 7517). Apenas os campos do tipo da chave são
+// preenchidos: N e E para RSA, Crv e X para Ed25519.
+type PublicKey struct {
+	Kty string
+	Kid string
+	Use string
+	Alg string
+	N   string
+	E   string
+	Crv string
+	X   string
+}
+
+type Issuer interface {
+	IssueAccessToken(userId string, sessionId string, roles []string, now time.Time) (string, time.Time, error)
+	VerifyAccessToken(token string) (*AccessTokenClaims, error)
+	PublicKeys() []PublicKey
+}
+
+type JWTIssuer struct {
+	privateKey crypto.Signer
+	publicKey  crypto.PublicKey
+	method     jwt.SigningMethod
+	keyId      string
+	issuer     string
+	ttl        time.Duration
+}
+
+// NewJWTIssuer assina com RS256 para chaves RSA e EdDSA para chaves Ed25519.
+func NewJWTIssuer(privateKey crypto.Signer, issuer string, ttl time.Duration) (*JWTIssuer, error) {
+	var method jwt.SigningMethod
+	switch privateKey.(type) {
+	case *rsa.PrivateKey:
+		method = jwt.SigningMethodRS256
+	case ed25519.PrivateKey:
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("tipo de chave não suportado: %T", privateKey)
+	}
+
+	publicKey := privateKey.Public()
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao serializar a chave pública: %w", err)
+	}
+	digest := sha256.Sum256(der)
+
+	return &JWTIssuer{
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		method:     method,
+		keyId:      base64.RawURLEncoding.EncodeToString(digest[:])[:16],
+		issuer:     issuer,
+		ttl:        ttl,
+	}, nil
+}
+
+// LoadJWTIssuer lê a chave privada PEM (PKCS#8 ou PKCS#1) indicada em policy.PrivateKeyFile.
+func LoadJWTIssuer(policy *config.TokenPolicy) (*JWTIssuer, error) {
+	data, err := os.ReadFile(policy.PrivateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao ler a chave privada do JWT: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("a chave privada do JWT não está no formato PEM")
+	}
+
+	var privateKey interface{}
+	privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao decodificar a chave privada do JWT: %w", err)
+		}
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("tipo de chave não suportado: %T", privateKey)
+	}
+
+	return NewJWTIssuer(signer, policy.Issuer, policy.AccessTokenTTL)
+}
+
+func (i *JWTIssuer) IssueAccessToken(userId string, sessionId string, roles []string, now time.Time) (string, time.Time, error) {
+	tokenId, err := randomString(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := now.Add(i.ttl)
+	claims := AccessTokenClaims{
+		SessionId: sessionId,
+		Roles:     roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    i.issuer,
+			Subject:   userId,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			ID:        tokenId,
+		},
+	}
+
+	token := jwt.NewWithClaims(i.method, claims)
+	token.Header["kid"] = i.keyId
+
+	signed, err := token.SignedString(i.privateKey)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("falha ao assinar o token de acesso: %w", err)
+	}
+	return signed, expiresAt, nil
+}
+
+func (i *JWTIssuer) VerifyAccessToken(tokenString string) (*AccessTokenClaims, error) {
+	claims := &AccessTokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if kid, ok := token.Header["kid"].(string); ok && kid != i.keyId {
+			return nil, fmt.Errorf("kid desconhecido: %s", kid)
+		}
+		return i.publicKey, nil
+	}, jwt.WithValidMethods([]string{i.method.Alg()}), jwt.WithIssuer(i.issuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAccessToken, err)
+	}
+
+	return claims, nil
+}
+
+func (i *JWTIssuer) PublicKeys() []PublicKey {
+	key := PublicKey{Kid: i.keyId, Use: "sig", Alg: i.method.Alg()}
+
+	switch publicKey := i.publicKey.(type) {
+	case *rsa.PublicKey:
+		key.Kty = "RSA"
+		key.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		key.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case ed25519.PublicKey:
+		key.Kty = "OKP"
+		key.Crv = "Ed25519"
+		key.X = base64.RawURLEncoding.EncodeToString(publicKey)
+	}
+
+	return []PublicKey{key}
+}
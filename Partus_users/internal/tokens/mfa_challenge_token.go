@@ -0,0 +1,13 @@
+package tokens
+
+const mfaChallengeTokenBytes = 32
+
+// NewMFAChallengeToken gera o token opaco que liga a primeira etapa do login (senha) à segunda
+// (código MFA). Assim como no refresh token, apenas o hash é gravado no banco.
+func NewMFAChallengeToken() (string, error) {
+	return randomString(mfaChallengeTokenBytes)
+}
+
+func HashMFAChallengeToken(token string) string {
+	return HashRefreshToken(token)
+}
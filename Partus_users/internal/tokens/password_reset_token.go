@@ -0,0 +1,13 @@
+package tokens
+
+const passwordResetTokenBytes = 32
+
+// NewPasswordResetToken gera o token enviado por e-mail para redefinir a senha. Apenas o hash é
+// gravado no banco.
+func NewPasswordResetToken() (string, error) {
+	return randomString(passwordResetTokenBytes)
+}
+
+func HashPasswordResetToken(token string) string {
+	return HashRefreshToken(token)
+}
@@ -0,0 +1,29 @@
+package tokens
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+const refreshTokenBytes = 32
+
+// NewRefreshToken gera um refresh token opaco. Apenas o hash dele é gravado no banco.
+func NewRefreshToken() (string, error) {
+	return randomString(refreshTokenBytes)
+}
+
+func HashRefreshToken(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+func randomString(size int) (string, error) {
+	buffer := make([]byte, size)
+	if _, err := rand.Read(buffer); err != nil {
+		return "", fmt.Errorf("falha ao gerar valor aleatório: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
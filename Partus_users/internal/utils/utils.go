@@ -36,17 +36,37 @@ func IsValidAreaCode(areaCode string) bool {
 	return ok
 }
 
+// DefaultTimeZone é o fuso usado para exibir datas a usuários sem fuso horário cadastrado.
+const DefaultTimeZone = "America/Sao_Paulo"
+
 func GetCurrentTimestamp() *timestamppb.Timestamp {
-	location, _ := time.LoadLocation("America/Sao_Paulo")
-	now := time.Now().In(location)
-	return timestamppb.New(now)
+	return timestamppb.New(time.Now().UTC())
+}
+
+// LoadTimeZone retorna o fuso IANA informado, ou DefaultTimeZone se ele estiver vazio ou não
+// existir. Sem a base de fusos no sistema, usa UTC.
+func LoadTimeZone(timeZone string) *time.Location {
+	if timeZone != "" {
+		if location, err := time.LoadLocation(timeZone); err == nil {
+			return location
+		}
+	}
+
+	location, err := time.LoadLocation(DefaultTimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
 }
 
-func AdjustToSaoPaulo(t *timestamppb.Timestamp) *timestamppb.Timestamp {
-	adjustedTime := t.AsTime().Add(-3 * time.Hour)
-	return timestamppb.New(adjustedTime)
+// FormatInTimeZone formata t para exibição no fuso horário do usuário.
+func FormatInTimeZone(t time.Time, timeZone string) string {
+	return t.In(LoadTimeZone(timeZone)).Format("02/01/2006 15:04 MST")
 }
 
-func ReadjustToSaoPaulo(t time.Time) time.Time {
-	return t.Add(3 * time.Hour)
+func TimestampToTime(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
 }
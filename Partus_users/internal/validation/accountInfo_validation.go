@@ -3,6 +3,7 @@ package validation
 import (
 	"errors"
 	"regexp"
+	"strings"
 	"time"
 
 	"github.com/jonh-dev/partus_users/api"
@@ -18,36 +19,82 @@ var (
 	ErrLastFailedLoginInFuture     = errors.New("a última tentativa de login falhada não pode estar no futuro")
 	ErrFailedLoginAttemptsNegative = errors.New("o número de tentativas de login falhadas não pode ser negativo")
 	ErrLastFailedLoginReasonEmpty  = errors.New("a razão da última tentativa de login falhada não pode estar vazia se houve uma tentativa de login falhada")
+	ErrTransitionReasonEmpty       = errors.New("a razão da mudança de status é obrigatória")
+	ErrTransitionActorEmpty        = errors.New("o responsável pela mudança de status é obrigatório")
+	ErrInvalidSuspensionEnd        = errors.New("o fim da suspensão só pode ser informado para SUSPENDED e deve estar no futuro")
 )
 
+// ValidateAccountInfo retorna um *ValidationError com todas as violações encontradas.
 func ValidateAccountInfo(accountInfo *api.AccountInfo, operation OperationType, originalAccountInfo *api.AccountInfo) error {
+	validationError := &ValidationError{}
+
 	if !isValidUsername(accountInfo.Username) {
-		return ErrInvalidUsername
+		validationError.add("username", ReasonInvalidUsername, ErrInvalidUsername)
 	}
 
 	if !isValidPassword(accountInfo.Password) {
-		return ErrInvalidPassword
+		validationError.add("password", ReasonInvalidPassword, ErrInvalidPassword)
 	}
 
 	if !isValidAccountStatus(accountInfo.AccountStatus) {
-		return ErrInvalidAccountStatus
-	}
-
-	if !isValidStatusReason(accountInfo.AccountStatus, accountInfo.StatusReason) {
-		return ErrInvalidStatusReason
+		validationError.add("accountStatus", ReasonInvalidStatus, ErrInvalidAccountStatus)
+	} else if !isValidStatusReason(accountInfo.AccountStatus, accountInfo.StatusReason) {
+		validationError.add("statusReason", ReasonMissingStatusReason, ErrInvalidStatusReason)
 	}
 
 	if operation == Update {
 		if !isCreatedAtUnchanged(originalAccountInfo.CreatedAt, accountInfo.CreatedAt) {
-			return ErrCreatedAtCannotBeUpdated
+			validationError.add("createdAt", ReasonImmutableField, ErrCreatedAtCannotBeUpdated)
 		}
 	}
 
 	// Adicione aqui as outras validações do AccountInfo
 
+	return validationError.errOrNil()
+}
+
+// ValidateUsername aplica as regras de username do AccountInfo a um username isolado. field é o
+// caminho do campo na requisição.
+func ValidateUsername(field string, username string) error {
+	if !isValidUsername(username) {
+		return NewFieldError(field, ReasonInvalidUsername, ErrInvalidUsername)
+	}
 	return nil
 }
 
+// ValidatePassword aplica as regras de senha do AccountInfo a uma senha isolada, como na
+// redefinição de senha. field é o caminho do campo na requisição.
+func ValidatePassword(field string, password string) error {
+	if !isValidPassword(password) {
+		return NewFieldError(field, ReasonInvalidPassword, ErrInvalidPassword)
+	}
+	return nil
+}
+
+// ValidateStatusChange valida os campos de uma mudança de status. actor é o responsável já definido
+// pelo serviço. As transições permitidas são verificadas pelo serviço, que conhece o status atual.
+func ValidateStatusChange(req *api.ChangeAccountStatusRequest, actor string, now time.Time) error {
+	validationError := &ValidationError{}
+
+	if !isValidAccountStatus(req.AccountStatus) {
+		validationError.add("accountStatus", ReasonInvalidStatus, ErrInvalidAccountStatus)
+	}
+
+	if strings.TrimSpace(req.Reason) == "" {
+		validationError.add("reason", ReasonMissingTransitionReason, ErrTransitionReasonEmpty)
+	}
+
+	if strings.TrimSpace(actor) == "" {
+		validationError.add("actor", ReasonMissingActor, ErrTransitionActorEmpty)
+	}
+
+	if req.SuspendedUntil != nil && (req.AccountStatus != api.AccountStatus_SUSPENDED || !req.SuspendedUntil.AsTime().After(now)) {
+		validationError.add("suspendedUntil", ReasonInvalidSuspensionEnd, ErrInvalidSuspensionEnd)
+	}
+
+	return validationError.errOrNil()
+}
+
 func isValidUsername(username string) bool {
 	re := regexp.MustCompile(`^(?i)[a-z0-9]+([._-]?[a-z0-9]+)*$`)
 	return len(username) >= 3 && len(username) <= 20 && re.MatchString(username)
@@ -84,23 +131,28 @@ func isValidStatusReason(accountStatus api.AccountStatus, statusReason string) b
 }
 
 func isCreatedAtUnchanged(originalCreatedAt *timestamppb.Timestamp, updatedCreatedAt *timestamppb.Timestamp) bool {
+	if updatedCreatedAt == nil {
+		return true
+	}
 	return originalCreatedAt.AsTime().Equal(updatedCreatedAt.AsTime())
 }
 
 func ValidateLoginFields(accountInfo *api.AccountInfo) error {
+	validationError := &ValidationError{}
+
 	if !isLastFailedLoginNotInFuture(accountInfo.LastFailedLogin) {
-		return ErrLastFailedLoginInFuture
+		validationError.add("lastFailedLogin", ReasonFutureTimestamp, ErrLastFailedLoginInFuture)
 	}
 
 	if accountInfo.FailedLoginAttempts < 0 {
-		return ErrFailedLoginAttemptsNegative
+		validationError.add("failedLoginAttempts", ReasonNegativeValue, ErrFailedLoginAttemptsNegative)
 	}
 
 	if accountInfo.FailedLoginAttempts > 0 && accountInfo.LastFailedLoginReason == "" {
-		return ErrLastFailedLoginReasonEmpty
+		validationError.add("lastFailedLoginReason", ReasonMissingFailedReason, ErrLastFailedLoginReasonEmpty)
 	}
 
-	return nil
+	return validationError.errOrNil()
 }
 
 func isLastFailedLoginNotInFuture(lastFailedLogin *timestamppb.Timestamp) bool {
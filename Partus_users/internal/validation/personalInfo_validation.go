@@ -2,6 +2,7 @@ package validation
 
 import (
 	"errors"
+	"fmt"
 	"net/url"
 	"regexp"
 	"time"
@@ -18,38 +19,92 @@ var (
 	ErrInvalidBirthDate    = errors.New("a data de nascimento deve estar no passado, o usuário deve ter pelo menos 13 anos e o ano deve ser entre 1900 e o ano atual")
 	ErrInvalidPhone        = errors.New("o telefone ou celular deve estar no formato correto, ou seja, começar com '+' seguido de 1 a 3 dígitos para números internacionais, ou começar diretamente com um dígito para números brasileiros, e ter entre 9 e 14 dígitos no total, sem conter nenhum caractere que não seja dígito ou '+'")
 	ErrInvalidProfileImage = errors.New("a imagem do perfil deve ser um URL válido")
+	ErrInvalidTimeZone     = errors.New("o fuso horário deve ser um identificador IANA válido, como America/Sao_Paulo")
 )
 
+// ValidatePersonalInfo retorna um *ValidationError com todas as violações encontradas.
 func ValidatePersonalInfo(personalInfo *api.PersonalInfo, operation OperationType) error {
+	validationError := &ValidationError{}
+
 	if personalInfo.FirstName != "" && !isValidFirstName(personalInfo.FirstName) {
-		return ErrInvalidFirstName
+		validationError.add("firstName", ReasonInvalidFirstName, ErrInvalidFirstName)
 	}
 
 	if personalInfo.LastName != "" && !isValidLastName(personalInfo.LastName) {
-		return ErrInvalidLastName
+		validationError.add("lastName", ReasonInvalidLastName, ErrInvalidLastName)
 	}
 
 	if personalInfo.Email != "" && !isValidEmail(personalInfo.Email) {
-		return ErrInvalidUserEmail
+		validationError.add("email", ReasonInvalidEmail, ErrInvalidUserEmail)
 	}
 
 	if personalInfo.BirthDate != nil && !isValidBirthDate(personalInfo.BirthDate) {
-		return ErrInvalidBirthDate
+		validationError.add("birthDate", ReasonInvalidBirthDate, ErrInvalidBirthDate)
 	}
 
 	if personalInfo.Phone != "" {
 		if err := isValidPhone(personalInfo.Phone); err != nil {
-			return err
+			validationError.add("phone", ReasonInvalidPhone, err)
 		}
 	}
 
 	if operation == UpdateProfile && personalInfo.ProfileImage != "" {
 		if !isValidProfileImage(personalInfo.ProfileImage) {
-			return ErrInvalidProfileImage
+			validationError.add("profileImage", ReasonInvalidProfileImage, ErrInvalidProfileImage)
 		}
 	}
 
-	return nil
+	if personalInfo.TimeZone != "" && !isValidTimeZone(personalInfo.TimeZone) {
+		validationError.add("timeZone", ReasonInvalidTimeZone, ErrInvalidTimeZone)
+	}
+
+	return validationError.errOrNil()
+}
+
+// ValidatePersonalInfoFields valida apenas os campos presentes na máscara de atualização.
+// Telefone, data de nascimento, imagem do perfil e fuso horário podem ser enviados vazios para
+// removê-los.
+func ValidatePersonalInfoFields(personalInfo *api.PersonalInfo, fields []string) error {
+	validationError := &ValidationError{}
+
+	for _, field := range fields {
+		switch field {
+		case "firstName":
+			if !isValidFirstName(personalInfo.FirstName) {
+				validationError.add(field, ReasonInvalidFirstName, ErrInvalidFirstName)
+			}
+		case "lastName":
+			if !isValidLastName(personalInfo.LastName) {
+				validationError.add(field, ReasonInvalidLastName, ErrInvalidLastName)
+			}
+		case "email":
+			if !isValidEmail(personalInfo.Email) {
+				validationError.add(field, ReasonInvalidEmail, ErrInvalidUserEmail)
+			}
+		case "birthDate":
+			if personalInfo.BirthDate != nil && !isValidBirthDate(personalInfo.BirthDate) {
+				validationError.add(field, ReasonInvalidBirthDate, ErrInvalidBirthDate)
+			}
+		case "phone":
+			if personalInfo.Phone != "" {
+				if err := isValidPhone(personalInfo.Phone); err != nil {
+					validationError.add(field, ReasonInvalidPhone, err)
+				}
+			}
+		case "profileImage":
+			if personalInfo.ProfileImage != "" && !isValidProfileImage(personalInfo.ProfileImage) {
+				validationError.add(field, ReasonInvalidProfileImage, ErrInvalidProfileImage)
+			}
+		case "timeZone":
+			if personalInfo.TimeZone != "" && !isValidTimeZone(personalInfo.TimeZone) {
+				validationError.add(field, ReasonInvalidTimeZone, ErrInvalidTimeZone)
+			}
+		default:
+			validationError.add(field, ReasonFieldNotUpdatable, fmt.Errorf("o campo %s não pode ser atualizado", field))
+		}
+	}
+
+	return validationError.errOrNil()
 }
 
 func isValidFirstName(name string) bool {
@@ -118,3 +173,12 @@ func isValidProfileImage(profileImage string) bool {
 	_, err := url.ParseRequestURI(profileImage)
 	return err == nil
 }
+
+// isValidTimeZone aceita apenas nomes da base IANA. "Local" é recusado porque depende do servidor.
+func isValidTimeZone(timeZone string) bool {
+	if timeZone == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(timeZone)
+	return err == nil
+}
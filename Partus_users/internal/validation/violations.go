@@ -0,0 +1,165 @@
+package validation
+
+import (
+	"errors"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ErrorDomain identifica a origem dos ErrorInfo anexados aos erros de validação.
+const ErrorDomain = "partus_users"
+
+// Códigos de motivo estáveis, para que o cliente trate cada violação sem depender do texto.
+const (
+	ReasonInvalidUsername     = "INVALID_USERNAME"
+	ReasonInvalidPassword     = "INVALID_PASSWORD"
+	ReasonInvalidStatus       = "INVALID_ACCOUNT_STATUS"
+	ReasonMissingStatusReason = "MISSING_STATUS_REASON"
+	ReasonImmutableField      = "IMMUTABLE_FIELD"
+	ReasonFutureTimestamp     = "FUTURE_TIMESTAMP"
+	ReasonNegativeValue       = "NEGATIVE_VALUE"
+	ReasonMissingFailedReason = "MISSING_FAILED_LOGIN_REASON"
+	ReasonInvalidFirstName    = "INVALID_FIRST_NAME"
+	ReasonInvalidLastName     = "INVALID_LAST_NAME"
+	ReasonInvalidEmail        = "INVALID_EMAIL"
+	ReasonInvalidBirthDate    = "INVALID_BIRTH_DATE"
+	ReasonInvalidPhone        = "INVALID_PHONE"
+	ReasonInvalidProfileImage = "INVALID_PROFILE_IMAGE"
+	ReasonInvalidTimeZone     = "INVALID_TIME_ZONE"
+	ReasonFieldNotUpdatable   = "FIELD_NOT_UPDATABLE"
+	ReasonPasswordReused      = "PASSWORD_REUSED"
+
+	ReasonMissingTransitionReason = "MISSING_TRANSITION_REASON"
+	ReasonMissingActor            = "MISSING_ACTOR"
+	ReasonInvalidSuspensionEnd    = "INVALID_SUSPENSION_END"
+)
+
+// FieldViolation descreve um campo inválido. Field é o caminho do campo na mensagem validada,
+// com os nomes do proto, e Err é o erro sentinela correspondente, como ErrInvalidPhone.
+type FieldViolation struct {
+	Field  string
+	Reason string
+	Err    error
+}
+
+// ValidationError reúne todas as violações encontradas, em vez de parar na primeira.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		descriptions[i] = violation.Err.Error()
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+// Unwrap permite errors.Is(err, ErrInvalidPhone) com qualquer uma das violações.
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Violations))
+	for i, violation := range e.Violations {
+		errs[i] = violation.Err
+	}
+	return errs
+}
+
+func (e *ValidationError) add(field string, reason string, err error) {
+	e.Violations = append(e.Violations, FieldViolation{Field: field, Reason: reason, Err: err})
+}
+
+// errOrNil evita devolver um *ValidationError vazio dentro de uma interface error não nula.
+func (e *ValidationError) errOrNil() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+// NewFieldError cria um erro de validação de um único campo, para as regras verificadas fora
+// deste pacote, como a política de senhas.
+func NewFieldError(field string, reason string, err error) *ValidationError {
+	validationError := &ValidationError{}
+	validationError.add(field, reason, err)
+	return validationError
+}
+
+// ToStatus converte o erro em InvalidArgument com message como prefixo. Para um ValidationError,
+// anexa um BadRequest com uma FieldViolation por campo e um ErrorInfo por violação, cujo Reason é
+// o código do motivo e cujo Metadata["field"] é o caminho do campo.
+func ToStatus(message string, err error) error {
+	st := status.New(codes.InvalidArgument, message+": "+err.Error())
+
+	var validationError *ValidationError
+	if !errors.As(err, &validationError) {
+		return st.Err()
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	details := []protoadapt.MessageV1{badRequest}
+	for _, violation := range validationError.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Err.Error(),
+		})
+		details = append(details, &errdetails.ErrorInfo{
+			Reason:   violation.Reason,
+			Domain:   ErrorDomain,
+			Metadata: map[string]string{"field": violation.Field},
+		})
+	}
+
+	withDetails, detailsErr := st.WithDetails(details...)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return withDetails.Err()
+}
+
+// PrefixFields reescreve um erro gerado por ToStatus quando a mensagem validada está aninhada em
+// outra, como o AccountInfo dentro de CreateUserRequest: cada campo ganha o prefixo e a mensagem
+// ganha o message. Outros erros são devolvidos sem alteração.
+func PrefixFields(err error, prefix string, message string) error {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		return err
+	}
+
+	prefixed := status.New(codes.InvalidArgument, message+": "+st.Message())
+	details := []protoadapt.MessageV1{}
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.BadRequest:
+			badRequest := &errdetails.BadRequest{}
+			for _, violation := range detail.FieldViolations {
+				badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+					Field:       prefix + "." + violation.Field,
+					Description: violation.Description,
+				})
+			}
+			details = append(details, badRequest)
+		case *errdetails.ErrorInfo:
+			metadata := make(map[string]string, len(detail.Metadata))
+			for key, value := range detail.Metadata {
+				metadata[key] = value
+			}
+			if field, exists := metadata["field"]; exists {
+				metadata["field"] = prefix + "." + field
+			}
+			details = append(details, &errdetails.ErrorInfo{Reason: detail.Reason, Domain: detail.Domain, Metadata: metadata})
+		}
+	}
+	if len(details) == 0 {
+		return prefixed.Err()
+	}
+
+	withDetails, detailsErr := prefixed.WithDetails(details...)
+	if detailsErr != nil {
+		return prefixed.Err()
+	}
+	return withDetails.Err()
+}